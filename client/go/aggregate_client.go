@@ -0,0 +1,148 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// executeAttempts bounds the automatic retries Execute makes when a
+// command loses a concurrency race.
+const executeAttempts = 3
+
+// SequenceSource reports the current head sequence of an aggregate's
+// stream, so a client can stamp outgoing commands with the sequence they
+// expect to follow.  QueryClient.CurrentSequence satisfies it.
+type SequenceSource func(ctx context.Context, domain string, root []byte) (uint32, error)
+
+// AggregateClient sends commands to an aggregate over gRPC, packaging a
+// domain message into the CommandBook envelope the BusinessLogic service
+// expects.  Build one with NewAggregateClient.
+type AggregateClient struct {
+	logic pb.BusinessLogicClient
+	seq   SequenceSource
+}
+
+// NewAggregateClient creates an aggregate client on an established
+// connection.
+func NewAggregateClient(conn *grpc.ClientConn) *AggregateClient {
+	return &AggregateClient{logic: pb.NewBusinessLogicClient(conn)}
+}
+
+// WithSequenceSource registers the lookup Execute uses to stamp each
+// command with the aggregate's current head sequence, and to re-stamp
+// before retrying a command that lost a concurrency race.  Without one,
+// commands go out at sequence 0 and the server assigns placement.
+func (c *AggregateClient) WithSequenceSource(seq SequenceSource) *AggregateClient {
+	c.seq = seq
+	return c
+}
+
+// ExecuteOption configures a single Execute call.
+type ExecuteOption func(*executeConfig)
+
+type executeConfig struct {
+	correlationID string
+	mode          SyncMode
+}
+
+// WithCorrelationID stamps the command's cover with a correlation id, so
+// downstream events, sagas, and notifications can be traced back to the
+// originating request.
+func WithCorrelationID(id string) ExecuteOption {
+	return func(c *executeConfig) { c.correlationID = id }
+}
+
+// WithSyncMode sends the command through the synchronous handler with
+// the given consistency mode: SyncModeSimple waits for the domain's
+// projectors, SyncModeCascade also for downstream sagas.  The mode rides
+// the request's gRPC metadata; the default is SyncModeAsync, which
+// returns as soon as the events are accepted.
+func WithSyncMode(mode SyncMode) ExecuteOption {
+	return func(c *executeConfig) { c.mode = mode }
+}
+
+// Execute packs cmd into a single-page CommandBook addressed to the
+// aggregate identified by domain and root, sends it, and returns the
+// emitted events.  When a sequence source is configured the command is
+// stamped with the stream's current head sequence, and a rejection with
+// codes.Aborted or codes.FailedPrecondition — the signature of losing a
+// race to a concurrent writer — is retried against a freshly queried
+// sequence, up to three attempts.  Other rejections return immediately.
+// Metadata attached to ctx with WithMetadata rides on the command's
+// cover, so tenant and actor context reach the business-logic server.
+func (c *AggregateClient) Execute(ctx context.Context, domain string, root []byte, cmd proto.Message, opts ...ExecuteOption) (*pb.EventBook, error) {
+	var cfg executeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	payload, err := anypb.New(cmd)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "packing command: %v", err)
+	}
+
+	attempts := 1
+	if c.seq != nil {
+		attempts = executeAttempts
+	}
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		var seq uint32
+		if c.seq != nil {
+			seq, err = c.seq(ctx, domain, root)
+			if err != nil {
+				return nil, err
+			}
+		}
+		book := &pb.CommandBook{
+			Cover: &pb.Cover{
+				Domain:        domain,
+				Root:          &pb.UUID{Value: root},
+				CorrelationId: cfg.correlationID,
+				Metadata:      MetadataFromContext(ctx),
+			},
+			Pages: []*pb.CommandPage{{
+				Sequence: seq,
+				Payload:  &pb.CommandPage_Command{Command: payload},
+			}},
+		}
+		events, err := c.send(ctx, &pb.ContextualCommand{Command: book}, cfg.mode)
+		if err == nil {
+			return events, nil
+		}
+		lastErr = err
+		if !retryableExecute(err) {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// send dispatches one attempt through the sync or async handler,
+// stamping non-async modes onto the outgoing metadata.
+func (c *AggregateClient) send(ctx context.Context, cc *pb.ContextualCommand, mode SyncMode) (*pb.EventBook, error) {
+	if mode != SyncModeAsync {
+		resp, err := c.logic.HandleSync(withSyncModeMetadata(ctx, mode), cc)
+		if err != nil {
+			return nil, err
+		}
+		return resp.GetEvents(), nil
+	}
+	return c.logic.Handle(ctx, cc)
+}
+
+// retryableExecute reports whether a rejection indicates a stale sequence
+// worth retrying with a fresh one.
+func retryableExecute(err error) bool {
+	switch status.Code(err) {
+	case codes.Aborted, codes.FailedPrecondition:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// fakeBusinessLogic is a scripted BusinessLogicClient: each Handle call
+// pops the next response or error.
+type fakeBusinessLogic struct {
+	calls     []*pb.ContextualCommand
+	responses []*pb.EventBook
+	errs      []error
+	synced    int
+}
+
+func (f *fakeBusinessLogic) pop(in *pb.ContextualCommand) (*pb.EventBook, error) {
+	f.calls = append(f.calls, in)
+	i := len(f.calls) - 1
+	var err error
+	if i < len(f.errs) {
+		err = f.errs[i]
+	}
+	var resp *pb.EventBook
+	if i < len(f.responses) {
+		resp = f.responses[i]
+	}
+	return resp, err
+}
+
+func (f *fakeBusinessLogic) Handle(_ context.Context, in *pb.ContextualCommand, _ ...grpc.CallOption) (*pb.EventBook, error) {
+	return f.pop(in)
+}
+
+func (f *fakeBusinessLogic) HandleSync(_ context.Context, in *pb.ContextualCommand, _ ...grpc.CallOption) (*pb.BusinessResponse, error) {
+	f.synced++
+	events, err := f.pop(in)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BusinessResponse{Events: events}, nil
+}
+
+func (f *fakeBusinessLogic) Speculate(_ context.Context, in *pb.ContextualCommand, _ ...grpc.CallOption) (*pb.EventBook, error) {
+	return f.pop(in)
+}
+
+func (f *fakeBusinessLogic) HandleStream(_ context.Context, _ ...grpc.CallOption) (pb.BusinessLogic_HandleStreamClient, error) {
+	return nil, status.Error(codes.Unimplemented, "fake does not stream")
+}
+
+func TestAggregateClientExecuteBuildsCommandBook(t *testing.T) {
+	fake := &fakeBusinessLogic{responses: []*pb.EventBook{testBook(t, 0)}}
+	client := &AggregateClient{logic: fake}
+
+	events, err := client.Execute(context.Background(), "player", testRoot().GetValue(),
+		wrapperspb.String("register"), WithCorrelationID("corr-1"))
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(events.GetPages()) != 1 {
+		t.Errorf("got %d event pages, want 1", len(events.GetPages()))
+	}
+	if len(fake.calls) != 1 {
+		t.Fatalf("got %d calls, want 1", len(fake.calls))
+	}
+	book := fake.calls[0].GetCommand()
+	if book.GetCover().GetDomain() != "player" {
+		t.Errorf("cover domain = %q, want %q", book.GetCover().GetDomain(), "player")
+	}
+	if book.GetCover().GetCorrelationId() != "corr-1" {
+		t.Errorf("correlation id = %q, want %q", book.GetCover().GetCorrelationId(), "corr-1")
+	}
+	if got := typeNameOf(book.GetPages()[0].GetCommand().GetTypeUrl()); got != "StringValue" {
+		t.Errorf("command type = %q, want StringValue", got)
+	}
+}
+
+func TestAggregateClientExecuteStampsQueriedSequence(t *testing.T) {
+	fake := &fakeBusinessLogic{responses: []*pb.EventBook{testBook(t, 7)}}
+	client := (&AggregateClient{logic: fake}).WithSequenceSource(
+		func(context.Context, string, []byte) (uint32, error) { return 7, nil })
+
+	if _, err := client.Execute(context.Background(), "player", testRoot().GetValue(),
+		wrapperspb.String("bet")); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := fake.calls[0].GetCommand().GetPages()[0].GetSequence(); got != 7 {
+		t.Errorf("command sequence = %d, want 7", got)
+	}
+}
+
+func TestAggregateClientExecuteRetriesOnLostRace(t *testing.T) {
+	fake := &fakeBusinessLogic{
+		errs:      []error{Aborted("stale sequence").GRPCStatus().Err(), nil},
+		responses: []*pb.EventBook{nil, testBook(t, 1)},
+	}
+	seqs := []uint32{0, 1}
+	var queried int
+	client := (&AggregateClient{logic: fake}).WithSequenceSource(
+		func(context.Context, string, []byte) (uint32, error) {
+			seq := seqs[queried]
+			queried++
+			return seq, nil
+		})
+
+	if _, err := client.Execute(context.Background(), "player", testRoot().GetValue(),
+		wrapperspb.String("bet")); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(fake.calls) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(fake.calls))
+	}
+	if got := fake.calls[1].GetCommand().GetPages()[0].GetSequence(); got != 1 {
+		t.Errorf("retried sequence = %d, want 1 (re-queried)", got)
+	}
+}
+
+func TestAggregateClientExecuteDoesNotRetryRejections(t *testing.T) {
+	fake := &fakeBusinessLogic{errs: []error{FieldError("amount", "must be positive")}}
+	client := (&AggregateClient{logic: fake}).WithSequenceSource(
+		func(context.Context, string, []byte) (uint32, error) { return 0, nil })
+
+	_, err := client.Execute(context.Background(), "player", testRoot().GetValue(),
+		wrapperspb.String("bet"))
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("code = %v, want InvalidArgument", got)
+	}
+	if len(fake.calls) != 1 {
+		t.Errorf("got %d attempts, want 1 (validation failures are final)", len(fake.calls))
+	}
+}
+
+func TestAggregateClientExecuteSyncMode(t *testing.T) {
+	fake := &fakeBusinessLogic{responses: []*pb.EventBook{testBook(t, 0)}}
+	client := &AggregateClient{logic: fake}
+
+	events, err := client.Execute(context.Background(), "player", testRoot().GetValue(),
+		wrapperspb.String("register"), WithSyncMode(SyncModeSimple))
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if fake.synced != 1 {
+		t.Errorf("sync handler called %d times, want 1", fake.synced)
+	}
+	if len(events.GetPages()) != 1 {
+		t.Errorf("got %d event pages, want 1", len(events.GetPages()))
+	}
+}
@@ -0,0 +1,266 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// TestAggregate drives commands through a real CommandRouter in Given/When
+// style, so aggregate tests exercise type matching, rebuild, and sequence
+// logic instead of calling handlers directly:
+//
+//	h := angzarr.NewTestAggregate(t, router)
+//	h.Given(&examples.HandStarted{...})
+//	h.When(&examples.DealCards{...}).ExpectEvent("CardsDealt")
+//
+// Prior events accumulate across Given and successful When calls, so a
+// scripted flow reads top to bottom.
+type TestAggregate struct {
+	tb     testing.TB
+	router *CommandRouter
+	root   []byte
+	prior  *pb.EventBook
+	now    time.Time
+}
+
+// NewTestAggregate creates a harness around the given router, with a fixed
+// default root and a fake clock for deterministic timestamps.
+func NewTestAggregate(tb testing.TB, router *CommandRouter) *TestAggregate {
+	tb.Helper()
+	return &TestAggregate{
+		tb:     tb,
+		router: router,
+		root:   []byte("0123456789abcdef"),
+		now:    time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// WithRoot replaces the default aggregate root identifier.
+func (h *TestAggregate) WithRoot(root []byte) *TestAggregate {
+	h.root = root
+	return h
+}
+
+// Given appends prior events to the aggregate's stream, packing each
+// message and assigning contiguous sequences and fake-clock timestamps.
+func (h *TestAggregate) Given(events ...proto.Message) *TestAggregate {
+	h.tb.Helper()
+	for _, event := range events {
+		packed, err := anypb.New(event)
+		if err != nil {
+			h.tb.Fatalf("packing given event: %v", err)
+		}
+		h.appendPrior(packed)
+	}
+	return h
+}
+
+// When dispatches one command through the router against the accumulated
+// prior events and returns the result for assertions.  On success the
+// emitted events are folded into the prior stream so subsequent When calls
+// see them.
+func (h *TestAggregate) When(cmd proto.Message) *AggregateResult {
+	h.tb.Helper()
+	packed, err := anypb.New(cmd)
+	if err != nil {
+		h.tb.Fatalf("packing command: %v", err)
+	}
+	cc := &pb.ContextualCommand{
+		Command: &pb.CommandBook{
+			Cover: &pb.Cover{Domain: h.router.Domain(), Root: &pb.UUID{Value: h.root}},
+			Pages: []*pb.CommandPage{{Payload: &pb.CommandPage_Command{Command: packed}}},
+		},
+		Events: h.prior,
+	}
+	events, dispatchErr := h.router.Handle(context.Background(), cc)
+	if dispatchErr == nil {
+		for _, page := range events.GetPages() {
+			h.prior = mergeBooks(h.prior, &pb.EventBook{Cover: cc.Command.Cover, Pages: []*pb.EventPage{page}})
+		}
+	}
+	return &AggregateResult{tb: h.tb, events: events, err: dispatchErr}
+}
+
+// WhenRejected dispatches a downstream rejection notification through the
+// router's HandleRejection path, exercising OnRejected registrations the
+// same way When exercises command handlers.  domain and command name the
+// rejected command and become the dispatch key; the notification supplies
+// the rest (reason, cover) and may be nil for handlers that ignore it.
+// State is rebuilt from the accumulated prior events, and on success any
+// compensating events in the response are folded into the stream so
+// subsequent When calls see them.
+func (h *TestAggregate) WhenRejected(domain, command string, notification *pb.Notification) *RejectionResult {
+	h.tb.Helper()
+	if notification == nil {
+		notification = NewNotification(domain, command, "", nil)
+	}
+	notification.Domain = domain
+	notification.Command = command
+	var state any
+	if h.router.rebuild != nil {
+		state = h.router.rebuild(h.prior)
+	}
+	response, err := h.router.HandleRejection(notification, state)
+	if err == nil {
+		for _, page := range response.GetEvents().GetPages() {
+			h.prior = mergeBooks(h.prior, &pb.EventBook{
+				Cover: &pb.Cover{Domain: h.router.Domain(), Root: &pb.UUID{Value: h.root}},
+				Pages: []*pb.EventPage{page},
+			})
+		}
+	}
+	return &RejectionResult{tb: h.tb, response: response, err: err}
+}
+
+// Events returns the aggregate's accumulated event stream.
+func (h *TestAggregate) Events() *pb.EventBook {
+	return h.prior
+}
+
+// appendPrior adds one packed event to the prior stream.
+func (h *TestAggregate) appendPrior(event *anypb.Any) {
+	if h.prior == nil {
+		h.prior = &pb.EventBook{
+			Cover: &pb.Cover{Domain: h.router.Domain(), Root: &pb.UUID{Value: h.root}},
+		}
+	}
+	h.prior.Pages = append(h.prior.Pages, &pb.EventPage{
+		Sequence:  &pb.EventPage_Num{Num: NextSequence(h.prior)},
+		CreatedAt: timestamppb.New(h.now),
+		Event:     event,
+	})
+}
+
+// AggregateResult carries one dispatch outcome with assertion helpers.
+// Expectation failures are reported through the harness's testing.TB.
+type AggregateResult struct {
+	tb     testing.TB
+	events *pb.EventBook
+	err    error
+}
+
+// Events returns the emitted event book, nil when dispatch failed.
+func (r *AggregateResult) Events() *pb.EventBook {
+	return r.events
+}
+
+// Err returns the dispatch error, nil on success.
+func (r *AggregateResult) Err() error {
+	return r.err
+}
+
+// ExpectEvent asserts that an event with the given short type name was
+// emitted.
+func (r *AggregateResult) ExpectEvent(name string) *AggregateResult {
+	r.tb.Helper()
+	if r.err != nil {
+		r.tb.Errorf("expected event %q, got error: %v", name, r.err)
+		return r
+	}
+	for _, page := range r.events.GetPages() {
+		if TypeNameEquals(page.GetEvent().GetTypeUrl(), name) {
+			return r
+		}
+	}
+	r.tb.Errorf("expected event %q among %d emitted pages", name, len(r.events.GetPages()))
+	return r
+}
+
+// ExpectEventCount asserts the number of emitted event pages.
+func (r *AggregateResult) ExpectEventCount(n int) *AggregateResult {
+	r.tb.Helper()
+	if r.err != nil {
+		r.tb.Errorf("expected %d events, got error: %v", n, r.err)
+		return r
+	}
+	if got := len(r.events.GetPages()); got != n {
+		r.tb.Errorf("expected %d emitted events, got %d", n, got)
+	}
+	return r
+}
+
+// ExpectRejected asserts that dispatch failed with the given status code.
+func (r *AggregateResult) ExpectRejected(code codes.Code) *AggregateResult {
+	r.tb.Helper()
+	if r.err == nil {
+		r.tb.Errorf("expected rejection with code %v, but dispatch succeeded", code)
+		return r
+	}
+	if got := status.Code(r.err); got != code {
+		r.tb.Errorf("expected rejection code %v, got %v (%v)", code, got, r.err)
+	}
+	return r
+}
+
+// RejectionResult carries one rejection-handler outcome with assertion
+// helpers, the compensation-side counterpart of AggregateResult.
+type RejectionResult struct {
+	tb       testing.TB
+	response *pb.BusinessResponse
+	err      error
+}
+
+// Response returns the handler's business response, nil when it failed.
+func (r *RejectionResult) Response() *pb.BusinessResponse {
+	return r.response
+}
+
+// Err returns the handler error, nil on success.
+func (r *RejectionResult) Err() error {
+	return r.err
+}
+
+// ExpectCompensatingEvent asserts that the response carries a compensating
+// event with the given short type name.
+func (r *RejectionResult) ExpectCompensatingEvent(name string) *RejectionResult {
+	r.tb.Helper()
+	if r.err != nil {
+		r.tb.Errorf("expected compensating event %q, got error: %v", name, r.err)
+		return r
+	}
+	for _, page := range r.response.GetEvents().GetPages() {
+		if TypeNameEquals(page.GetEvent().GetTypeUrl(), name) {
+			return r
+		}
+	}
+	r.tb.Errorf("expected compensating event %q among %d emitted pages",
+		name, len(r.response.GetEvents().GetPages()))
+	return r
+}
+
+// ExpectRevocation asserts that the response revokes a command with the
+// given short type name.
+func (r *RejectionResult) ExpectRevocation(name string) *RejectionResult {
+	r.tb.Helper()
+	if r.err != nil {
+		r.tb.Errorf("expected revocation of %q, got error: %v", name, r.err)
+		return r
+	}
+	for _, page := range r.response.GetRevocation().GetPages() {
+		if TypeNameEquals(page.GetCommand().GetTypeUrl(), name) {
+			return r
+		}
+	}
+	r.tb.Errorf("expected revocation of %q among %d revocation pages",
+		name, len(r.response.GetRevocation().GetPages()))
+	return r
+}
+
+// ExpectNoRevocation asserts that the response revokes nothing.
+func (r *RejectionResult) ExpectNoRevocation() *RejectionResult {
+	r.tb.Helper()
+	if r.err == nil && len(r.response.GetRevocation().GetPages()) > 0 {
+		r.tb.Errorf("expected no revocation, got %d revocation pages",
+			len(r.response.GetRevocation().GetPages()))
+	}
+	return r
+}
@@ -0,0 +1,96 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestAggregateHarnessDispatchesThroughRouter(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	h := NewTestAggregate(t, router)
+
+	h.When(wrapperspb.String("register")).
+		ExpectEvent("StringValue").
+		ExpectEventCount(1)
+}
+
+func TestAggregateHarnessAccumulatesSequences(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	h := NewTestAggregate(t, router)
+
+	h.Given(wrapperspb.String("registered"), wrapperspb.String("funded"))
+	res := h.When(wrapperspb.String("play"))
+	res.ExpectEventCount(1)
+
+	// The harness fed NextSequence through the real router: two givens
+	// occupy 0 and 1, so the emitted event lands at 2.
+	if got, ok := pageNum(res.Events().GetPages()[0]); !ok || got != 2 {
+		t.Errorf("emitted sequence = %d (set %v), want 2", got, ok)
+	}
+	if got := len(h.Events().GetPages()); got != 3 {
+		t.Errorf("accumulated stream has %d pages, want 3", got)
+	}
+}
+
+func TestAggregateHarnessReportsRejection(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	h := NewTestAggregate(t, router)
+
+	// BoolValue has no registered handler.
+	h.When(wrapperspb.Bool(true)).ExpectRejected(codes.Unimplemented)
+}
+
+func TestAggregateHarnessWhenRejectedDispatchesHandler(t *testing.T) {
+	router := NewCommandRouter("player").
+		OnRejected("table", "JoinTable", func(n *pb.Notification, state any) (*pb.BusinessResponse, error) {
+			refund, err := anypb.New(wrapperspb.String("refund:" + n.GetReason()))
+			if err != nil {
+				return nil, err
+			}
+			revoke, err := anypb.New(wrapperspb.Bool(true))
+			if err != nil {
+				return nil, err
+			}
+			return &pb.BusinessResponse{
+				Events: &pb.EventBook{Pages: []*pb.EventPage{{
+					Sequence:  &pb.EventPage_Num{Num: 0},
+					CreatedAt: timestamppb.Now(),
+					Event:     refund,
+				}}},
+				Revocation: &pb.CommandBook{Pages: []*pb.CommandPage{{
+					Payload: &pb.CommandPage_Command{Command: revoke},
+				}}},
+			}, nil
+		})
+
+	h := NewTestAggregate(t, router)
+	h.WhenRejected("table", "JoinTable", NewNotification("table", "JoinTable", "table full", nil)).
+		ExpectCompensatingEvent("StringValue").
+		ExpectRevocation("BoolValue")
+
+	// Compensating events join the stream for subsequent dispatches.
+	if got := len(h.Events().GetPages()); got != 1 {
+		t.Errorf("accumulated stream has %d pages, want 1", got)
+	}
+}
+
+func TestAggregateHarnessWhenRejectedUnmatchedFallsBackToDefault(t *testing.T) {
+	router := NewCommandRouter("player")
+	h := NewTestAggregate(t, router)
+
+	// No OnRejected registration: the framework default acknowledges with
+	// an empty response.
+	res := h.WhenRejected("table", "JoinTable", nil).ExpectNoRevocation()
+	if res.Err() != nil {
+		t.Fatalf("default rejection handling failed: %v", res.Err())
+	}
+	if len(res.Response().GetEvents().GetPages()) != 0 {
+		t.Error("default rejection handling emitted compensating events")
+	}
+}
@@ -0,0 +1,207 @@
+// Package angzarr is the Go client library for the angzarr event-sourcing
+// framework.  It provides the routers, server helpers, and book/page
+// utilities that business-logic services build on.
+package angzarr
+
+import (
+	"fmt"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// pageNum extracts the explicit sequence number from a page's oneof,
+// reporting false for force-sequenced or unset pages.
+func pageNum(page *pb.EventPage) (uint32, bool) {
+	if page == nil {
+		return 0, false
+	}
+	if n, ok := page.Sequence.(*pb.EventPage_Num); ok {
+		return n.Num, true
+	}
+	return 0, false
+}
+
+// PageSequence returns the sequence number of an event or command page,
+// regardless of representation.  The wire types deliberately differ:
+// EventPage carries its sequence in a oneof (an explicit number or a
+// force-append marker), while CommandPage carries a bare uint32.  Code
+// that handles both kinds of page should go through this accessor and
+// SetPageSequence instead of reaching into the oneof, which only exists
+// on EventPage.  Unset or force-sequenced event pages report 0.
+func PageSequence(page any) uint32 {
+	switch p := page.(type) {
+	case *pb.EventPage:
+		n, _ := pageNum(p)
+		return n
+	case *pb.CommandPage:
+		return p.GetSequence()
+	}
+	return 0
+}
+
+// SetPageSequence sets the sequence number on an event or command page,
+// writing the representation canonical for each type: the EventPage_Num
+// oneof wrapper for event pages, the bare field for command pages.  Pages
+// of any other type are left untouched.
+func SetPageSequence(page any, n uint32) {
+	switch p := page.(type) {
+	case *pb.EventPage:
+		if p != nil {
+			p.Sequence = &pb.EventPage_Num{Num: n}
+		}
+	case *pb.CommandPage:
+		if p != nil {
+			p.Sequence = n
+		}
+	}
+}
+
+// NextSequence returns the next free event sequence for the book,
+// accounting for snapshots: the maximum of one past the last page's
+// explicit sequence and one past the snapshot's sequence.  A snapshot-only
+// book therefore continues from the snapshot rather than restarting at 0
+// and clobbering history; a book with neither pages nor snapshot starts
+// at 0.
+func NextSequence(book *pb.EventBook) uint32 {
+	next := uint32(0)
+	if snap := book.GetSnapshot(); snap != nil {
+		next = snap.GetSequence() + 1
+	}
+	pages := book.GetPages()
+	if len(pages) > 0 {
+		if num, ok := pageNum(pages[len(pages)-1]); ok {
+			if num+1 > next {
+				next = num + 1
+			}
+		} else if uint32(len(pages)) > next {
+			next = uint32(len(pages))
+		}
+	}
+	return next
+}
+
+// LastSequence returns the highest sequence recorded in the book: the
+// maximum explicit page sequence, scanned rather than taken from the final
+// page so out-of-order books still report correctly, falling back to the
+// snapshot's sequence for snapshot-only books.  An empty book reports 0,
+// indistinguishable from a book whose last event is at sequence 0 — pair
+// with a page-count check where that matters.  Projectors use this to
+// stamp Projection.Sequence.
+func LastSequence(book *pb.EventBook) uint32 {
+	last := uint32(0)
+	found := false
+	for _, page := range book.GetPages() {
+		if num, ok := pageNum(page); ok && (!found || num > last) {
+			last = num
+			found = true
+		}
+	}
+	if !found {
+		if snap := book.GetSnapshot(); snap != nil {
+			return snap.GetSequence()
+		}
+	}
+	return last
+}
+
+// ValidateSequences verifies that a book's pages arrive in order with no
+// gaps: each page carries an explicit sequence, and the sequences run
+// contiguously from the expected start — one past the snapshot when the
+// book has one, 0 otherwise.  It returns nil for a well-ordered book and
+// a descriptive error naming the first violation.  This is the
+// sequencing subset of ValidateBookConsistency, for consumers that care
+// about ordering but not payload invariants; routers apply it when
+// sequence validation is switched on.
+func ValidateSequences(book *pb.EventBook) error {
+	expected := uint32(0)
+	if snap := book.GetSnapshot(); snap != nil {
+		expected = snap.GetSequence() + 1
+	}
+	for i, page := range book.GetPages() {
+		num, ok := pageNum(page)
+		if !ok {
+			return fmt.Errorf("page %d has no explicit sequence", i)
+		}
+		switch {
+		case num == expected:
+		case num < expected:
+			return fmt.Errorf("page %d is out of order: sequence %d after %d", i, num, expected-1)
+		default:
+			return fmt.Errorf("gap before page %d: expected sequence %d, got %d", i, expected, num)
+		}
+		expected++
+	}
+	return nil
+}
+
+// AssertContiguous verifies that a book's pages are numbered
+// expectedStart, expectedStart+1, … with no gaps or repeats — the
+// contract for events a handler emits at a given next sequence.  Pages
+// with a force-append marker instead of an explicit sequence are exempt:
+// the store assigns their numbers.  The command router applies this to
+// every emitted book, so an off-by-one in a multi-event handler fails
+// the command instead of corrupting the stream.
+func AssertContiguous(book *pb.EventBook, expectedStart uint32) error {
+	expected := expectedStart
+	for i, page := range book.GetPages() {
+		num, ok := pageNum(page)
+		if !ok {
+			continue
+		}
+		if num != expected {
+			return fmt.Errorf("page %d has sequence %d, expected %d", i, num, expected)
+		}
+		expected++
+	}
+	return nil
+}
+
+// ValidateBookConsistency checks the structural invariants of an event book
+// before it is handed to business logic or persisted: the book has a cover
+// with a domain and root, page sequences are unique and contiguous starting
+// from the snapshot base (snapshot sequence + 1, or 0 without a snapshot),
+// every page carries an event unless it is explicitly a marker, and every
+// page has a CreatedAt timestamp.  It returns nil for a consistent book and
+// a descriptive error naming the first violation otherwise.
+func ValidateBookConsistency(book *pb.EventBook) error {
+	if book == nil {
+		return fmt.Errorf("event book is nil")
+	}
+	cover := book.GetCover()
+	if cover.GetDomain() == "" {
+		return fmt.Errorf("event book cover has no domain")
+	}
+	if len(cover.GetRoot().GetValue()) == 0 {
+		return fmt.Errorf("event book cover for domain %q has no root", cover.GetDomain())
+	}
+
+	expected := uint32(0)
+	if snap := book.GetSnapshot(); snap != nil {
+		expected = snap.GetSequence() + 1
+	}
+	seen := make(map[uint32]bool, len(book.GetPages()))
+	for i, page := range book.GetPages() {
+		if page == nil {
+			return fmt.Errorf("page %d is nil", i)
+		}
+		num, ok := pageNum(page)
+		if !ok {
+			return fmt.Errorf("page %d has no explicit sequence", i)
+		}
+		if seen[num] {
+			return fmt.Errorf("page %d duplicates sequence %d", i, num)
+		}
+		seen[num] = true
+		if num != expected {
+			return fmt.Errorf("page %d has sequence %d, expected %d", i, num, expected)
+		}
+		expected++
+		if page.GetEvent() == nil && !page.GetMarker() {
+			return fmt.Errorf("page at sequence %d has no event and is not a marker", num)
+		}
+		if page.GetCreatedAt() == nil {
+			return fmt.Errorf("page at sequence %d has no CreatedAt timestamp", num)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package angzarr
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// EventBookBuilder assembles event books fluently, auto-assigning
+// contiguous sequences and timestamps so tests and fixtures stop spelling
+// out EventPage structs by hand:
+//
+//	book := angzarr.NewEventBookBuilder("hand", root).
+//		Append(&examples.HandStarted{...}).
+//		Append(&examples.CardsDealt{...}).
+//		Build()
+//
+// Timestamps come from a fixed fake clock advancing one second per page,
+// keeping built books deterministic; use WithTime to anchor elsewhere.
+type EventBookBuilder struct {
+	book *pb.EventBook
+	next uint32
+	now  time.Time
+}
+
+// NewEventBookBuilder starts a book for the given domain and root.
+func NewEventBookBuilder(domain string, root []byte) *EventBookBuilder {
+	return &EventBookBuilder{
+		book: &pb.EventBook{
+			Cover: &pb.Cover{Domain: domain, Root: &pb.UUID{Value: root}},
+		},
+		now: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// WithTime anchors the fake clock used for page timestamps.
+func (b *EventBookBuilder) WithTime(t time.Time) *EventBookBuilder {
+	b.now = t
+	return b
+}
+
+// WithSnapshot sets the book's snapshot from a state message at the given
+// sequence; appended pages continue from seq+1 unless AppendAt moved the
+// cursor past it.
+func (b *EventBookBuilder) WithSnapshot(state proto.Message, seq uint32) *EventBookBuilder {
+	b.book.Snapshot = &pb.Snapshot{Sequence: seq, State: b.pack(state)}
+	if b.next <= seq {
+		b.next = seq + 1
+	}
+	return b
+}
+
+// Append adds one event at the next free sequence.
+func (b *EventBookBuilder) Append(msg proto.Message) *EventBookBuilder {
+	return b.AppendAt(b.next, msg)
+}
+
+// AppendAt adds one event at an explicit sequence, for building books with
+// gaps or out-of-order fixtures.  Subsequent Append calls continue from
+// seq+1.
+func (b *EventBookBuilder) AppendAt(seq uint32, msg proto.Message) *EventBookBuilder {
+	b.book.Pages = append(b.book.Pages, &pb.EventPage{
+		Sequence:  &pb.EventPage_Num{Num: seq},
+		CreatedAt: timestamppb.New(b.now),
+		Event:     b.pack(msg),
+	})
+	b.now = b.now.Add(time.Second)
+	if b.next <= seq {
+		b.next = seq + 1
+	}
+	return b
+}
+
+// Build returns the assembled book.  The builder is single-use; the
+// returned book shares no state with subsequent builders.
+func (b *EventBookBuilder) Build() *pb.EventBook {
+	return b.book
+}
+
+// pack marshals a message into an Any.  A failure here is a wiring bug in
+// the fixture, so it panics rather than threading errors through every
+// builder call.
+func (b *EventBookBuilder) pack(msg proto.Message) *anypb.Any {
+	packed, err := anypb.New(msg)
+	if err != nil {
+		panic(fmt.Sprintf("angzarr: packing %T into event book: %v", msg, err))
+	}
+	return packed
+}
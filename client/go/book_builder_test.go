@@ -0,0 +1,58 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestEventBookBuilderAssignsSequences(t *testing.T) {
+	book := NewEventBookBuilder("player", testRoot().GetValue()).
+		Append(wrapperspb.String("registered")).
+		Append(wrapperspb.String("funded")).
+		Build()
+
+	if err := ValidateBookConsistency(book); err != nil {
+		t.Fatalf("built book inconsistent: %v", err)
+	}
+	for i, page := range book.GetPages() {
+		if got, ok := pageNum(page); !ok || got != uint32(i) {
+			t.Errorf("page %d sequence = %d (set %v)", i, got, ok)
+		}
+		if page.GetCreatedAt() == nil {
+			t.Errorf("page %d missing timestamp", i)
+		}
+	}
+}
+
+func TestEventBookBuilderSnapshotAdvancesCursor(t *testing.T) {
+	book := NewEventBookBuilder("player", testRoot().GetValue()).
+		WithSnapshot(wrapperspb.Int32(42), 4).
+		Append(wrapperspb.String("after")).
+		Build()
+
+	if book.GetSnapshot().GetSequence() != 4 {
+		t.Fatalf("snapshot sequence = %d, want 4", book.GetSnapshot().GetSequence())
+	}
+	if got, _ := pageNum(book.Pages[0]); got != 5 {
+		t.Errorf("first page after snapshot = %d, want 5", got)
+	}
+	if NextSequence(book) != 6 {
+		t.Errorf("NextSequence = %d, want 6", NextSequence(book))
+	}
+}
+
+func TestEventBookBuilderAppendAtGap(t *testing.T) {
+	book := NewEventBookBuilder("player", testRoot().GetValue()).
+		Append(wrapperspb.String("first")).
+		AppendAt(5, wrapperspb.String("gapped")).
+		Append(wrapperspb.String("resumed")).
+		Build()
+
+	want := []uint32{0, 5, 6}
+	for i, page := range book.GetPages() {
+		if got, _ := pageNum(page); got != want[i] {
+			t.Errorf("page %d sequence = %d, want %d", i, got, want[i])
+		}
+	}
+}
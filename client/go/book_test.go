@@ -0,0 +1,364 @@
+package angzarr
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func testRoot() *pb.UUID {
+	return &pb.UUID{Value: []byte("0123456789abcdef")}
+}
+
+func testPage(t *testing.T, seq uint32) *pb.EventPage {
+	t.Helper()
+	event, err := anypb.New(wrapperspb.String("event"))
+	if err != nil {
+		t.Fatalf("packing event: %v", err)
+	}
+	return &pb.EventPage{
+		Sequence:  &pb.EventPage_Num{Num: seq},
+		CreatedAt: timestamppb.Now(),
+		Event:     event,
+	}
+}
+
+func testBook(t *testing.T, seqs ...uint32) *pb.EventBook {
+	t.Helper()
+	book := &pb.EventBook{
+		Cover: &pb.Cover{Domain: "player", Root: testRoot()},
+	}
+	for _, seq := range seqs {
+		book.Pages = append(book.Pages, testPage(t, seq))
+	}
+	return book
+}
+
+func TestValidateBookConsistencyValid(t *testing.T) {
+	if err := ValidateBookConsistency(testBook(t, 0, 1, 2)); err != nil {
+		t.Errorf("valid book: unexpected error %v", err)
+	}
+}
+
+func TestValidateBookConsistencyValidWithSnapshot(t *testing.T) {
+	book := testBook(t, 5, 6)
+	book.Snapshot = &pb.Snapshot{Sequence: 4}
+	if err := ValidateBookConsistency(book); err != nil {
+		t.Errorf("valid snapshot book: unexpected error %v", err)
+	}
+}
+
+func TestValidateBookConsistencyViolations(t *testing.T) {
+	tests := []struct {
+		name string
+		book func(t *testing.T) *pb.EventBook
+		want string
+	}{
+		{
+			name: "nil book",
+			book: func(t *testing.T) *pb.EventBook { return nil },
+			want: "nil",
+		},
+		{
+			name: "missing domain",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 0)
+				book.Cover.Domain = ""
+				return book
+			},
+			want: "no domain",
+		},
+		{
+			name: "missing root",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 0)
+				book.Cover.Root = nil
+				return book
+			},
+			want: "no root",
+		},
+		{
+			name: "duplicate sequence",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 0, 1)
+				book.Pages[1].Sequence = &pb.EventPage_Num{Num: 0}
+				return book
+			},
+			want: "duplicates sequence 0",
+		},
+		{
+			name: "gap in sequences",
+			book: func(t *testing.T) *pb.EventBook { return testBook(t, 0, 2) },
+			want: "expected 1",
+		},
+		{
+			name: "wrong snapshot base",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 0)
+				book.Snapshot = &pb.Snapshot{Sequence: 4}
+				return book
+			},
+			want: "expected 5",
+		},
+		{
+			name: "missing event",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 0)
+				book.Pages[0].Event = nil
+				return book
+			},
+			want: "no event",
+		},
+		{
+			name: "nil timestamp",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 0)
+				book.Pages[0].CreatedAt = nil
+				return book
+			},
+			want: "no CreatedAt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBookConsistency(tt.book(t))
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextSequence(t *testing.T) {
+	tests := []struct {
+		name string
+		book func(t *testing.T) *pb.EventBook
+		want uint32
+	}{
+		{
+			name: "empty book",
+			book: func(t *testing.T) *pb.EventBook { return testBook(t) },
+			want: 0,
+		},
+		{
+			name: "pages only",
+			book: func(t *testing.T) *pb.EventBook { return testBook(t, 0, 1, 2) },
+			want: 3,
+		},
+		{
+			name: "snapshot only",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t)
+				book.Snapshot = &pb.Snapshot{Sequence: 7}
+				return book
+			},
+			want: 8,
+		},
+		{
+			name: "snapshot and pages",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 8, 9)
+				book.Snapshot = &pb.Snapshot{Sequence: 7}
+				return book
+			},
+			want: 10,
+		},
+		{
+			name: "snapshot ahead of pages",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 0, 1)
+				book.Snapshot = &pb.Snapshot{Sequence: 7}
+				return book
+			},
+			want: 8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextSequence(tt.book(t)); got != tt.want {
+				t.Errorf("NextSequence = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBookConsistencyMarkerPage(t *testing.T) {
+	book := testBook(t, 0)
+	book.Pages[0].Event = nil
+	book.Pages[0].Marker = true
+	if err := ValidateBookConsistency(book); err != nil {
+		t.Errorf("marker page: unexpected error %v", err)
+	}
+}
+
+func TestPageSequenceAccessors(t *testing.T) {
+	event := &pb.EventPage{}
+	SetPageSequence(event, 7)
+	if got := PageSequence(event); got != 7 {
+		t.Errorf("event page sequence = %d, want 7", got)
+	}
+	if _, ok := event.Sequence.(*pb.EventPage_Num); !ok {
+		t.Error("SetPageSequence did not write the canonical oneof wrapper")
+	}
+
+	command := &pb.CommandPage{}
+	SetPageSequence(command, 9)
+	if got := PageSequence(command); got != 9 {
+		t.Errorf("command page sequence = %d, want 9", got)
+	}
+
+	forced := &pb.EventPage{Sequence: &pb.EventPage_Force{Force: true}}
+	if got := PageSequence(forced); got != 0 {
+		t.Errorf("force-sequenced page reported %d, want 0", got)
+	}
+}
+
+func TestLastSequence(t *testing.T) {
+	if got := LastSequence(nil); got != 0 {
+		t.Errorf("nil book = %d, want 0", got)
+	}
+	if got := LastSequence(testBook(t, 0, 1, 2)); got != 2 {
+		t.Errorf("contiguous book = %d, want 2", got)
+	}
+	// Out-of-order pages still report the highest sequence.
+	if got := LastSequence(testBook(t, 4, 2, 3)); got != 4 {
+		t.Errorf("out-of-order book = %d, want 4", got)
+	}
+	snapshotOnly := &pb.EventBook{Snapshot: &pb.Snapshot{Sequence: 9}}
+	if got := LastSequence(snapshotOnly); got != 9 {
+		t.Errorf("snapshot-only book = %d, want 9", got)
+	}
+}
+
+func TestValidateSequences(t *testing.T) {
+	tests := []struct {
+		name string
+		book func(t *testing.T) *pb.EventBook
+		want string
+	}{
+		{
+			name: "contiguous from zero",
+			book: func(t *testing.T) *pb.EventBook { return testBook(t, 0, 1, 2) },
+		},
+		{
+			name: "contiguous from snapshot",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 5, 6)
+				book.Snapshot = &pb.Snapshot{Sequence: 4}
+				return book
+			},
+		},
+		{
+			name: "empty book",
+			book: func(t *testing.T) *pb.EventBook { return &pb.EventBook{} },
+		},
+		{
+			name: "gap",
+			book: func(t *testing.T) *pb.EventBook { return testBook(t, 0, 2) },
+			want: "gap",
+		},
+		{
+			name: "out of order",
+			book: func(t *testing.T) *pb.EventBook { return testBook(t, 0, 1, 1) },
+			want: "out of order",
+		},
+		{
+			name: "starts past zero",
+			book: func(t *testing.T) *pb.EventBook { return testBook(t, 1) },
+			want: "gap",
+		},
+		{
+			name: "page below snapshot",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 3)
+				book.Snapshot = &pb.Snapshot{Sequence: 4}
+				return book
+			},
+			want: "out of order",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSequences(tt.book(t))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertContiguous(t *testing.T) {
+	tests := []struct {
+		name  string
+		book  func(t *testing.T) *pb.EventBook
+		start uint32
+		want  string
+	}{
+		{
+			name:  "contiguous from start",
+			book:  func(t *testing.T) *pb.EventBook { return testBook(t, 3, 4, 5) },
+			start: 3,
+		},
+		{
+			name: "empty book",
+			book: func(t *testing.T) *pb.EventBook { return &pb.EventBook{} },
+		},
+		{
+			name:  "gap",
+			book:  func(t *testing.T) *pb.EventBook { return testBook(t, 3, 5) },
+			start: 3,
+			want:  "page 1 has sequence 5, expected 4",
+		},
+		{
+			name:  "wrong start",
+			book:  func(t *testing.T) *pb.EventBook { return testBook(t, 4) },
+			start: 3,
+			want:  "page 0 has sequence 4, expected 3",
+		},
+		{
+			name:  "repeat",
+			book:  func(t *testing.T) *pb.EventBook { return testBook(t, 3, 3) },
+			start: 3,
+			want:  "page 1 has sequence 3, expected 4",
+		},
+		{
+			name: "force pages exempt",
+			book: func(t *testing.T) *pb.EventBook {
+				book := testBook(t, 3)
+				book.Pages = append(book.Pages,
+					&pb.EventPage{Sequence: &pb.EventPage_Force{Force: true}})
+				book.Pages = append(book.Pages, testBook(t, 4).GetPages()...)
+				return book
+			},
+			start: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AssertContiguous(tt.book(t), tt.start)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
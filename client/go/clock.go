@@ -0,0 +1,66 @@
+package angzarr
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Clock supplies the current time to handlers.  Production code uses the
+// real clock by default; tests inject a fixed one so emitted timestamps
+// (CreatedAt and the like) become assertable instead of racing the wall
+// clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock, reading the wall clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FixedClock returns a Clock frozen at t.
+func FixedClock(t time.Time) Clock { return fixedClock(t) }
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// clockKey keys the injected Clock in a context.
+type clockKey struct{}
+
+// WithClock returns a context carrying the given clock, which Now reads
+// in preference to the wall clock.
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// Now returns the current time as a protobuf timestamp, read from the
+// clock carried in ctx when one was injected with WithClock and from the
+// wall clock otherwise.  Handlers should call this instead of
+// timestamppb.Now() so their emitted events are deterministic under
+// test.
+func Now(ctx context.Context) *timestamppb.Timestamp {
+	return timestamppb.New(NowTime(ctx))
+}
+
+// NowTime is Now for code that wants a time.Time rather than a protobuf
+// timestamp.
+func NowTime(ctx context.Context) time.Time {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock.Now()
+	}
+	return time.Now()
+}
+
+// ClockInterceptor threads a clock into every request's context, so
+// handlers reached through the server read time via Now(ctx).  Install a
+// fixed clock in integration tests to freeze emitted timestamps; omit
+// the interceptor entirely in production to keep the wall clock.
+func ClockInterceptor(clock Clock) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(WithClock(ctx, clock), req)
+	}
+}
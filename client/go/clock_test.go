@@ -0,0 +1,27 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNowReadsInjectedClock(t *testing.T) {
+	frozen := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := WithClock(context.Background(), FixedClock(frozen))
+
+	if got := Now(ctx).AsTime(); !got.Equal(frozen) {
+		t.Errorf("Now(ctx) = %v, want %v", got, frozen)
+	}
+	if got := NowTime(ctx); !got.Equal(frozen) {
+		t.Errorf("NowTime(ctx) = %v, want %v", got, frozen)
+	}
+}
+
+func TestNowDefaultsToWallClock(t *testing.T) {
+	before := time.Now()
+	got := Now(context.Background()).AsTime()
+	if got.Before(before.Add(-time.Minute)) || got.After(time.Now().Add(time.Minute)) {
+		t.Errorf("Now without a clock = %v, want roughly the wall clock", got)
+	}
+}
@@ -0,0 +1,125 @@
+package angzarr
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// cloudEventsSpecVersion is the CloudEvents spec version stamped on emitted
+// events whose handlers did not set one.
+const cloudEventsSpecVersion = "1.0"
+
+// CloudEventHandler maps a single event page to an outbound CloudEvent.
+// Returning nil skips publication for that page.
+type CloudEventHandler func(source *pb.EventBook, page *pb.EventPage) (*pb.CloudEvent, error)
+
+// CloudEventsRouter converts domain events into CloudEvents for external
+// consumption.  Handlers only need to set Type and Data; the router fills
+// the remaining required CloudEvents 1.0 attributes (id, source, time,
+// specversion) from the event book so emitted events are valid for
+// downstream HTTP and Kafka consumers.
+type CloudEventsRouter struct {
+	name     string
+	domains  []string
+	handlers map[string]CloudEventHandler
+	filter   func(event proto.Message) bool
+}
+
+// NewCloudEventsRouter creates a CloudEvents router with the given
+// projector name, subscribed to events from the listed domains.
+func NewCloudEventsRouter(name string, domains ...string) *CloudEventsRouter {
+	return &CloudEventsRouter{
+		name:     name,
+		domains:  domains,
+		handlers: make(map[string]CloudEventHandler),
+	}
+}
+
+// Name returns the projector's registered name.
+func (r *CloudEventsRouter) Name() string {
+	return r.name
+}
+
+// Domains returns the domains the router subscribes to.
+func (r *CloudEventsRouter) Domains() []string {
+	return r.domains
+}
+
+// On registers a handler for the event with the given short type name.
+func (r *CloudEventsRouter) On(name string, handler CloudEventHandler) *CloudEventsRouter {
+	r.handlers[name] = handler
+	return r
+}
+
+// Filter registers a predicate consulted before any handler runs: pages
+// whose unpacked event it rejects are skipped without reaching the
+// handler.  This keeps threshold-style publication rules — only deposits
+// over an amount, say — out of every handler body.  Filtered pages are
+// indistinguishable from unhandled ones: nothing is emitted for them.
+func (r *CloudEventsRouter) Filter(keep func(event proto.Message) bool) *CloudEventsRouter {
+	r.filter = keep
+	return r
+}
+
+// Handle dispatches each page with a registered handler and returns the
+// resulting CloudEvents with required attributes filled in.  Id is derived
+// from the cover root and page sequence, Source from the source domain and
+// projector name, Time from the page's CreatedAt, and SpecVersion defaults
+// to "1.0" — each only when the handler left the attribute unset.  A
+// handler returning a nil CloudEvent (and nil error) skips publication
+// for that page — no blank event is emitted; this is the supported way
+// for a handler to decline a page it was registered for.
+func (r *CloudEventsRouter) Handle(source *pb.EventBook) ([]*pb.CloudEvent, error) {
+	var events []*pb.CloudEvent
+	for _, page := range source.GetPages() {
+		handler, ok := r.handlers[typeNameOf(page.GetEvent().GetTypeUrl())]
+		if !ok {
+			continue
+		}
+		if r.filter != nil {
+			unpacked, err := page.GetEvent().UnmarshalNew()
+			if err != nil {
+				return nil, err
+			}
+			if !r.filter(unpacked) {
+				continue
+			}
+		}
+		event, err := handler(source, page)
+		if err != nil {
+			return nil, err
+		}
+		if event == nil {
+			continue
+		}
+		r.fillDefaults(event, source, page)
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// fillDefaults populates the required CloudEvents attributes the handler
+// left unset.
+func (r *CloudEventsRouter) fillDefaults(event *pb.CloudEvent, source *pb.EventBook, page *pb.EventPage) {
+	cover := source.GetCover()
+	if event.Id == "" {
+		num, _ := pageNum(page)
+		event.Id = fmt.Sprintf("%s-%d", hex.EncodeToString(cover.GetRoot().GetValue()), num)
+	}
+	if event.Source == "" {
+		event.Source = fmt.Sprintf("/%s/%s", cover.GetDomain(), r.name)
+	}
+	if event.Time == nil {
+		event.Time = page.GetCreatedAt()
+	}
+	if event.SpecVersion == "" {
+		event.SpecVersion = cloudEventsSpecVersion
+	}
+	if event.Type == "" {
+		event.Type = typeNameOf(page.GetEvent().GetTypeUrl())
+	}
+}
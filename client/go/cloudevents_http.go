@@ -0,0 +1,207 @@
+package angzarr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// HTTPSink posts CloudEvents to a webhook URL, in the CloudEvents HTTP
+// binding's binary mode (attributes as ce-* headers) by default or
+// structured mode (a single JSON body) when toggled.  Transient failures
+// (network errors and 5xx responses) are retried with exponential backoff.
+type HTTPSink struct {
+	url         string
+	client      *http.Client
+	structured  bool
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+// NewHTTPSink creates a sink posting to the given webhook URL with three
+// attempts and a 100ms base backoff.
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{
+		url:         url,
+		client:      http.DefaultClient,
+		maxAttempts: 3,
+		baseDelay:   100 * time.Millisecond,
+	}
+}
+
+// WithClient replaces the underlying HTTP client.
+func (s *HTTPSink) WithClient(client *http.Client) *HTTPSink {
+	s.client = client
+	return s
+}
+
+// WithStructuredMode selects between structured content mode (a JSON body
+// carrying all attributes) and the default binary mode (attributes as
+// ce-* headers with the raw payload as the body).
+func (s *HTTPSink) WithStructuredMode(structured bool) *HTTPSink {
+	s.structured = structured
+	return s
+}
+
+// WithRetry configures the retry budget and base backoff delay.
+func (s *HTTPSink) WithRetry(maxAttempts int, baseDelay time.Duration) *HTTPSink {
+	s.maxAttempts = maxAttempts
+	s.baseDelay = baseDelay
+	return s
+}
+
+// Send posts one CloudEvent, retrying transient failures until the retry
+// budget is exhausted.
+func (s *HTTPSink) Send(ctx context.Context, event *pb.CloudEvent) error {
+	var lastErr error
+	for attempt := 0; attempt < s.maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := s.baseDelay << (attempt - 1)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		lastErr = s.post(ctx, event)
+		if lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("posting cloud event %s after %d attempts: %w", event.GetId(), s.maxAttempts, lastErr)
+}
+
+// post performs a single delivery attempt.
+func (s *HTTPSink) post(ctx context.Context, event *pb.CloudEvent) error {
+	req, err := s.request(ctx, event)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	if resp.StatusCode >= 400 {
+		// Client errors are not retryable; surface them terminally.
+		return &permanentSinkError{status: resp.Status}
+	}
+	return nil
+}
+
+// permanentSinkError marks delivery failures that retrying cannot fix.
+type permanentSinkError struct {
+	status string
+}
+
+func (e *permanentSinkError) Error() string {
+	return "webhook rejected event: " + e.status
+}
+
+// request builds the HTTP request for the configured content mode.
+func (s *HTTPSink) request(ctx context.Context, event *pb.CloudEvent) (*http.Request, error) {
+	if s.structured {
+		body, err := json.Marshal(structuredCloudEvent(event))
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+		return req, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(event.GetData().GetValue()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/protobuf")
+	req.Header.Set("ce-specversion", event.GetSpecVersion())
+	req.Header.Set("ce-id", event.GetId())
+	req.Header.Set("ce-source", event.GetSource())
+	req.Header.Set("ce-type", event.GetType())
+	if ts := event.GetTime(); ts != nil {
+		req.Header.Set("ce-time", ts.AsTime().Format(time.RFC3339Nano))
+	}
+	for name, value := range event.GetExtensions() {
+		req.Header.Set("ce-"+name, value)
+	}
+	return req, nil
+}
+
+// structuredCloudEvent renders the event as a CloudEvents JSON object with
+// the payload base64-encoded, per the structured content mode.
+func structuredCloudEvent(event *pb.CloudEvent) map[string]any {
+	body := map[string]any{
+		"specversion": event.GetSpecVersion(),
+		"id":          event.GetId(),
+		"source":      event.GetSource(),
+		"type":        event.GetType(),
+	}
+	if ts := event.GetTime(); ts != nil {
+		body["time"] = ts.AsTime().Format(time.RFC3339Nano)
+	}
+	if data := event.GetData(); data != nil {
+		body["datacontenttype"] = "application/protobuf"
+		body["data_base64"] = base64.StdEncoding.EncodeToString(data.GetValue())
+	}
+	for name, value := range event.GetExtensions() {
+		body[name] = value
+	}
+	return body
+}
+
+// webhookProjectorServer adapts a CloudEventsRouter plus HTTPSink to the
+// Projector gRPC service.
+type webhookProjectorServer struct {
+	router *CloudEventsRouter
+	sink   *HTTPSink
+}
+
+func (s *webhookProjectorServer) HandleSync(ctx context.Context, events *pb.EventBook) (*pb.Projections, error) {
+	cloudEvents, err := s.router.Handle(events)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range cloudEvents {
+		if err := s.sink.Send(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.Projections{}, nil
+}
+
+// RunCloudEventsWebhook serves the router as a Projector gRPC service on
+// addr and forwards every emitted CloudEvent to the sink's webhook URL.
+// This realizes the HTTP delivery half of the CloudEvents projector; see
+// the sink's content-mode and retry options for delivery behavior.
+func RunCloudEventsWebhook(name, addr string, router *CloudEventsRouter, sink *HTTPSink) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	grpcServer, healthSrv := newGRPCServer(ServerConfig{})
+	pb.RegisterProjectorServer(grpcServer, &webhookProjectorServer{router: router, sink: sink})
+	LogServerStart(logger, "cloudevents-webhook", name, addr, nil)
+	return serveGracefully(ServerConfig{}, grpcServer, healthSrv, lis, logger, nil)
+}
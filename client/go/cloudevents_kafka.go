@@ -0,0 +1,99 @@
+package angzarr
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// KafkaHeader is a single Kafka record header.
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+// KafkaProducer is the thin producer surface the Kafka projector needs,
+// so any Kafka client (confluent, franz-go, sarama) plugs in with a
+// small adapter instead of the library choosing one.
+type KafkaProducer interface {
+	// Produce publishes one record and returns once it is accepted for
+	// delivery.
+	Produce(ctx context.Context, topic string, key, value []byte, headers []KafkaHeader) error
+}
+
+// kafkaRecord renders a CloudEvent per the CloudEvents Kafka protocol
+// binding's binary mode: attributes ride ce_* headers, the raw payload
+// is the record value, and the event id is the record key, so events for
+// one aggregate flow keep their relative order within a partition.
+func kafkaRecord(event *pb.CloudEvent) (key, value []byte, headers []KafkaHeader) {
+	headers = []KafkaHeader{
+		{Key: "content-type", Value: []byte("application/protobuf")},
+		{Key: "ce_specversion", Value: []byte(event.GetSpecVersion())},
+		{Key: "ce_id", Value: []byte(event.GetId())},
+		{Key: "ce_source", Value: []byte(event.GetSource())},
+		{Key: "ce_type", Value: []byte(event.GetType())},
+	}
+	if ts := event.GetTime(); ts != nil {
+		headers = append(headers, KafkaHeader{
+			Key: "ce_time", Value: []byte(ts.AsTime().Format(time.RFC3339Nano))})
+	}
+	for name, val := range event.GetExtensions() {
+		headers = append(headers, KafkaHeader{Key: "ce_" + name, Value: []byte(val)})
+	}
+	return []byte(event.GetId()), event.GetData().GetValue(), headers
+}
+
+// kafkaProjectorServer adapts a CloudEventsRouter plus KafkaProducer to
+// the Projector gRPC service.
+type kafkaProjectorServer struct {
+	router   *CloudEventsRouter
+	producer KafkaProducer
+	topicFor func(*pb.CloudEvent) string
+}
+
+func (s *kafkaProjectorServer) HandleSync(ctx context.Context, events *pb.EventBook) (*pb.Projections, error) {
+	cloudEvents, err := s.router.Handle(events)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range cloudEvents {
+		key, value, headers := kafkaRecord(event)
+		if err := s.producer.Produce(ctx, s.topicFor(event), key, value, headers); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.Projections{}, nil
+}
+
+// RunCloudEventsKafka serves the router as a Projector gRPC service on
+// addr and publishes every emitted CloudEvent through the producer, to
+// the topic topicFor derives from the event (a nil topicFor publishes to
+// the event's Type).  This is the Kafka delivery half of the CloudEvents
+// projector, the sibling of RunCloudEventsWebhook.
+func RunCloudEventsKafka(name, addr string, router *CloudEventsRouter, producer KafkaProducer, topicFor func(*pb.CloudEvent) string) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	if topicFor == nil {
+		topicFor = func(event *pb.CloudEvent) string { return event.GetType() }
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	grpcServer, healthSrv := newGRPCServer(ServerConfig{})
+	pb.RegisterProjectorServer(grpcServer, &kafkaProjectorServer{
+		router:   router,
+		producer: producer,
+		topicFor: topicFor,
+	})
+	LogServerStart(logger, "cloudevents-kafka", name, addr, nil)
+	return serveGracefully(ServerConfig{}, grpcServer, healthSrv, lis, logger, nil)
+}
@@ -0,0 +1,61 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// fakeProducer records produced records.
+type fakeProducer struct {
+	topics  []string
+	keys    []string
+	headers [][]KafkaHeader
+}
+
+func (p *fakeProducer) Produce(_ context.Context, topic string, key, _ []byte, headers []KafkaHeader) error {
+	p.topics = append(p.topics, topic)
+	p.keys = append(p.keys, string(key))
+	p.headers = append(p.headers, headers)
+	return nil
+}
+
+func TestKafkaProjectorPublishesBinaryMode(t *testing.T) {
+	router := NewCloudEventsRouter("prj-kafka", "player").
+		On("StringValue", func(source *pb.EventBook, page *pb.EventPage) (*pb.CloudEvent, error) {
+			return &pb.CloudEvent{Type: "player.registered", Data: page.GetEvent()}, nil
+		})
+	producer := &fakeProducer{}
+	srv := &kafkaProjectorServer{
+		router:   router,
+		producer: producer,
+		topicFor: func(event *pb.CloudEvent) string { return "topic-" + event.GetType() },
+	}
+
+	source := triggerBook(t, "player", []byte("0123456789abcdef"), 4, wrapperspb.String("alice"))
+	if _, err := srv.HandleSync(context.Background(), source); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(producer.topics) != 1 || producer.topics[0] != "topic-player.registered" {
+		t.Errorf("topics = %v, want [topic-player.registered]", producer.topics)
+	}
+	if want := "30313233343536373839616263646566-4"; producer.keys[0] != want {
+		t.Errorf("record key = %q, want event id %q", producer.keys[0], want)
+	}
+	got := make(map[string]string)
+	for _, header := range producer.headers[0] {
+		got[header.Key] = string(header.Value)
+	}
+	if got["ce_type"] != "player.registered" {
+		t.Errorf("ce_type header = %q", got["ce_type"])
+	}
+	if got["ce_specversion"] != "1.0" {
+		t.Errorf("ce_specversion header = %q", got["ce_specversion"])
+	}
+	if got["ce_id"] == "" || got["ce_source"] == "" {
+		t.Errorf("required ce_* headers missing: %v", got)
+	}
+}
@@ -0,0 +1,129 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestCloudEventsRouterFillsRequiredAttributes(t *testing.T) {
+	router := NewCloudEventsRouter("prj-cloudevents", "player").
+		On("StringValue", func(source *pb.EventBook, page *pb.EventPage) (*pb.CloudEvent, error) {
+			// Handler sets only Type and Data, as the examples do.
+			return &pb.CloudEvent{Type: "player.registered", Data: page.GetEvent()}, nil
+		})
+
+	source := triggerBook(t, "player", []byte("0123456789abcdef"), 4, wrapperspb.String("alice"))
+	events, err := router.Handle(source)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 cloud event, got %d", len(events))
+	}
+	event := events[0]
+	if want := "30313233343536373839616263646566-4"; event.GetId() != want {
+		t.Errorf("id = %q, want %q", event.GetId(), want)
+	}
+	if want := "/player/prj-cloudevents"; event.GetSource() != want {
+		t.Errorf("source = %q, want %q", event.GetSource(), want)
+	}
+	if event.GetSpecVersion() != "1.0" {
+		t.Errorf("specversion = %q, want 1.0", event.GetSpecVersion())
+	}
+	if event.GetTime() == nil {
+		t.Error("time not populated from page CreatedAt")
+	}
+	if event.GetType() != "player.registered" {
+		t.Errorf("handler-set type overwritten: %q", event.GetType())
+	}
+}
+
+func TestCloudEventsRouterKeepsHandlerSetAttributes(t *testing.T) {
+	explicit := timestamppb.Now()
+	router := NewCloudEventsRouter("prj-cloudevents", "player").
+		On("StringValue", func(source *pb.EventBook, page *pb.EventPage) (*pb.CloudEvent, error) {
+			return &pb.CloudEvent{
+				Id:          "custom-id",
+				Source:      "/custom",
+				SpecVersion: "1.0.1",
+				Time:        explicit,
+			}, nil
+		})
+
+	events, err := router.Handle(triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.String("x")))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	event := events[0]
+	if event.GetId() != "custom-id" || event.GetSource() != "/custom" ||
+		event.GetSpecVersion() != "1.0.1" || event.GetTime() != explicit {
+		t.Errorf("handler-set attributes overwritten: %+v", event)
+	}
+	if event.GetType() != "StringValue" {
+		t.Errorf("unset type not defaulted from event: %q", event.GetType())
+	}
+}
+
+func TestCloudEventsRouterNilReturnSkipsPublication(t *testing.T) {
+	router := NewCloudEventsRouter("prj-cloudevents", "player").
+		On("Int32Value", func(source *pb.EventBook, page *pb.EventPage) (*pb.CloudEvent, error) {
+			var amount wrapperspb.Int32Value
+			if err := page.GetEvent().UnmarshalTo(&amount); err != nil {
+				return nil, err
+			}
+			if amount.Value < 100 {
+				return nil, nil
+			}
+			return &pb.CloudEvent{Type: "player.deposited", Data: page.GetEvent()}, nil
+		})
+
+	small := triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.Int32(5))
+	events, err := router.Handle(small)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("nil return still published %d events", len(events))
+	}
+
+	large := triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.Int32(500))
+	if events, err = router.Handle(large); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+}
+
+func TestCloudEventsRouterFilterPreemptsHandlers(t *testing.T) {
+	var handled int
+	router := NewCloudEventsRouter("prj-cloudevents", "player").
+		Filter(func(event proto.Message) bool {
+			amount, ok := event.(*wrapperspb.Int32Value)
+			return !ok || amount.Value >= 100
+		}).
+		On("Int32Value", func(source *pb.EventBook, page *pb.EventPage) (*pb.CloudEvent, error) {
+			handled++
+			return &pb.CloudEvent{Type: "player.deposited", Data: page.GetEvent()}, nil
+		})
+
+	if _, err := router.Handle(triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.Int32(5))); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if handled != 0 {
+		t.Error("filtered page reached the handler")
+	}
+
+	events, err := router.Handle(triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.Int32(500)))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if handled != 1 || len(events) != 1 {
+		t.Errorf("handled = %d, events = %d; want 1 and 1", handled, len(events))
+	}
+}
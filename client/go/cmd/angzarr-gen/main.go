@@ -0,0 +1,572 @@
+// Command angzarr-gen generates angzarr wiring code from annotated Go
+// source.  It scans a file for a struct whose methods carry annotations in
+// their doc comments:
+//
+//	// @handler RegisterPlayer        — aggregate command handler
+//	// @reacts PotAwarded domain=hand — saga event reaction
+//	// @rejected JoinTable domain=table — rejection handler
+//	// @projects PlayerRegistered domain=player — projector handler
+//
+// and emits a _gen.go file wiring the annotated methods into the matching
+// router for the selected component type.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// MethodInfo describes one annotated method.
+type MethodInfo struct {
+	MethodName string
+	// TypeName is the command/event short type name from the annotation.
+	TypeName string
+	// GoType is the concrete Go type of the method's payload parameter
+	// (e.g. examples.RegisterPlayer), captured from the signature so the
+	// generated wrapper can unmarshal into it.
+	GoType string
+	// Domain qualifies @reacts/@rejected/@projects annotations.
+	Domain string
+	// Params and Results are the printed parameter and result types of
+	// the annotated method, used to validate the signature matches what
+	// the generated wrapper will call.
+	Params  []string
+	Results []string
+	File    string
+	Line    int
+}
+
+// TypeInfo describes the annotated struct and everything the templates
+// need to wire it up.
+type TypeInfo struct {
+	Package      string
+	Name         string
+	Handlers     []MethodInfo
+	Reactions    []MethodInfo
+	Rejections   []MethodInfo
+	Projections  []MethodInfo
+	InputDomains map[string][]string
+	// PayloadImports are the input file's imports that the payload types
+	// referenced from generated wrappers resolve through; Go imports are
+	// per file, so the generated file must repeat them.
+	PayloadImports []ImportSpec
+}
+
+// ImportSpec is one import the generated file needs for a payload type.
+type ImportSpec struct {
+	// Name is the import's local name, empty for the default.
+	Name string
+	Path string
+}
+
+func main() {
+	var (
+		input     = flag.String("input", "", "annotated Go source file to scan")
+		output    = flag.String("output", "", "generated file to write (default <input>_gen.go)")
+		component = flag.String("component", "aggregate", "component type: aggregate, saga, or projector")
+		domain    = flag.String("domain", "", "aggregate/saga domain")
+		dryRun    = flag.Bool("dry-run", false, "print generated code to stdout instead of writing the output file")
+		check     = flag.Bool("check", false, "exit non-zero if the output file differs from the generated code, writing nothing")
+		module    = flag.String("module", "", "client library import path for generated code (default: detect from the nearest go.mod, falling back to "+defaultModule+")")
+	)
+	flag.Parse()
+
+	if *input == "" {
+		fmt.Fprintln(os.Stderr, "angzarr-gen: -input is required")
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = strings.TrimSuffix(*input, ".go") + "_gen.go"
+	}
+
+	info, err := parseFile(*input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "angzarr-gen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := validateMethods(info); err != nil {
+		fmt.Fprintf(os.Stderr, "angzarr-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *module == "" {
+		*module = detectModule(*input)
+	}
+
+	code, err := generate(*component, *domain, *module, info)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "angzarr-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case *dryRun:
+		// Preview mode: print to stdout so CI and humans can diff the
+		// would-be output without touching the tree.
+		os.Stdout.Write(code)
+	case *check:
+		existing, err := os.ReadFile(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "angzarr-gen: -check: %v\n", err)
+			os.Exit(1)
+		}
+		if !bytes.Equal(existing, code) {
+			fmt.Fprintf(os.Stderr, "angzarr-gen: %s is out of date; re-run angzarr-gen\n", *output)
+			os.Exit(1)
+		}
+	default:
+		if err := os.WriteFile(*output, code, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "angzarr-gen: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// defaultModule is the client library import path used when no -module
+// flag is given and no go.mod is found.
+const defaultModule = "github.com/benjaminabbitt/angzarr/client/go"
+
+// detectModule walks up from the input file looking for a go.mod and
+// returns its module path, falling back to defaultModule.  Generated code
+// must import the angzarr library from wherever the surrounding module
+// vendors it, not a hardcoded path.
+func detectModule(input string) string {
+	dir, err := filepath.Abs(filepath.Dir(input))
+	if err != nil {
+		return defaultModule
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if path, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+					return strings.TrimSpace(path)
+				}
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return defaultModule
+		}
+		dir = parent
+	}
+}
+
+// parseFile scans the input file for annotated methods, returning the
+// collected type information.
+func parseFile(path string) (*TypeInfo, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	info := &TypeInfo{
+		Package:      file.Name.Name,
+		InputDomains: make(map[string][]string),
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Doc == nil {
+			continue
+		}
+		for _, comment := range fn.Doc.List {
+			parseAnnotation(info, fset, fn, comment.Text)
+		}
+	}
+	if info.Name == "" {
+		return nil, fmt.Errorf("%s: no annotated methods found", path)
+	}
+	info.PayloadImports = payloadImports(file, info)
+	return info, nil
+}
+
+// payloadImports resolves the package qualifiers of the collected payload
+// types against the input file's imports, returning the imports the
+// generated file must repeat, sorted by path.
+func payloadImports(file *ast.File, info *TypeInfo) []ImportSpec {
+	qualifiers := make(map[string]bool)
+	for _, methods := range [][]MethodInfo{info.Handlers, info.Reactions, info.Projections} {
+		for _, m := range methods {
+			if qual, _, ok := strings.Cut(m.GoType, "."); ok {
+				qualifiers[qual] = true
+			}
+		}
+	}
+	var out []ImportSpec
+	for _, spec := range file.Imports {
+		path := strings.Trim(spec.Path.Value, `"`)
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		local := name
+		if local == "" {
+			local = path[strings.LastIndex(path, "/")+1:]
+		}
+		if qualifiers[local] {
+			out = append(out, ImportSpec{Name: name, Path: path})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// parseAnnotation records a single annotation line against the type info.
+func parseAnnotation(info *TypeInfo, fset *token.FileSet, fn *ast.FuncDecl, text string) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(text), "//"))
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "@") {
+		return
+	}
+	method := MethodInfo{
+		MethodName: fn.Name.Name,
+		TypeName:   fields[1],
+		GoType:     payloadType(fn),
+		Params:     fieldTypes(fn.Type.Params),
+		Results:    fieldTypes(fn.Type.Results),
+		File:       fset.Position(fn.Pos()).Filename,
+		Line:       fset.Position(fn.Pos()).Line,
+	}
+	for _, field := range fields[2:] {
+		if strings.HasPrefix(field, "domain=") {
+			method.Domain = strings.TrimPrefix(field, "domain=")
+		}
+	}
+	info.Name = receiverName(fn)
+	switch fields[0] {
+	case "@handler":
+		info.Handlers = append(info.Handlers, method)
+	case "@reacts":
+		info.Reactions = append(info.Reactions, method)
+		info.addInput(method)
+	case "@rejected":
+		info.Rejections = append(info.Rejections, method)
+	case "@projects":
+		info.Projections = append(info.Projections, method)
+		info.addInput(method)
+	}
+}
+
+// addInput records the domain subscription implied by an annotation.
+func (i *TypeInfo) addInput(method MethodInfo) {
+	if method.Domain == "" {
+		return
+	}
+	i.InputDomains[method.Domain] = append(i.InputDomains[method.Domain], method.TypeName)
+}
+
+// receiverName returns the name of the method's receiver type.
+func receiverName(fn *ast.FuncDecl) string {
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	return types.ExprString(expr)
+}
+
+// payloadType returns the concrete type of the method's payload parameter
+// (the second parameter after any leading context.Context by convention,
+// or the only parameter for single-argument projector methods), with any
+// pointer stripped, so the generated wrapper can declare a value of it and
+// unmarshal into it.
+func payloadType(fn *ast.FuncDecl) string {
+	params := fn.Type.Params.List
+	if len(params) > 0 && types.ExprString(params[0].Type) == "context.Context" {
+		params = params[1:]
+	}
+	var expr ast.Expr
+	switch {
+	case len(params) >= 2:
+		expr = params[1].Type
+	case len(params) == 1:
+		expr = params[0].Type
+	default:
+		return ""
+	}
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	return types.ExprString(expr)
+}
+
+// fieldTypes prints the types of a parameter or result list, one entry per
+// declared name (so `a, b *T` yields two entries).
+func fieldTypes(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+	var out []string
+	for _, field := range fields.List {
+		text := types.ExprString(field.Type)
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			out = append(out, text)
+		}
+	}
+	return out
+}
+
+// signatureError describes one annotated method whose signature the
+// generated wrapper could not call.
+func signatureError(m MethodInfo, annotation, expected string) string {
+	return fmt.Sprintf("%s:%d: %s %s on %s: expected signature %s, got (%s) (%s)",
+		m.File, m.Line, annotation, m.TypeName, m.MethodName, expected,
+		strings.Join(m.Params, ", "), strings.Join(m.Results, ", "))
+}
+
+// endsWith reports whether the printed type ends with the given base name,
+// tolerating package qualifiers and pointers.
+func endsWith(printed, base string) bool {
+	return strings.HasSuffix(printed, base)
+}
+
+// validateMethods checks every annotated method's signature against the
+// shape its generated wrapper will call, returning one error listing all
+// mismatches so a typo is reported here rather than as a compile failure
+// far from its source.
+func validateMethods(info *TypeInfo) error {
+	var problems []string
+	for _, m := range info.Handlers {
+		if len(m.Params) != 5 || m.Params[0] != "context.Context" || !endsWith(m.Params[1], "CommandBook") || m.GoType == "" ||
+			len(m.Results) != 2 || !endsWith(m.Results[0], "EventBook") || m.Results[1] != "error" {
+			problems = append(problems, signatureError(m, "@handler",
+				"(ctx context.Context, cb *pb.CommandBook, cmd *T, state any, seq uint32) (*pb.EventBook, error)"))
+		}
+	}
+	for _, m := range info.Reactions {
+		if len(m.Params) != 4 || m.Params[0] != "context.Context" || !endsWith(m.Params[1], "EventBook") || m.GoType == "" ||
+			len(m.Results) != 2 || !endsWith(m.Results[0], "CommandBook") || m.Results[1] != "error" {
+			problems = append(problems, signatureError(m, "@reacts",
+				"(ctx context.Context, source *pb.EventBook, event *T, destinations []*pb.EventBook) ([]*pb.CommandBook, error)"))
+		}
+	}
+	for _, m := range info.Rejections {
+		if len(m.Params) != 2 || !endsWith(m.Params[0], "Notification") ||
+			len(m.Results) != 2 || !endsWith(m.Results[0], "BusinessResponse") || m.Results[1] != "error" {
+			problems = append(problems, signatureError(m, "@rejected",
+				"(notification *pb.Notification, state any) (*pb.BusinessResponse, error)"))
+		}
+	}
+	for _, m := range info.Projections {
+		if len(m.Params) != 1 || !strings.HasPrefix(m.Params[0], "*") || len(m.Results) != 0 {
+			problems = append(problems, signatureError(m, "@projects", "(event *T)"))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("annotation/signature mismatches:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// templateData is what the templates render from.
+type templateData struct {
+	*TypeInfo
+	Domain string
+	Module string
+}
+
+// DomainEvents groups the event types subscribed from one domain.
+type DomainEvents struct {
+	Domain string
+	Events []string
+}
+
+// Inputs returns the InputDomains subscriptions grouped per domain in
+// deterministic (sorted) domain order, for template iteration.
+func (d templateData) Inputs() []DomainEvents {
+	domains := make([]string, 0, len(d.InputDomains))
+	for domain := range d.InputDomains {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	inputs := make([]DomainEvents, 0, len(domains))
+	for _, domain := range domains {
+		inputs = append(inputs, DomainEvents{Domain: domain, Events: d.InputDomains[domain]})
+	}
+	return inputs
+}
+
+// generate renders the template for the selected component type.
+func generate(component, domain, module string, info *TypeInfo) ([]byte, error) {
+	var tmpl *template.Template
+	switch component {
+	case "aggregate":
+		tmpl = aggregateTemplate
+	case "saga":
+		tmpl = sagaTemplate
+	case "projector":
+		tmpl = projectorTemplate
+	case "staterouter":
+		tmpl = stateRouterTemplate
+	default:
+		return nil, fmt.Errorf("unknown component type %q", component)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData{TypeInfo: info, Domain: domain, Module: module}); err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, buf.String())
+	}
+	return code, nil
+}
+
+var aggregateTemplate = template.Must(template.New("aggregate").Parse(`// Code generated by angzarr-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	angzarr "{{.Module}}"
+	pb "{{.Module}}/proto/angzarr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+{{- range .PayloadImports}}
+	{{if .Name}}{{.Name}} {{end}}"{{.Path}}"
+{{- end}}
+)
+
+// NewRouter wires the annotated handlers of {{.Name}} into a CommandRouter.
+func NewRouter(agg *{{.Name}}) *angzarr.CommandRouter {
+	return angzarr.NewCommandRouter("{{.Domain}}").
+{{- range .Handlers}}
+		On("{{.TypeName}}", wrap{{.MethodName}}(agg)).
+{{- end}}
+{{- range .Rejections}}
+		OnRejected("{{.Domain}}", "{{.TypeName}}", wrap{{.MethodName}}(agg)).
+{{- end}}
+		WithRebuild(func(events *pb.EventBook) any { return agg.RebuildState(events) })
+}
+{{range .Handlers}}
+func wrap{{.MethodName}}(agg *{{$.Name}}) angzarr.CommandHandler {
+	return func(ctx context.Context, cb *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+		var c {{.GoType}}
+		if err := cmd.UnmarshalTo(&c); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unmarshaling {{.TypeName}}: %v", err)
+		}
+		return agg.{{.MethodName}}(ctx, cb, &c, state, seq)
+	}
+}
+{{end}}
+{{- range .Rejections}}
+func wrap{{.MethodName}}(agg *{{$.Name}}) angzarr.RejectionHandler {
+	return func(notification *pb.Notification, state any) (*pb.BusinessResponse, error) {
+		return agg.{{.MethodName}}(notification, state)
+	}
+}
+{{end}}`))
+
+var sagaTemplate = template.Must(template.New("saga").Parse(`// Code generated by angzarr-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	angzarr "{{.Module}}"
+	pb "{{.Module}}/proto/angzarr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+{{- range .PayloadImports}}
+	{{if .Name}}{{.Name}} {{end}}"{{.Path}}"
+{{- end}}
+)
+
+// NewRouter wires the annotated reactions of {{.Name}} into an EventRouter.
+func NewRouter(saga *{{.Name}}) *angzarr.EventRouter {
+	r := angzarr.NewEventRouter("{{.Name}}").Domain("{{.Domain}}")
+{{- range .Inputs}}
+	r.Subscribes("{{.Domain}}"{{range .Events}}, "{{.}}"{{end}})
+{{- end}}
+{{- range .Reactions}}
+	r.On("{{.TypeName}}", wrap{{.MethodName}}(saga))
+{{- end}}
+	return r
+}
+{{range .Reactions}}
+func wrap{{.MethodName}}(saga *{{$.Name}}) angzarr.EventHandler {
+	return func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+		var e {{.GoType}}
+		if err := event.UnmarshalTo(&e); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unmarshaling {{.TypeName}}: %v", err)
+		}
+		return saga.{{.MethodName}}(ctx, source, &e, destinations)
+	}
+}
+{{end}}`))
+
+var projectorTemplate = template.Must(template.New("projector").Parse(`// Code generated by angzarr-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	angzarr "{{.Module}}"
+)
+
+// NewRouter wires the annotated projections of {{.Name}} into a StateRouter.
+func NewRouter(proj *{{.Name}}) *angzarr.StateRouter {
+	r := angzarr.NewStateRouter("{{.Name}}")
+{{- range .Inputs}}
+	r.Subscribes("{{.Domain}}"{{range .Events}}, "{{.}}"{{end}})
+{{- end}}
+{{- range .Projections}}
+	r.On("{{.TypeName}}", proj.{{.MethodName}})
+{{- end}}
+	return r
+}
+`))
+
+// stateRouterTemplate wires @projects methods through explicit typed
+// wrappers that unmarshal into the concrete event type each method takes,
+// unlike the projector template which leans on StateRouter's reflective On.
+var stateRouterTemplate = template.Must(template.New("staterouter").Parse(`// Code generated by angzarr-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	angzarr "{{.Module}}"
+	"google.golang.org/protobuf/types/known/anypb"
+{{- range .PayloadImports}}
+	{{if .Name}}{{.Name}} {{end}}"{{.Path}}"
+{{- end}}
+)
+
+// NewRouter wires the annotated projections of {{.Name}} into a StateRouter.
+func NewRouter(proj *{{.Name}}) *angzarr.StateRouter {
+	r := angzarr.NewStateRouter("{{.Name}}")
+{{- range .Inputs}}
+	r.Subscribes("{{.Domain}}"{{range .Events}}, "{{.}}"{{end}})
+{{- end}}
+{{- range .Projections}}
+	r.OnRaw("{{.TypeName}}", wrap{{.MethodName}}(proj))
+{{- end}}
+	return r
+}
+{{range .Projections}}
+func wrap{{.MethodName}}(proj *{{$.Name}}) func(*anypb.Any) {
+	return func(event *anypb.Any) {
+		var e {{.GoType}}
+		if err := event.UnmarshalTo(&e); err != nil {
+			return
+		}
+		proj.{{.MethodName}}(&e)
+	}
+}
+{{end}}`))
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestProjectorGoldenMultiDomain verifies the projector template wires the
+// InputDomains subscriptions gathered from @projects annotations, grouped
+// per domain, against a golden file.
+func TestProjectorGoldenMultiDomain(t *testing.T) {
+	info, err := parseFile("testdata/multi_projector.go")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if err := validateMethods(info); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	code, err := generate("projector", "", defaultModule, info)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	want, err := os.ReadFile("testdata/multi_projector.golden")
+	if err != nil {
+		t.Fatalf("reading golden: %v", err)
+	}
+	if !bytes.Equal(code, want) {
+		t.Errorf("generated code does not match golden file\n--- got ---\n%s\n--- want ---\n%s", code, want)
+	}
+}
+
+// TestGeneratedImportsUseModulePath verifies the templates emit imports
+// under the configured module path rather than the old hardcoded
+// github.com/angzarr location that never resolved.
+func TestGeneratedImportsUseModulePath(t *testing.T) {
+	info, err := parseFile("testdata/multi_projector.go")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	code, err := generate("projector", "", defaultModule, info)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if !bytes.Contains(code, []byte(`angzarr "github.com/benjaminabbitt/angzarr/client/go"`)) {
+		t.Errorf("generated code does not import the client library module path:\n%s", code)
+	}
+	if bytes.Contains(code, []byte("github.com/angzarr/")) {
+		t.Errorf("generated code still references the old import path:\n%s", code)
+	}
+}
+
+func TestDetectModuleFallsBack(t *testing.T) {
+	// testdata has no go.mod anywhere above it in this repository layout
+	// that differs from the default, so detection must not return "".
+	if got := detectModule("testdata/multi_projector.go"); got == "" {
+		t.Error("detectModule returned empty module path")
+	}
+}
+
+func TestInputDomainsGrouping(t *testing.T) {
+	info, err := parseFile("testdata/multi_projector.go")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := len(info.InputDomains["player"]); got != 2 {
+		t.Errorf("player subscriptions = %d, want 2", got)
+	}
+	if got := len(info.InputDomains["table"]); got != 1 {
+		t.Errorf("table subscriptions = %d, want 1", got)
+	}
+}
+
+// golden generates code for one testdata input and compares it against the
+// committed golden file.
+func golden(t *testing.T, input, component, domain, goldenFile string) []byte {
+	t.Helper()
+	info, err := parseFile(input)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if err := validateMethods(info); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	code, err := generate(component, domain, defaultModule, info)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("reading golden: %v", err)
+	}
+	if !bytes.Equal(code, want) {
+		t.Errorf("generated code does not match golden file\n--- got ---\n%s\n--- want ---\n%s", code, want)
+	}
+	return code
+}
+
+// TestAggregateGolden verifies the aggregate template against its golden
+// file, in particular that wrap closures carry the ctx-first
+// CommandHandler signature and repeat the payload type's import.
+func TestAggregateGolden(t *testing.T) {
+	golden(t, "testdata/aggregate.go", "aggregate", "player", "testdata/aggregate.golden")
+}
+
+// TestSagaGolden is the saga-template counterpart: ctx-first EventHandler
+// closures plus the Subscribes wiring from @reacts domains.
+func TestSagaGolden(t *testing.T) {
+	golden(t, "testdata/saga.go", "saga", "hand", "testdata/saga.golden")
+}
+
+// TestGeneratedCodeCompiles builds each testdata sample together with its
+// generated output, so a template drifting from the router handler
+// signatures fails here instead of in downstream projects.
+func TestGeneratedCodeCompiles(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not on PATH")
+	}
+	for _, tc := range []struct{ sample, component, domain string }{
+		{"testdata/aggregate.go", "aggregate", "player"},
+		{"testdata/saga.go", "saga", "hand"},
+	} {
+		t.Run(tc.component, func(t *testing.T) {
+			info, err := parseFile(tc.sample)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			code, err := generate(tc.component, tc.domain, defaultModule, info)
+			if err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			// The build dir must live inside the module for imports of the
+			// client library to resolve.
+			dir, err := os.MkdirTemp(".", "compile")
+			if err != nil {
+				t.Fatalf("temp dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			sample, err := os.ReadFile(tc.sample)
+			if err != nil {
+				t.Fatalf("reading sample: %v", err)
+			}
+			for name, content := range map[string][]byte{"sample.go": sample, "sample_gen.go": code} {
+				if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+					t.Fatalf("writing %s: %v", name, err)
+				}
+			}
+			cmd := exec.Command(goTool, "build", "./"+dir)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("generated %s code does not compile: %v\n%s", tc.component, err, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package testdata
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// PlayerAgg is an annotated aggregate.  Wrapper well-known types stand in
+// for domain commands.
+type PlayerAgg struct{}
+
+// HandleRegister registers a player.
+// @handler StringValue
+func (a *PlayerAgg) HandleRegister(ctx context.Context, cb *pb.CommandBook, cmd *wrapperspb.StringValue, state any, seq uint32) (*pb.EventBook, error) {
+	return nil, nil
+}
+
+// HandleJoinRejected compensates a rejected table join.
+// @rejected Int32Value domain=table
+func (a *PlayerAgg) HandleJoinRejected(notification *pb.Notification, state any) (*pb.BusinessResponse, error) {
+	return &pb.BusinessResponse{}, nil
+}
+
+// RebuildState folds prior events into aggregate state.
+func (a *PlayerAgg) RebuildState(events *pb.EventBook) any { return nil }
@@ -0,0 +1,21 @@
+package testdata
+
+import (
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// MultiProjector projects events from the player and table domains.
+// Wrapper well-known types stand in for domain events.
+type MultiProjector struct{}
+
+// HandlePlayerRegistered records a player registration.
+// @projects StringValue domain=player
+func (p *MultiProjector) HandlePlayerRegistered(event *wrapperspb.StringValue) {}
+
+// HandleTableCreated records a table creation.
+// @projects Int32Value domain=table
+func (p *MultiProjector) HandleTableCreated(event *wrapperspb.Int32Value) {}
+
+// HandleFundsDeposited records a deposit.
+// @projects BoolValue domain=player
+func (p *MultiProjector) HandleFundsDeposited(event *wrapperspb.BoolValue) {}
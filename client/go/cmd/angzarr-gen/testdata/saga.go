@@ -0,0 +1,19 @@
+package testdata
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// CardSaga is an annotated saga.  Wrapper well-known types stand in for
+// domain events.
+type CardSaga struct{}
+
+// ReactPotAwarded reacts to a pot award.
+// @reacts Int32Value domain=hand
+func (s *CardSaga) ReactPotAwarded(ctx context.Context, source *pb.EventBook, event *wrapperspb.Int32Value, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+	return nil, nil
+}
@@ -0,0 +1,68 @@
+package angzarr
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// CommandBuilder assembles a single-command book with the cover, payload
+// oneof, and sequence set consistently, replacing the hand-construction
+// that differed from saga to saga:
+//
+//	book, err := angzarr.NewCommand("player", winnerRoot).
+//		WithSequence(index.NextSequence(winnerRoot)).
+//		Payload(&examples.DepositFunds{Amount: total})
+//
+// Payload validates the root and packs the message, so a malformed book
+// fails at build time rather than at the destination aggregate.
+type CommandBuilder struct {
+	cover *pb.Cover
+	seq   uint32
+}
+
+// NewCommand starts a command book against the aggregate at domain/root.
+func NewCommand(domain string, root []byte) *CommandBuilder {
+	return &CommandBuilder{
+		cover: &pb.Cover{Domain: domain, Root: &pb.UUID{Value: root}},
+	}
+}
+
+// WithCorrelation stamps the correlation id linking this command to the
+// flow that caused it.
+func (b *CommandBuilder) WithCorrelation(id string) *CommandBuilder {
+	b.cover.CorrelationId = id
+	return b
+}
+
+// WithSequence sets the command page's destination sequence.  Sagas can
+// leave it at 0 and let Handle assign sequences from the destination
+// books (see EventRouter.Handle).
+func (b *CommandBuilder) WithSequence(seq uint32) *CommandBuilder {
+	b.seq = seq
+	return b
+}
+
+// Payload packs msg into the book's single command page and returns the
+// finished book.  It fails when the root is not 16 bytes or the message
+// cannot be packed.
+func (b *CommandBuilder) Payload(msg proto.Message) (*pb.CommandBook, error) {
+	if root := b.cover.GetRoot().GetValue(); len(root) != 16 {
+		return nil, fmt.Errorf("command against %q: root has %d bytes, expected 16",
+			b.cover.GetDomain(), len(root))
+	}
+	packed, err := anypb.New(msg)
+	if err != nil {
+		return nil, fmt.Errorf("command against %q: %v", b.cover.GetDomain(), err)
+	}
+	return &pb.CommandBook{
+		Cover: b.cover,
+		Pages: []*pb.CommandPage{{
+			Sequence: b.seq,
+			Payload:  &pb.CommandPage_Command{Command: packed},
+		}},
+	}, nil
+}
@@ -0,0 +1,39 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewCommandBuildsSinglePageBook(t *testing.T) {
+	book, err := NewCommand("player", testRoot().GetValue()).
+		WithCorrelation("corr-1").
+		WithSequence(4).
+		Payload(wrapperspb.String("deposit"))
+	if err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if book.GetCover().GetDomain() != "player" {
+		t.Errorf("domain = %q", book.GetCover().GetDomain())
+	}
+	if book.GetCover().GetCorrelationId() != "corr-1" {
+		t.Errorf("correlation = %q", book.GetCover().GetCorrelationId())
+	}
+	if len(book.GetPages()) != 1 {
+		t.Fatalf("got %d pages, want 1", len(book.GetPages()))
+	}
+	page := book.GetPages()[0]
+	if page.GetSequence() != 4 {
+		t.Errorf("sequence = %d, want 4", page.GetSequence())
+	}
+	if got := typeNameOf(page.GetCommand().GetTypeUrl()); got != "StringValue" {
+		t.Errorf("payload type = %q, want StringValue", got)
+	}
+}
+
+func TestNewCommandRejectsShortRoot(t *testing.T) {
+	if _, err := NewCommand("player", []byte("short")).Payload(wrapperspb.String("x")); err == nil {
+		t.Error("5-byte root accepted")
+	}
+}
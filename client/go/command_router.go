@@ -0,0 +1,369 @@
+package angzarr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// CommandHandler handles a single command against the aggregate's rebuilt
+// state.  ctx is the inbound request context — long-running logic should
+// watch it for cancellation when the caller disconnects.  cmdBook is the
+// full inbound book, cmd the packed command payload, state the value
+// produced by the router's rebuild function, and seq the next free event
+// sequence.  It returns the events to append.
+type CommandHandler func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error)
+
+// RejectionHandler reacts to the rejection of a command this aggregate
+// previously issued, returning compensating events and/or a revocation.
+type RejectionHandler func(notification *pb.Notification, state any) (*pb.BusinessResponse, error)
+
+// RebuildFunc folds an aggregate's prior events into its current state.
+type RebuildFunc func(events *pb.EventBook) any
+
+// CommandRouter dispatches inbound commands to handlers registered by
+// command type name, rebuilding aggregate state first.  Build one with
+// NewCommandRouter and register handlers with On.
+type CommandRouter struct {
+	domain            string
+	rebuild           RebuildFunc
+	streamRebuild     func() StreamingRebuild
+	handlers          map[string]CommandHandler
+	rejectionHandlers map[string]RejectionHandler
+	schemaVersion     int32
+	emits             map[string]bool
+	permissiveEmits   bool
+	defaultRejection  RejectionHandler
+	upcaster          *Upcaster
+	tracer            trace.Tracer
+	metrics           *Metrics
+}
+
+// NewCommandRouter creates a command router for the given aggregate domain.
+func NewCommandRouter(domain string) *CommandRouter {
+	return &CommandRouter{
+		domain:            domain,
+		handlers:          make(map[string]CommandHandler),
+		rejectionHandlers: make(map[string]RejectionHandler),
+	}
+}
+
+// Domain returns the aggregate domain the router serves.
+func (r *CommandRouter) Domain() string {
+	return r.domain
+}
+
+// WithRebuild registers the function that folds prior events into state
+// before each dispatch.  Without one, handlers receive a nil state.
+func (r *CommandRouter) WithRebuild(rebuild RebuildFunc) *CommandRouter {
+	r.rebuild = rebuild
+	return r
+}
+
+// WithUpcaster rewrites the prior events to their latest schema shapes
+// before rebuild and dispatch, so the rebuild function and handlers only
+// ever see current event formats; see Upcaster.
+func (r *CommandRouter) WithUpcaster(upcaster *Upcaster) *CommandRouter {
+	r.upcaster = upcaster
+	return r
+}
+
+// WithEventSchemaVersion stamps every emitted event page with the given
+// schema/contract version so consumers can detect and upcast older event
+// shapes.  Pages a handler already stamped explicitly are left untouched.
+// Servers built from a ServerConfig apply the config's SchemaVersion here.
+func (r *CommandRouter) WithEventSchemaVersion(version int32) *CommandRouter {
+	r.schemaVersion = version
+	return r
+}
+
+// Emits declares the event types this aggregate may emit.  Once any are
+// declared, every emitted event is validated against the set and a handler
+// emitting a foreign or unlisted type fails with codes.Internal — an
+// aggregate emitting outside its own contract is a bug, not bad input.
+// Repeated calls merge.
+func (r *CommandRouter) Emits(events ...string) *CommandRouter {
+	if r.emits == nil {
+		r.emits = make(map[string]bool)
+	}
+	for _, event := range events {
+		r.emits[event] = true
+	}
+	return r
+}
+
+// WithPermissiveEmits keeps the Emits declarations for introspection but
+// disables the rejection of undeclared emitted events, for services mid-way
+// through adopting the allowlist.
+func (r *CommandRouter) WithPermissiveEmits() *CommandRouter {
+	r.permissiveEmits = true
+	return r
+}
+
+// validateEmitted enforces the Emits allowlist against emitted events.
+func (r *CommandRouter) validateEmitted(events *pb.EventBook) error {
+	if r.emits == nil || r.permissiveEmits {
+		return nil
+	}
+	for _, page := range events.GetPages() {
+		if page.GetEvent() == nil {
+			continue
+		}
+		name := typeNameOf(page.GetEvent().GetTypeUrl())
+		if !r.emits[name] {
+			return status.Errorf(codes.Internal,
+				"aggregate %q emitted undeclared event type %q", r.domain, name)
+		}
+	}
+	return nil
+}
+
+// On registers a handler for a command type.  Two matching modes are
+// supported: a short type name (e.g. "RegisterPlayer", as returned by Name)
+// matches any command whose URL's final segment equals it, while a full
+// type URL (as returned by TypeURL, containing '/') matches that exact URL.
+// Full-URL registration is immune to short-name collisions across packages;
+// dispatch tries the full URL first, then the short name.
+func (r *CommandRouter) On(name string, handler CommandHandler) *CommandRouter {
+	r.handlers[name] = handler
+	return r
+}
+
+// OnRaw registers a handler receiving the command's packed bytes without
+// unmarshaling, for routers that pass payloads through opaquely — the
+// C-shared-library bindings copy command bytes straight into an event and
+// never hold the Go type.  The handler is otherwise a CommandHandler:
+// same state, sequence, and emitted-book contract.
+func (r *CommandRouter) OnRaw(name string, handler func(cmdBook *pb.CommandBook, raw []byte, state any, seq uint32) (*pb.EventBook, error)) *CommandRouter {
+	return r.On(name, func(_ context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+		return handler(cmdBook, cmd.GetValue(), state, seq)
+	})
+}
+
+// GuardFunc checks an aggregate's preconditions against its rebuilt state
+// before any business logic runs.  A plain error is surfaced as
+// FailedPrecondition; return a CommandError for a different code.
+type GuardFunc func(state any) error
+
+// OnStaged registers a handler decomposed into guard and compute phases:
+// the guard checks preconditions against the rebuilt state, and only when
+// it passes does compute run the business logic with the command already
+// unmarshaled.  Compute returns plain event messages; the router packs
+// them into a book on the command's cover at contiguous sequences.  A nil
+// guard skips straight to compute.  Like OnTyped, it is a package-level
+// function only because Go methods cannot be generic:
+//
+//	angzarr.OnStaged(router, "ApplyDiscount",
+//		guardOrderOpen,
+//		func(ctx context.Context, cmd *examples.ApplyDiscount, state any) ([]proto.Message, error) {
+//			...
+//		})
+//
+// Splitting the phases keeps validation rules testable in isolation from
+// the event-producing logic.
+func OnStaged[T any, PT interface {
+	*T
+	proto.Message
+}](r *CommandRouter, name string, guard GuardFunc, compute func(ctx context.Context, cmd PT, state any) ([]proto.Message, error)) *CommandRouter {
+	return r.On(name, func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+		typed := PT(new(T))
+		if err := cmd.UnmarshalTo(typed); err != nil {
+			return nil, err
+		}
+		if guard != nil {
+			if err := guard(state); err != nil {
+				var cmdErr *CommandError
+				if errors.As(err, &cmdErr) {
+					return nil, err
+				}
+				return nil, FailedPrecondition(err.Error())
+			}
+		}
+		msgs, err := compute(ctx, typed, state)
+		if err != nil {
+			return nil, err
+		}
+		if len(msgs) == 0 {
+			return nil, nil
+		}
+		events := &pb.EventBook{Cover: cmdBook.GetCover()}
+		for i, msg := range msgs {
+			packed, err := anypb.New(msg)
+			if err != nil {
+				return nil, err
+			}
+			events.Pages = append(events.Pages, &pb.EventPage{
+				Sequence:  &pb.EventPage_Num{Num: seq + uint32(i)},
+				CreatedAt: timestamppb.Now(),
+				Event:     packed,
+			})
+		}
+		return events, nil
+	})
+}
+
+// lookup finds the handler for a command's type URL, trying an exact
+// full-URL registration before the short-name registration.
+func (r *CommandRouter) lookup(typeURL string) (CommandHandler, bool) {
+	if handler, ok := r.handlers[typeURL]; ok {
+		return handler, true
+	}
+	handler, ok := r.handlers[typeNameOf(typeURL)]
+	return handler, ok
+}
+
+// RegisteredCommands returns the command type names (or full URLs) with
+// registered handlers, sorted, for runtime topology introspection.
+func (r *CommandRouter) RegisteredCommands() []string {
+	return sortedKeys(r.handlers)
+}
+
+// RegisteredRejections returns the domain/command pairs with registered
+// rejection handlers, sorted.
+func (r *CommandRouter) RegisteredRejections() []string {
+	return sortedKeys(r.rejectionHandlers)
+}
+
+// OnRejected registers a handler invoked when a command this aggregate
+// issued against the given domain is rejected downstream.
+func (r *CommandRouter) OnRejected(domain, command string, handler RejectionHandler) *CommandRouter {
+	r.rejectionHandlers[domain+"/"+command] = handler
+	return r
+}
+
+// Handle rebuilds state from the contextual command's prior events and
+// dispatches every command page, in order, to its registered handler,
+// returning the combined emitted events with the router's schema version
+// stamped on.  Each page sees the events emitted by the pages before it:
+// state is rebuilt over the growing stream and sequences stay monotonic
+// across the whole book.  Dispatch is atomic — if any page fails, no
+// events are returned, so a partially-applied book never reaches the
+// coordinator.  ctx is threaded through to every handler; callers without
+// a request context pass context.Background().
+func (r *CommandRouter) Handle(ctx context.Context, cc *pb.ContextualCommand) (*pb.EventBook, error) {
+	cmdBook := cc.GetCommand()
+	pages := cmdBook.GetPages()
+	if len(pages) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "command book has no pages")
+	}
+
+	working := cc.GetEvents()
+	if r.upcaster != nil {
+		var err error
+		if working, err = r.upcaster.ApplyBook(working); err != nil {
+			return nil, err
+		}
+	}
+	var emitted *pb.EventBook
+	for i, page := range pages {
+		cmd := page.GetCommand()
+		if cmd == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "command page %d has no payload", i)
+		}
+		handler, ok := r.lookup(cmd.GetTypeUrl())
+		if !ok {
+			return nil, status.Errorf(codes.Unimplemented, "unknown command type %q in domain %q",
+				typeNameOf(cmd.GetTypeUrl()), r.domain)
+		}
+		var state any
+		if r.rebuild != nil {
+			state = r.rebuild(working)
+		}
+		seq := NextSequence(working)
+
+		handlerCtx, finishSpan := startDispatchSpan(ctx, r.tracer,
+			r.domain+"/"+typeNameOf(cmd.GetTypeUrl()), cmdBook.GetCover())
+		start := time.Now()
+		events, err := handler(handlerCtx, cmdBook, cmd, state, seq)
+		finishSpan(err)
+		r.metrics.observeCommand(r.domain, typeNameOf(cmd.GetTypeUrl()),
+			len(events.GetPages()), time.Since(start), err)
+		if err != nil {
+			return nil, MapCommandError(err)
+		}
+		if err := r.validateEmitted(events); err != nil {
+			return nil, err
+		}
+		if err := AssertContiguous(events, seq); err != nil {
+			return nil, status.Errorf(codes.Internal,
+				"aggregate %q emitted non-contiguous events: %v", r.domain, err)
+		}
+		working = mergeBooks(working, events)
+		if emitted == nil {
+			emitted = events
+		} else {
+			emitted.Pages = append(emitted.Pages, events.GetPages()...)
+		}
+	}
+	r.stampSchemaVersion(emitted)
+	if emitted != nil {
+		propagateMetadata(emitted.GetCover(), cmdBook.GetCover().GetMetadata())
+	}
+	return emitted, nil
+}
+
+// WithDefaultRejectionHandler replaces the framework default invoked when
+// no OnRejected registration matches a notification's domain and command.
+func (r *CommandRouter) WithDefaultRejectionHandler(handler RejectionHandler) *CommandRouter {
+	r.defaultRejection = handler
+	return r
+}
+
+// HandleRejection dispatches a downstream rejection notification to the
+// handler registered for its domain and command.  When none matches, the
+// default handler runs instead: it logs the notification and acknowledges
+// with an empty response — no compensating events, no revocation — so an
+// unhandled rejection never fails the aggregate.  Override the default
+// with WithDefaultRejectionHandler.
+func (r *CommandRouter) HandleRejection(notification *pb.Notification, state any) (*pb.BusinessResponse, error) {
+	if handler, ok := r.rejectionHandlers[notification.GetDomain()+"/"+notification.GetCommand()]; ok {
+		return handler(notification, state)
+	}
+	if r.defaultRejection != nil {
+		return r.defaultRejection(notification, state)
+	}
+	return defaultRejectionHandler(r.domain, notification)
+}
+
+// defaultRejectionHandler is the framework fallback for notifications with
+// no registered handler.
+func defaultRejectionHandler(domain string, notification *pb.Notification) (*pb.BusinessResponse, error) {
+	if logger, err := zap.NewProduction(); err == nil {
+		defer logger.Sync()
+		logger.Warn("unhandled command rejection",
+			zap.String("aggregate", domain),
+			zap.String("domain", notification.GetDomain()),
+			zap.String("command", notification.GetCommand()),
+			zap.String("reason", notification.GetReason()))
+	}
+	return &pb.BusinessResponse{}, nil
+}
+
+// stampSchemaVersion applies the router's schema version to any emitted
+// page that was not already stamped by its handler.
+func (r *CommandRouter) stampSchemaVersion(events *pb.EventBook) {
+	if r.schemaVersion == 0 {
+		return
+	}
+	for _, page := range events.GetPages() {
+		if page != nil && page.SchemaVersion == 0 {
+			page.SchemaVersion = r.schemaVersion
+		}
+	}
+}
+
+// EventSchemaVersion returns the schema version stamped on an emitted event
+// page, or 0 when the producer did not stamp one.
+func EventSchemaVersion(page *pb.EventPage) int {
+	return int(page.GetSchemaVersion())
+}
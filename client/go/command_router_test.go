@@ -0,0 +1,341 @@
+package angzarr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// commandFor packs msg into a single-page contextual command for domain.
+func commandFor(t *testing.T, domain string, msg proto.Message, prior *pb.EventBook) *pb.ContextualCommand {
+	t.Helper()
+	cmd, err := anypb.New(msg)
+	if err != nil {
+		t.Fatalf("packing command: %v", err)
+	}
+	return &pb.ContextualCommand{
+		Command: &pb.CommandBook{
+			Cover: &pb.Cover{Domain: domain, Root: testRoot()},
+			Pages: []*pb.CommandPage{{Payload: &pb.CommandPage_Command{Command: cmd}}},
+		},
+		Events: prior,
+	}
+}
+
+// echoHandler emits a single event page echoing the command payload.
+func echoHandler(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+	return &pb.EventBook{
+		Cover: cmdBook.GetCover(),
+		Pages: []*pb.EventPage{{
+			Sequence:  &pb.EventPage_Num{Num: seq},
+			CreatedAt: timestamppb.Now(),
+			Event:     cmd,
+		}},
+	}, nil
+}
+
+func TestCommandRouterStampsSchemaVersion(t *testing.T) {
+	router := NewCommandRouter("player").
+		WithEventSchemaVersion(3).
+		On("StringValue", echoHandler)
+
+	events, err := router.Handle(context.Background(), commandFor(t, "player", wrapperspb.String("register"), nil))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(events.GetPages()) != 1 {
+		t.Fatalf("expected 1 emitted page, got %d", len(events.GetPages()))
+	}
+	if got := EventSchemaVersion(events.Pages[0]); got != 3 {
+		t.Errorf("emitted page schema version = %d, want 3", got)
+	}
+}
+
+func TestCommandRouterKeepsExplicitSchemaVersion(t *testing.T) {
+	router := NewCommandRouter("player").
+		WithEventSchemaVersion(3).
+		On("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+			events, err := echoHandler(ctx, cmdBook, cmd, state, seq)
+			if err != nil {
+				return nil, err
+			}
+			events.Pages[0].SchemaVersion = 7
+			return events, nil
+		})
+
+	events, err := router.Handle(context.Background(), commandFor(t, "player", wrapperspb.String("register"), nil))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if got := EventSchemaVersion(events.Pages[0]); got != 7 {
+		t.Errorf("handler-stamped version overwritten: got %d, want 7", got)
+	}
+}
+
+func TestCommandRouterUnstampedWithoutVersion(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	events, err := router.Handle(context.Background(), commandFor(t, "player", wrapperspb.String("register"), nil))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if got := EventSchemaVersion(events.Pages[0]); got != 0 {
+		t.Errorf("unconfigured router stamped version %d, want 0", got)
+	}
+}
+
+func TestCommandRouterFullURLRegistration(t *testing.T) {
+	router := NewCommandRouter("inventory").
+		On(TypeURL(wrapperspb.String("")), echoHandler)
+	if _, err := router.Handle(context.Background(), commandFor(t, "inventory", wrapperspb.String("init"), nil)); err != nil {
+		t.Errorf("full-URL registration did not dispatch: %v", err)
+	}
+	// A different message with the same registration style must not match.
+	if _, err := router.Handle(context.Background(), commandFor(t, "inventory", wrapperspb.Bool(true), nil)); err == nil {
+		t.Error("unregistered type dispatched via full-URL registration")
+	}
+}
+
+func TestCommandRouterEmitsAllowlist(t *testing.T) {
+	// echoHandler emits a StringValue page for a StringValue command.
+	router := NewCommandRouter("player").
+		Emits("StringValue").
+		On("StringValue", echoHandler)
+	if _, err := router.Handle(context.Background(), commandFor(t, "player", wrapperspb.String("ok"), nil)); err != nil {
+		t.Errorf("declared event rejected: %v", err)
+	}
+
+	undeclared := NewCommandRouter("player").
+		Emits("FundsDeposited").
+		On("StringValue", echoHandler)
+	if _, err := undeclared.Handle(context.Background(), commandFor(t, "player", wrapperspb.String("bad"), nil)); err == nil {
+		t.Error("undeclared emitted event not rejected")
+	}
+
+	permissive := NewCommandRouter("player").
+		Emits("FundsDeposited").
+		WithPermissiveEmits().
+		On("StringValue", echoHandler)
+	if _, err := permissive.Handle(context.Background(), commandFor(t, "player", wrapperspb.String("ok"), nil)); err != nil {
+		t.Errorf("permissive mode still rejected: %v", err)
+	}
+}
+
+func TestCommandRouterUnknownCommand(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	if _, err := router.Handle(context.Background(), commandFor(t, "player", wrapperspb.Bool(true), nil)); err == nil {
+		t.Fatal("expected error for unregistered command type")
+	}
+}
+
+func TestCommandRouterDefaultRejectionHandler(t *testing.T) {
+	router := NewCommandRouter("player")
+	notification := &pb.Notification{Domain: "table", Command: "JoinTable", Reason: "table full"}
+
+	// No OnRejected registration: the framework default acknowledges with
+	// an empty response instead of erroring or panicking.
+	resp, err := router.HandleRejection(notification, nil)
+	if err != nil {
+		t.Fatalf("default rejection handler errored: %v", err)
+	}
+	if resp.GetEvents() != nil || resp.GetRevocation() != nil {
+		t.Errorf("default response should be empty, got %v", resp)
+	}
+}
+
+func TestCommandRouterDefaultRejectionOverride(t *testing.T) {
+	called := false
+	router := NewCommandRouter("player").
+		WithDefaultRejectionHandler(func(notification *pb.Notification, state any) (*pb.BusinessResponse, error) {
+			called = true
+			return &pb.BusinessResponse{}, nil
+		})
+
+	if _, err := router.HandleRejection(&pb.Notification{Domain: "table", Command: "JoinTable"}, nil); err != nil {
+		t.Fatalf("override errored: %v", err)
+	}
+	if !called {
+		t.Error("override not invoked for unregistered rejection")
+	}
+}
+
+func TestCommandRouterRegisteredRejectionWinsOverDefault(t *testing.T) {
+	router := NewCommandRouter("player").
+		WithDefaultRejectionHandler(func(*pb.Notification, any) (*pb.BusinessResponse, error) {
+			t.Error("default invoked despite matching registration")
+			return nil, nil
+		}).
+		OnRejected("table", "JoinTable", func(*pb.Notification, any) (*pb.BusinessResponse, error) {
+			return &pb.BusinessResponse{}, nil
+		})
+
+	if _, err := router.HandleRejection(&pb.Notification{Domain: "table", Command: "JoinTable"}, nil); err != nil {
+		t.Fatalf("registered handler errored: %v", err)
+	}
+}
+
+// multiCommandBook packs each msg into its own page of one command book.
+func multiCommandBook(t *testing.T, domain string, msgs ...proto.Message) *pb.ContextualCommand {
+	t.Helper()
+	book := &pb.CommandBook{Cover: &pb.Cover{Domain: domain, Root: testRoot()}}
+	for _, msg := range msgs {
+		cmd, err := anypb.New(msg)
+		if err != nil {
+			t.Fatalf("packing command: %v", err)
+		}
+		book.Pages = append(book.Pages, &pb.CommandPage{Payload: &pb.CommandPage_Command{Command: cmd}})
+	}
+	return &pb.ContextualCommand{Command: book}
+}
+
+func TestCommandRouterDispatchesAllPages(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	events, err := router.Handle(context.Background(), multiCommandBook(t, "player",
+		wrapperspb.String("one"), wrapperspb.String("two"), wrapperspb.String("three")))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(events.GetPages()) != 3 {
+		t.Fatalf("expected 3 emitted pages, got %d", len(events.GetPages()))
+	}
+	// Sequences advance monotonically across pages.
+	for i, page := range events.GetPages() {
+		if got, _ := pageNum(page); got != uint32(i) {
+			t.Errorf("page %d sequence = %d, want %d", i, got, i)
+		}
+	}
+}
+
+func TestCommandRouterBatchIsAtomic(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	// Second page has no handler: the whole book fails, no partial events.
+	events, err := router.Handle(context.Background(), multiCommandBook(t, "player",
+		wrapperspb.String("ok"), wrapperspb.Bool(true)))
+	if err == nil {
+		t.Fatal("expected failure for unhandled second page")
+	}
+	if events != nil {
+		t.Errorf("partial events returned despite failure: %v", events)
+	}
+}
+
+func TestOnStagedGuardRejectsBeforeCompute(t *testing.T) {
+	router := NewCommandRouter("order")
+	OnStaged(router, "StringValue",
+		func(state any) error { return errors.New("order is closed") },
+		func(ctx context.Context, cmd *wrapperspb.StringValue, state any) ([]proto.Message, error) {
+			t.Error("compute ran despite guard rejection")
+			return nil, nil
+		})
+
+	_, err := router.Handle(context.Background(), commandFor(t, "order", wrapperspb.String("discount"), nil))
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("guard rejection code = %v, want FailedPrecondition", status.Code(err))
+	}
+}
+
+func TestOnStagedGuardKeepsCommandErrorCode(t *testing.T) {
+	router := NewCommandRouter("order")
+	OnStaged(router, "StringValue",
+		func(state any) error { return InvalidArgument("bad coupon") },
+		func(ctx context.Context, cmd *wrapperspb.StringValue, state any) ([]proto.Message, error) {
+			return nil, nil
+		})
+
+	_, err := router.Handle(context.Background(), commandFor(t, "order", wrapperspb.String("discount"), nil))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("guard CommandError code = %v, want InvalidArgument", status.Code(err))
+	}
+}
+
+func TestOnStagedComputePacksSequencedEvents(t *testing.T) {
+	router := NewCommandRouter("player")
+	OnStaged(router, "StringValue",
+		nil,
+		func(ctx context.Context, cmd *wrapperspb.StringValue, state any) ([]proto.Message, error) {
+			return []proto.Message{wrapperspb.String(cmd.Value), wrapperspb.Bool(true)}, nil
+		})
+
+	events, err := router.Handle(context.Background(),
+		commandFor(t, "player", wrapperspb.String("register"), testBook(t, 0, 1)))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(events.GetPages()) != 2 {
+		t.Fatalf("expected 2 emitted pages, got %d", len(events.GetPages()))
+	}
+	if got := PageSequence(events.GetPages()[0]); got != 2 {
+		t.Errorf("first emitted sequence = %d, want 2", got)
+	}
+	if got := PageSequence(events.GetPages()[1]); got != 3 {
+		t.Errorf("second emitted sequence = %d, want 3", got)
+	}
+	if events.GetCover().GetDomain() != "player" {
+		t.Errorf("emitted cover domain = %q", events.GetCover().GetDomain())
+	}
+}
+
+func TestOnRawPassesCommandBytesThrough(t *testing.T) {
+	router := NewCommandRouter("orders").
+		OnRaw("StringValue", func(cmdBook *pb.CommandBook, raw []byte, state any, seq uint32) (*pb.EventBook, error) {
+			return &pb.EventBook{
+				Cover: cmdBook.GetCover(),
+				Pages: []*pb.EventPage{{
+					Sequence:  &pb.EventPage_Num{Num: seq},
+					CreatedAt: timestamppb.Now(),
+					Event:     &anypb.Any{TypeUrl: "type.googleapis.com/orders.OrderCreated", Value: raw},
+				}},
+			}, nil
+		})
+
+	events, err := router.Handle(context.Background(), commandFor(t, "orders", wrapperspb.String("order-7"), nil))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	page := events.GetPages()[0]
+	if got := typeNameOf(page.GetEvent().GetTypeUrl()); got != "OrderCreated" {
+		t.Errorf("event type = %q, want OrderCreated", got)
+	}
+	var echoed wrapperspb.StringValue
+	if err := proto.Unmarshal(page.GetEvent().GetValue(), &echoed); err != nil {
+		t.Fatalf("unmarshaling copied bytes: %v", err)
+	}
+	if echoed.Value != "order-7" {
+		t.Errorf("copied payload = %q, want order-7", echoed.Value)
+	}
+}
+
+func TestHandleRejectsNonContiguousEmissions(t *testing.T) {
+	router := NewCommandRouter("transaction").
+		On("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+			// Buggy handler: skips a sequence between its two pages.
+			book, err := echoHandler(ctx, cmdBook, cmd, state, seq)
+			if err != nil {
+				return nil, err
+			}
+			skipped := &pb.EventPage{
+				Sequence:  &pb.EventPage_Num{Num: seq + 2},
+				CreatedAt: timestamppb.Now(),
+				Event:     book.GetPages()[0].GetEvent(),
+			}
+			book.Pages = append(book.Pages, skipped)
+			return book, nil
+		})
+
+	_, err := router.Handle(context.Background(), commandFor(t, "transaction", wrapperspb.String("deposit"), nil))
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("non-contiguous emission code = %v, want Internal", status.Code(err))
+	}
+	if !strings.Contains(err.Error(), "non-contiguous") {
+		t.Errorf("error = %v, want it to mention non-contiguous", err)
+	}
+}
@@ -0,0 +1,47 @@
+package angzarr
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// coverKey uniquely identifies a cover by domain and root for map keys.
+func coverKey(cover *pb.Cover) string {
+	return fmt.Sprintf("%s/%s", cover.GetDomain(), hex.EncodeToString(cover.GetRoot().GetValue()))
+}
+
+// MergeCommands concatenates the given command book slices into one,
+// dropping duplicate pages that target the same cover (domain and root) at
+// the same sequence.  Ordering is stable: books and pages appear in input
+// order, and when duplicates collide the first occurrence wins.  Books whose
+// pages are all duplicates are dropped entirely.  This makes it safe to
+// compose command slices from fan-out sagas and process managers that may
+// independently target the same aggregates.
+func MergeCommands(books ...[]*pb.CommandBook) []*pb.CommandBook {
+	seen := make(map[string]bool)
+	var merged []*pb.CommandBook
+	for _, slice := range books {
+		for _, book := range slice {
+			if book == nil {
+				continue
+			}
+			key := coverKey(book.GetCover())
+			var pages []*pb.CommandPage
+			for _, page := range book.GetPages() {
+				pageKey := fmt.Sprintf("%s@%d", key, page.GetSequence())
+				if seen[pageKey] {
+					continue
+				}
+				seen[pageKey] = true
+				pages = append(pages, page)
+			}
+			if len(pages) == 0 {
+				continue
+			}
+			merged = append(merged, &pb.CommandBook{Cover: book.GetCover(), Pages: pages})
+		}
+	}
+	return merged
+}
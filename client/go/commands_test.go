@@ -0,0 +1,54 @@
+package angzarr
+
+import (
+	"testing"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func testCommandBook(domain string, root []byte, seqs ...uint32) *pb.CommandBook {
+	book := &pb.CommandBook{
+		Cover: &pb.Cover{Domain: domain, Root: &pb.UUID{Value: root}},
+	}
+	for _, seq := range seqs {
+		book.Pages = append(book.Pages, &pb.CommandPage{Sequence: seq})
+	}
+	return book
+}
+
+func TestMergeCommandsDropsDuplicatePages(t *testing.T) {
+	root := []byte("0123456789abcdef")
+	first := testCommandBook("player", root, 3)
+	second := testCommandBook("player", root, 3, 4)
+
+	merged := MergeCommands([]*pb.CommandBook{first}, []*pb.CommandBook{second})
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 books, got %d", len(merged))
+	}
+	if got := len(merged[1].Pages); got != 1 {
+		t.Fatalf("expected duplicate page dropped, got %d pages", got)
+	}
+	if got := merged[1].Pages[0].GetSequence(); got != 4 {
+		t.Errorf("surviving page has sequence %d, want 4", got)
+	}
+}
+
+func TestMergeCommandsKeepsDistinctCovers(t *testing.T) {
+	merged := MergeCommands(
+		[]*pb.CommandBook{testCommandBook("player", []byte("0123456789abcdef"), 0)},
+		[]*pb.CommandBook{testCommandBook("table", []byte("0123456789abcdef"), 0)},
+		[]*pb.CommandBook{testCommandBook("player", []byte("fedcba9876543210"), 0)},
+	)
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 books, got %d", len(merged))
+	}
+}
+
+func TestMergeCommandsDropsFullyDuplicateBooks(t *testing.T) {
+	root := []byte("0123456789abcdef")
+	book := testCommandBook("player", root, 1)
+	merged := MergeCommands([]*pb.CommandBook{book, testCommandBook("player", root, 1)})
+	if len(merged) != 1 {
+		t.Fatalf("expected fully-duplicate book dropped, got %d books", len(merged))
+	}
+}
@@ -0,0 +1,87 @@
+package angzarr
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fileConfig is the shape of an ANGZARR_CONFIG file.
+type fileConfig struct {
+	Domain    string   `json:"domain"`
+	Port      string   `json:"port"`
+	Subscribe []string `json:"subscribe"`
+}
+
+// LoadConfig builds a ServerConfig from the environment, layered over the
+// given code defaults: the file named by ANGZARR_CONFIG (JSON, with
+// domain, port, and subscribe keys) overrides the defaults, and the
+// ANGZARR_DOMAIN, ANGZARR_PORT, and ANGZARR_SUBSCRIBE variables override
+// the file, so one binary can be repurposed across environments without
+// recompiling.  ANGZARR_SUBSCRIBE is a comma-separated list of
+// domain/Type pairs; the saga and process manager Run* helpers register
+// them on the router they serve.  PORT keeps its existing precedence over
+// the loaded port at listen time.
+func LoadConfig(defaults ServerConfig) (ServerConfig, error) {
+	cfg := defaults
+	if path := os.Getenv("ANGZARR_CONFIG"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("reading config file: %w", err)
+		}
+		var file fileConfig
+		if err := json.Unmarshal(data, &file); err != nil {
+			return cfg, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+		if file.Domain != "" {
+			cfg.Domain = file.Domain
+		}
+		if file.Port != "" {
+			cfg.DefaultPort = file.Port
+		}
+		if len(file.Subscribe) > 0 {
+			cfg.Subscriptions = file.Subscribe
+		}
+	}
+	if domain := os.Getenv("ANGZARR_DOMAIN"); domain != "" {
+		cfg.Domain = domain
+	}
+	if port := os.Getenv("ANGZARR_PORT"); port != "" {
+		cfg.DefaultPort = port
+	}
+	if subscribe := os.Getenv("ANGZARR_SUBSCRIBE"); subscribe != "" {
+		cfg.Subscriptions = splitSubscriptions(subscribe)
+	}
+	return cfg, nil
+}
+
+// splitSubscriptions parses a comma-separated subscription list, dropping
+// empty entries so a trailing comma is harmless.
+func splitSubscriptions(value string) []string {
+	var subs []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			subs = append(subs, entry)
+		}
+	}
+	return subs
+}
+
+// subscriptionPairs splits the config's domain/Type subscriptions,
+// skipping malformed entries.  A bare domain subscribes to all its
+// events ("hand" is "hand/*" shorthand).
+func (c ServerConfig) subscriptionPairs() [][2]string {
+	var pairs [][2]string
+	for _, sub := range c.Subscriptions {
+		domain, typeName, found := strings.Cut(sub, "/")
+		if !found {
+			typeName = Wildcard
+		}
+		if domain == "" {
+			continue
+		}
+		pairs = append(pairs, [2]string{domain, typeName})
+	}
+	return pairs
+}
@@ -0,0 +1,74 @@
+package angzarr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfigKeepsDefaultsWithEmptyEnvironment(t *testing.T) {
+	cfg, err := LoadConfig(Config("player", "50201"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Domain != "player" || cfg.DefaultPort != "50201" {
+		t.Errorf("config = %q:%q, want player:50201", cfg.Domain, cfg.DefaultPort)
+	}
+}
+
+func TestLoadConfigEnvOverridesDefaults(t *testing.T) {
+	t.Setenv("ANGZARR_DOMAIN", "table")
+	t.Setenv("ANGZARR_PORT", "50999")
+	t.Setenv("ANGZARR_SUBSCRIBE", "hand/PotAwarded, hand/HandOpened,")
+
+	cfg, err := LoadConfig(Config("player", "50201"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Domain != "table" || cfg.DefaultPort != "50999" {
+		t.Errorf("config = %q:%q, want table:50999", cfg.Domain, cfg.DefaultPort)
+	}
+	want := []string{"hand/PotAwarded", "hand/HandOpened"}
+	if !reflect.DeepEqual(cfg.Subscriptions, want) {
+		t.Errorf("subscriptions = %v, want %v", cfg.Subscriptions, want)
+	}
+}
+
+func TestLoadConfigFileUnderEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "angzarr.json")
+	if err := os.WriteFile(path, []byte(`{"domain":"hand","port":"50301","subscribe":["player/PlayerRegistered"]}`), 0o644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	t.Setenv("ANGZARR_CONFIG", path)
+	t.Setenv("ANGZARR_DOMAIN", "table")
+
+	cfg, err := LoadConfig(ServerConfig{})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Domain != "table" {
+		t.Errorf("domain = %q, want table (env wins over file)", cfg.Domain)
+	}
+	if cfg.DefaultPort != "50301" {
+		t.Errorf("port = %q, want 50301 (from file)", cfg.DefaultPort)
+	}
+	if len(cfg.Subscriptions) != 1 || cfg.Subscriptions[0] != "player/PlayerRegistered" {
+		t.Errorf("subscriptions = %v, want [player/PlayerRegistered]", cfg.Subscriptions)
+	}
+}
+
+func TestLoadConfigRejectsUnreadableFile(t *testing.T) {
+	t.Setenv("ANGZARR_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := LoadConfig(ServerConfig{}); err == nil {
+		t.Error("missing config file was not reported")
+	}
+}
+
+func TestSubscriptionPairs(t *testing.T) {
+	cfg := ServerConfig{Subscriptions: []string{"hand/PotAwarded", "table", "/Broken"}}
+	want := [][2]string{{"hand", "PotAwarded"}, {"table", Wildcard}}
+	if got := cfg.subscriptionPairs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("pairs = %v, want %v", got, want)
+	}
+}
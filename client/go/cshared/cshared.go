@@ -0,0 +1,125 @@
+// Package cshared exports angzarr command dispatch over a C ABI, for
+// hosts that embed the Go business logic as a shared library (the Rust
+// integration links against a -buildmode=c-shared build of a main package
+// importing this one).  Each call returns an angzarr_result struct with
+// separate data and error buffers instead of overloading one buffer with
+// a sign-flipped length, so the caller can never misread an error message
+// as an event book; both buffers are owned by the caller and released
+// with a single angzarr_free_result.
+package cshared
+
+/*
+#include <stdlib.h>
+#include <string.h>
+
+// angzarr_result carries one dispatch outcome across the C boundary.
+// Exactly one of ptr and err_ptr is non-NULL.  The caller owns both
+// buffers and must pass the struct to angzarr_free_result exactly once.
+typedef struct {
+	void  *ptr;
+	size_t len;
+	void  *err_ptr;
+	size_t err_len;
+} angzarr_result;
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+
+	angzarr "github.com/benjaminabbitt/angzarr/client/go"
+)
+
+var (
+	mu      sync.RWMutex
+	routers = make(map[string]*angzarr.CommandRouter)
+)
+
+// Register wires a command router under its domain, making it reachable
+// from angzarr_handle.  The embedding main package registers its routers
+// in an init function before the host makes any calls.
+func Register(router *angzarr.CommandRouter) {
+	mu.Lock()
+	defer mu.Unlock()
+	routers[router.Domain()] = router
+}
+
+// routerFor looks up the router registered for domain.
+func routerFor(domain string) (*angzarr.CommandRouter, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	router, ok := routers[domain]
+	if !ok {
+		return nil, fmt.Errorf("no router registered for domain %q", domain)
+	}
+	return router, nil
+}
+
+// makeResult copies a dispatch outcome into C memory.  Data and error
+// travel in separate buffers so neither needs in-band signalling.
+func makeResult(data []byte, err error) C.angzarr_result {
+	var result C.angzarr_result
+	if err != nil {
+		msg := err.Error()
+		result.err_ptr = C.CBytes([]byte(msg))
+		result.err_len = C.size_t(len(msg))
+		return result
+	}
+	result.ptr = C.CBytes(data)
+	result.len = C.size_t(len(data))
+	return result
+}
+
+// resultData reads a result back into Go memory, for tests exercising the
+// round trip from the caller's side of the boundary.
+func resultData(result C.angzarr_result) (data []byte, errMsg string) {
+	if result.err_ptr != nil {
+		return nil, string(C.GoBytes(result.err_ptr, C.int(result.err_len)))
+	}
+	return C.GoBytes(result.ptr, C.int(result.len)), ""
+}
+
+// roundTrip drives the exported entry points the way a C caller would —
+// domain and command copied into C memory, result buffers read back and
+// freed — so tests exercise the real boundary.  Test files cannot use
+// cgo themselves, which is why this lives here.
+func roundTrip(domain string, cmd []byte) (data []byte, errMsg string) {
+	cDomain := C.CString(domain)
+	defer C.free(unsafe.Pointer(cDomain))
+	cCmd := C.CBytes(cmd)
+	defer C.free(cCmd)
+	result := angzarr_handle(cDomain, cCmd, C.size_t(len(cmd)))
+	defer angzarr_free_result(result)
+	return resultData(result)
+}
+
+// angzarr_handle dispatches a serialized ContextualCommand through the
+// router registered for domain and returns the serialized emitted event
+// book; see angzarr.FFIHandle for the command encoding and dispatch
+// semantics.  The input buffer is copied before dispatch and may be freed
+// by the caller as soon as the call returns.
+//
+//export angzarr_handle
+func angzarr_handle(domain *C.char, cmd unsafe.Pointer, cmdLen C.size_t) C.angzarr_result {
+	router, err := routerFor(C.GoString(domain))
+	if err != nil {
+		return makeResult(nil, err)
+	}
+	out, err := angzarr.FFIHandle(router, router.Domain(), C.GoBytes(cmd, C.int(cmdLen)))
+	return makeResult(out, err)
+}
+
+// angzarr_free_result releases both of a result's buffers.  Freeing a
+// result twice is the caller's bug, as with any C allocation.
+//
+//export angzarr_free_result
+func angzarr_free_result(result C.angzarr_result) {
+	if result.ptr != nil {
+		C.free(result.ptr)
+	}
+	if result.err_ptr != nil {
+		C.free(result.err_ptr)
+	}
+}
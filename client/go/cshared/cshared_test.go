@@ -0,0 +1,84 @@
+package cshared
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	angzarr "github.com/benjaminabbitt/angzarr/client/go"
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func init() {
+	Register(angzarr.NewCommandRouter("player").
+		On("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+			return &pb.EventBook{
+				Cover: cmdBook.GetCover(),
+				Pages: []*pb.EventPage{{
+					Sequence:  &pb.EventPage_Num{Num: seq},
+					CreatedAt: timestamppb.Now(),
+					Event:     cmd,
+				}},
+			}, nil
+		}))
+}
+
+// encodeCommand serializes a command the way the embedding host does.
+func encodeCommand(t *testing.T, domain, payload string) []byte {
+	t.Helper()
+	cmd, err := anypb.New(wrapperspb.String(payload))
+	if err != nil {
+		t.Fatalf("packing command: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&pb.ContextualCommand{
+		Command: &pb.CommandBook{
+			Cover: &pb.Cover{Domain: domain, Root: &pb.UUID{Value: []byte("0123456789abcdef")}},
+			Pages: []*pb.CommandPage{{Payload: &pb.CommandPage_Command{Command: cmd}}},
+		},
+	}); err != nil {
+		t.Fatalf("encoding command: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestHandleRoundTripsThroughCBoundary(t *testing.T) {
+	data, errMsg := roundTrip("player", encodeCommand(t, "player", "register"))
+	if errMsg != "" {
+		t.Fatalf("handle: %s", errMsg)
+	}
+	var events pb.EventBook
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&events); err != nil {
+		t.Fatalf("decoding events: %v", err)
+	}
+	if len(events.GetPages()) != 1 {
+		t.Errorf("emitted %d pages, want 1", len(events.GetPages()))
+	}
+}
+
+func TestHandleReportsErrorsInSeparateBuffer(t *testing.T) {
+	data, errMsg := roundTrip("unregistered", encodeCommand(t, "unregistered", "register"))
+	if errMsg == "" {
+		t.Fatal("unregistered domain produced no error")
+	}
+	if data != nil {
+		t.Errorf("error result also carried %d data bytes", len(data))
+	}
+}
+
+// TestRepeatedCallsFreeCleanly hammers the boundary with paired
+// handle/free calls; a mismatched or double free crashes the C allocator
+// here rather than in a host process.
+func TestRepeatedCallsFreeCleanly(t *testing.T) {
+	cmd := encodeCommand(t, "player", "register")
+	for i := 0; i < 1000; i++ {
+		if _, errMsg := roundTrip("player", cmd); errMsg != "" {
+			t.Fatalf("call %d: %s", i, errMsg)
+		}
+	}
+}
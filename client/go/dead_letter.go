@@ -0,0 +1,133 @@
+package angzarr
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// DeadLetter captures an event book a service failed to process, with
+// enough context to inspect and replay it: the failing service, the raw
+// book, and the error that killed the dispatch.
+type DeadLetter struct {
+	Service string
+	Book    *pb.EventBook
+	Reason  string
+	At      time.Time
+}
+
+// DeadLetterSink receives events that failed processing.  Configure one
+// through ServerConfig.DeadLetterSink; the Run* helpers install it on
+// the router or handler they serve.  Dead-lettering is a copy for
+// operators — the original error still propagates to the coordinator.
+type DeadLetterSink interface {
+	Write(letter DeadLetter) error
+}
+
+// MemoryDeadLetterSink collects dead letters in memory, for tests and
+// for services that expose them over an admin endpoint.
+type MemoryDeadLetterSink struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+// NewMemoryDeadLetterSink creates an empty in-memory sink.
+func NewMemoryDeadLetterSink() *MemoryDeadLetterSink {
+	return &MemoryDeadLetterSink{}
+}
+
+// Write implements DeadLetterSink.
+func (s *MemoryDeadLetterSink) Write(letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+// Letters returns a copy of the collected dead letters.
+func (s *MemoryDeadLetterSink) Letters() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter(nil), s.letters...)
+}
+
+// FileDeadLetterSink appends dead letters to a file as NDJSON, one
+// letter per line, with event payloads base64-encoded so books can be
+// reconstructed for replay.  Each write is flushed immediately — dead
+// letters are rare and must survive a crash.
+type FileDeadLetterSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileDeadLetterSink opens (creating or appending) the file at path.
+func NewFileDeadLetterSink(path string) (*FileDeadLetterSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileDeadLetterSink{file: file}, nil
+}
+
+// Write implements DeadLetterSink.
+func (s *FileDeadLetterSink) Write(letter DeadLetter) error {
+	line, err := json.Marshal(jsonDeadLetter(letter))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the underlying file.
+func (s *FileDeadLetterSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// jsonDeadLetter renders a letter as a flat JSON-friendly record.
+func jsonDeadLetter(letter DeadLetter) map[string]any {
+	record := map[string]any{
+		"service": letter.Service,
+		"reason":  letter.Reason,
+		"at":      letter.At.Format(time.RFC3339Nano),
+		"domain":  letter.Book.GetCover().GetDomain(),
+		"root":    hex.EncodeToString(letter.Book.GetCover().GetRoot().GetValue()),
+	}
+	var pages []map[string]any
+	for _, page := range letter.Book.GetPages() {
+		entry := map[string]any{"sequence": PageSequence(page)}
+		if event := page.GetEvent(); event != nil {
+			entry["type"] = event.GetTypeUrl()
+			entry["data_base64"] = base64.StdEncoding.EncodeToString(event.GetValue())
+		}
+		pages = append(pages, entry)
+	}
+	record["pages"] = pages
+	return record
+}
+
+// deadLetter writes a letter to the sink, nil-safe so dispatch paths can
+// call it unconditionally.  Sink failures are swallowed: dead-lettering
+// is best-effort and must never mask the original dispatch error.
+func deadLetter(sink DeadLetterSink, service string, book *pb.EventBook, cause error) {
+	if sink == nil || cause == nil {
+		return
+	}
+	sink.Write(DeadLetter{
+		Service: service,
+		Book:    book,
+		Reason:  cause.Error(),
+		At:      time.Now(),
+	})
+}
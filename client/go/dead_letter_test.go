@@ -0,0 +1,81 @@
+package angzarr
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestEventRouterDeadLettersFailedDispatch(t *testing.T) {
+	sink := NewMemoryDeadLetterSink()
+	router := NewEventRouter("saga-test").Domain("table").
+		WithDeadLetterSink(sink).
+		On("StringValue", func(context.Context, *pb.EventBook, *anypb.Any, []*pb.EventBook) ([]*pb.CommandBook, error) {
+			return nil, FailedPrecondition("cannot react")
+		})
+
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("go"))
+	if _, err := router.Handle(context.Background(), source, nil); err == nil {
+		t.Fatal("expected dispatch failure")
+	}
+	letters := sink.Letters()
+	if len(letters) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(letters))
+	}
+	if letters[0].Service != "saga-test" || letters[0].Book.GetCover().GetDomain() != "table" {
+		t.Errorf("letter = %+v", letters[0])
+	}
+	if letters[0].Reason == "" {
+		t.Error("letter carries no reason")
+	}
+}
+
+func TestProjectorDeadLettersFailedDispatch(t *testing.T) {
+	sink := NewMemoryDeadLetterSink()
+	handler := NewProjectorHandler("prj-test", "player").
+		WithDeadLetterSink(sink).
+		WithHandle(func(context.Context, *pb.EventBook) (*pb.Projection, error) {
+			return nil, FailedPrecondition("disk full")
+		})
+
+	if _, err := handler.HandleSync(context.Background(), testBook(t, 0)); err == nil {
+		t.Fatal("expected dispatch failure")
+	}
+	if got := len(sink.Letters()); got != 1 {
+		t.Errorf("got %d dead letters, want 1", got)
+	}
+}
+
+func TestFileDeadLetterSinkWritesNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.ndjson")
+	sink, err := NewFileDeadLetterSink(path)
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	defer sink.Close()
+
+	deadLetter(sink, "prj-test", testBook(t, 3), FailedPrecondition("boom"))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("unmarshal line %q: %v", data, err)
+	}
+	if record["service"] != "prj-test" || record["domain"] != "player" {
+		t.Errorf("record = %v", record)
+	}
+	pages, ok := record["pages"].([]any)
+	if !ok || len(pages) != 1 {
+		t.Fatalf("pages = %v, want 1 entry", record["pages"])
+	}
+}
@@ -0,0 +1,95 @@
+package angzarr
+
+import (
+	"context"
+	"sort"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// sortedKeys returns a map's keys in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// descriptionServer serves a service's routing table, captured once at
+// startup — registrations do not change after a Run* helper starts.
+type descriptionServer struct {
+	desc *pb.ServiceDescription
+}
+
+func (s *descriptionServer) Describe(context.Context, *pb.DescribeRequest) (*pb.ServiceDescription, error) {
+	return s.desc, nil
+}
+
+// describeAggregate captures a command router's routing table.
+func describeAggregate(r *CommandRouter) *pb.ServiceDescription {
+	return &pb.ServiceDescription{
+		Name:       r.Domain(),
+		Domain:     r.Domain(),
+		Commands:   r.RegisteredCommands(),
+		Rejections: r.RegisteredRejections(),
+	}
+}
+
+// domainEventPairs flattens a domain → event-types table into sorted
+// "domain/Type" strings, the form descriptions and startup logs share.
+func domainEventPairs(subs map[string][]string) []string {
+	var events []string
+	for domain, types := range subs {
+		for _, typeName := range types {
+			events = append(events, domain+"/"+typeName)
+		}
+	}
+	sort.Strings(events)
+	return events
+}
+
+// describeSaga captures an event router's routing table.  Events are
+// reported as domain/Type pairs; outputs are the declared Sends domains.
+func describeSaga(r *EventRouter) *pb.ServiceDescription {
+	events := domainEventPairs(r.Subscriptions())
+	return &pb.ServiceDescription{
+		Name:       r.Name(),
+		Domain:     r.SourceDomain(),
+		Events:     events,
+		Rejections: sortedKeys(r.rejectionHandlers),
+		Outputs:    sortedKeys(r.sends),
+	}
+}
+
+// describeProjector captures a projector handler's subscriptions.
+func describeProjector(h *ProjectorHandler) *pb.ServiceDescription {
+	events := append([]string(nil), h.EventTypes()...)
+	sort.Strings(events)
+	domain := ""
+	if domains := h.Domains(); len(domains) > 0 {
+		domain = domains[0]
+	}
+	return &pb.ServiceDescription{
+		Name:   h.Name(),
+		Domain: domain,
+		Events: events,
+	}
+}
+
+// describeProcessManager captures a process manager's subscriptions as
+// domain/Type pairs (domain/* for wildcard registrations).
+func describeProcessManager(h *ProcessManagerHandler) *pb.ServiceDescription {
+	var events []string
+	for domain, types := range h.subscriptions {
+		for typeName := range types {
+			events = append(events, domain+"/"+typeName)
+		}
+	}
+	sort.Strings(events)
+	return &pb.ServiceDescription{
+		Name:   h.Name(),
+		Events: events,
+	}
+}
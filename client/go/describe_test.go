@@ -0,0 +1,68 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestRegisteredCommandsSorted(t *testing.T) {
+	router := NewCommandRouter("player").
+		On("Withdraw", echoHandler).
+		On("Deposit", echoHandler).
+		OnRejected("bank", "Transfer", func(*pb.Notification, any) (*pb.BusinessResponse, error) {
+			return &pb.BusinessResponse{}, nil
+		})
+
+	commands := router.RegisteredCommands()
+	if len(commands) != 2 || commands[0] != "Deposit" || commands[1] != "Withdraw" {
+		t.Errorf("registered commands = %v, want [Deposit Withdraw]", commands)
+	}
+	rejections := router.RegisteredRejections()
+	if len(rejections) != 1 || rejections[0] != "bank/Transfer" {
+		t.Errorf("registered rejections = %v, want [bank/Transfer]", rejections)
+	}
+}
+
+func TestEventRouterSubscriptionsCopied(t *testing.T) {
+	router := NewEventRouter("saga-test").Domain("table").
+		Subscribes("table", "HandStarted")
+
+	subs := router.Subscriptions()
+	subs["table"] = append(subs["table"], "Tampered")
+	if got := router.Subscriptions()["table"]; len(got) != 1 {
+		t.Errorf("mutating the copy leaked into the router: %v", got)
+	}
+}
+
+func TestDescribeSaga(t *testing.T) {
+	router := NewEventRouter("saga-table-hand").Domain("table").
+		Subscribes("table", "HandStarted").
+		Sends("hand", "DealCards").
+		On("HandStarted", func(context.Context, *pb.EventBook, *anypb.Any, []*pb.EventBook) ([]*pb.CommandBook, error) {
+			return nil, nil
+		})
+
+	desc := describeSaga(router)
+	if desc.GetName() != "saga-table-hand" || desc.GetDomain() != "table" {
+		t.Errorf("described as %s/%s", desc.GetName(), desc.GetDomain())
+	}
+	if len(desc.GetEvents()) != 1 || desc.GetEvents()[0] != "table/HandStarted" {
+		t.Errorf("events = %v, want [table/HandStarted]", desc.GetEvents())
+	}
+	if len(desc.GetOutputs()) != 1 || desc.GetOutputs()[0] != "hand" {
+		t.Errorf("outputs = %v, want [hand]", desc.GetOutputs())
+	}
+}
+
+func TestDescribeAggregate(t *testing.T) {
+	desc := describeAggregate(NewCommandRouter("player").On("Deposit", echoHandler))
+	if desc.GetDomain() != "player" {
+		t.Errorf("domain = %q, want player", desc.GetDomain())
+	}
+	if len(desc.GetCommands()) != 1 || desc.GetCommands()[0] != "Deposit" {
+		t.Errorf("commands = %v, want [Deposit]", desc.GetCommands())
+	}
+}
@@ -0,0 +1,46 @@
+package angzarr
+
+import (
+	"encoding/hex"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// DestinationIndex indexes the destination books a saga or process
+// manager receives by their root identifier, replacing the hand-rolled
+// hex-keyed maps handlers built to look up a destination's state and
+// next sequence.  Build one at the top of a handler:
+//
+//	index := angzarr.NewDestinationIndex(destinations)
+//	page.Sequence = index.NextSequence(winnerRoot)
+type DestinationIndex struct {
+	byRoot map[string]*pb.EventBook
+}
+
+// NewDestinationIndex indexes the given books by cover root.  Books
+// without a root are skipped; when two books share a root the last one
+// wins.
+func NewDestinationIndex(destinations []*pb.EventBook) DestinationIndex {
+	byRoot := make(map[string]*pb.EventBook, len(destinations))
+	for _, book := range destinations {
+		root := book.GetCover().GetRoot().GetValue()
+		if len(root) == 0 {
+			continue
+		}
+		byRoot[hex.EncodeToString(root)] = book
+	}
+	return DestinationIndex{byRoot: byRoot}
+}
+
+// Book returns the destination book for the given root, or nil when the
+// root was not prepared.
+func (i DestinationIndex) Book(root []byte) *pb.EventBook {
+	return i.byRoot[hex.EncodeToString(root)]
+}
+
+// NextSequence returns the next free sequence for the destination with
+// the given root.  An unprepared root reports 0 — the correct starting
+// sequence for an aggregate with no history.
+func (i DestinationIndex) NextSequence(root []byte) uint32 {
+	return NextSequence(i.Book(root))
+}
@@ -0,0 +1,41 @@
+package angzarr
+
+import (
+	"testing"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestDestinationIndexLooksUpByRoot(t *testing.T) {
+	first := testBook(t, 0, 1)
+	second := testBook(t, 0, 1, 2)
+	second.Cover = &pb.Cover{Domain: "hand", Root: &pb.UUID{Value: []byte("handroot00000000")}}
+
+	index := NewDestinationIndex([]*pb.EventBook{first, second})
+	if got := index.Book([]byte("handroot00000000")); got != second {
+		t.Error("lookup returned the wrong book")
+	}
+	if got := index.NextSequence([]byte("handroot00000000")); got != 3 {
+		t.Errorf("next sequence = %d, want 3", got)
+	}
+	if got := index.NextSequence(testRoot().GetValue()); got != 2 {
+		t.Errorf("next sequence = %d, want 2", got)
+	}
+}
+
+func TestDestinationIndexUnpreparedRootStartsAtZero(t *testing.T) {
+	index := NewDestinationIndex(nil)
+	if got := index.Book([]byte("missing000000000")); got != nil {
+		t.Errorf("missing root returned %v", got)
+	}
+	if got := index.NextSequence([]byte("missing000000000")); got != 0 {
+		t.Errorf("next sequence = %d, want 0", got)
+	}
+}
+
+func TestDestinationIndexSkipsRootlessBooks(t *testing.T) {
+	index := NewDestinationIndex([]*pb.EventBook{{Cover: &pb.Cover{Domain: "hand"}}})
+	if got := index.NextSequence(nil); got != 0 {
+		t.Errorf("next sequence = %d, want 0", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Package discount holds the discount rules shared by the transaction
+// aggregate and the cross-language cart examples, so the Go servers and
+// the FFI bindings apply identical math instead of drifting copies.
+// Amounts are int32 cents throughout, matching the money helpers in the
+// parent package.  Every function returns the discount amount — what to
+// subtract from the subtotal — never the discounted total.
+package discount
+
+import (
+	"fmt"
+
+	angzarr "github.com/benjaminabbitt/angzarr/client/go"
+)
+
+// coupons maps the recognized coupon codes to their percentage discounts.
+var coupons = map[string]int32{
+	"SAVE10":  10,
+	"SAVE20":  20,
+	"HALFOFF": 50,
+}
+
+// Bulk discount tiers: an order of at least the tier's item count earns
+// its percentage.  Tiers are checked largest first.
+var bulkTiers = []struct {
+	items int32
+	pct   int32
+}{
+	{items: 50, pct: 10},
+	{items: 10, pct: 5},
+}
+
+// Percentage returns pct percent of subtotal, rounded half up to the
+// nearest cent, so a 15% discount on 999 cents is 150, not 149.  pct
+// outside 0–100 is rejected with codes.InvalidArgument; 0 and 100 are
+// valid and return 0 and the full subtotal respectively.
+func Percentage(subtotal, pct int32) (int32, error) {
+	if pct < 0 || pct > 100 {
+		return 0, angzarr.InvalidArgument(fmt.Sprintf("discount percentage %d outside 0-100", pct))
+	}
+	if subtotal < 0 {
+		return 0, angzarr.InvalidArgument("discount against negative subtotal")
+	}
+	return int32((int64(subtotal)*int64(pct) + 50) / 100), nil
+}
+
+// Fixed returns a flat discount of amount cents, capped at the subtotal —
+// a 500-cent coupon on a 300-cent order discounts 300, never driving the
+// total negative.  A negative amount is rejected with
+// codes.InvalidArgument.
+func Fixed(subtotal, amount int32) (int32, error) {
+	if amount < 0 {
+		return 0, angzarr.InvalidArgument(fmt.Sprintf("negative fixed discount %d", amount))
+	}
+	if subtotal < 0 {
+		return 0, angzarr.InvalidArgument("discount against negative subtotal")
+	}
+	if amount > subtotal {
+		return subtotal, nil
+	}
+	return amount, nil
+}
+
+// Coupon returns the percentage a coupon code is worth.  Unknown codes
+// are rejected with codes.InvalidArgument — a mistyped code is bad input
+// to surface to the customer, not a silent zero discount.
+func Coupon(code string) (int32, error) {
+	pct, ok := coupons[code]
+	if !ok {
+		return 0, angzarr.InvalidArgument(fmt.Sprintf("unknown coupon code %q", code))
+	}
+	return pct, nil
+}
+
+// Bulk returns the volume discount for an order: Percentage of the
+// subtotal at the highest tier itemCount reaches, or 0 below every tier.
+func Bulk(itemCount, subtotal int32) (int32, error) {
+	for _, tier := range bulkTiers {
+		if itemCount >= tier.items {
+			return Percentage(subtotal, tier.pct)
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,114 @@
+package discount
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	angzarr "github.com/benjaminabbitt/angzarr/client/go"
+)
+
+func TestPercentage(t *testing.T) {
+	tests := []struct {
+		name     string
+		subtotal int32
+		pct      int32
+		want     int32
+		wantCode codes.Code
+	}{
+		{name: "typical", subtotal: 1000, pct: 15, want: 150},
+		{name: "rounds half up", subtotal: 999, pct: 15, want: 150},
+		{name: "zero percent", subtotal: 1000, pct: 0, want: 0},
+		{name: "full subtotal", subtotal: 1000, pct: 100, want: 1000},
+		{name: "negative percent", subtotal: 1000, pct: -1, wantCode: codes.InvalidArgument},
+		{name: "over hundred", subtotal: 1000, pct: 101, wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Percentage(tt.subtotal, tt.pct)
+			if tt.wantCode != codes.OK {
+				if status.Code(angzarr.MapCommandError(err)) != tt.wantCode {
+					t.Fatalf("error = %v, want %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("percentage: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("discount = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixed(t *testing.T) {
+	tests := []struct {
+		name     string
+		subtotal int32
+		amount   int32
+		want     int32
+		wantCode codes.Code
+	}{
+		{name: "under subtotal", subtotal: 1000, amount: 300, want: 300},
+		{name: "exactly subtotal", subtotal: 1000, amount: 1000, want: 1000},
+		{name: "capped at subtotal", subtotal: 300, amount: 500, want: 300},
+		{name: "negative amount", subtotal: 1000, amount: -5, wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Fixed(tt.subtotal, tt.amount)
+			if tt.wantCode != codes.OK {
+				if status.Code(angzarr.MapCommandError(err)) != tt.wantCode {
+					t.Fatalf("error = %v, want %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fixed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("discount = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoupon(t *testing.T) {
+	pct, err := Coupon("SAVE10")
+	if err != nil {
+		t.Fatalf("coupon: %v", err)
+	}
+	if pct != 10 {
+		t.Errorf("SAVE10 = %d%%, want 10%%", pct)
+	}
+	if _, err := Coupon("SAVE99"); status.Code(angzarr.MapCommandError(err)) != codes.InvalidArgument {
+		t.Errorf("unknown code error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestBulk(t *testing.T) {
+	tests := []struct {
+		name      string
+		itemCount int32
+		subtotal  int32
+		want      int32
+	}{
+		{name: "below every tier", itemCount: 9, subtotal: 1000, want: 0},
+		{name: "first tier", itemCount: 10, subtotal: 1000, want: 50},
+		{name: "top tier", itemCount: 50, subtotal: 1000, want: 100},
+		{name: "top tier not doubled", itemCount: 200, subtotal: 1000, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Bulk(tt.itemCount, tt.subtotal)
+			if err != nil {
+				t.Fatalf("bulk: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("discount = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package angzarr
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CommandError is a semantic rejection a handler returns instead of a raw
+// gRPC status, naming why a command cannot be applied: bad input, a state
+// precondition, a missing or duplicate aggregate, or a lost race.  It
+// implements GRPCStatus, so the transport surfaces the intended code
+// rather than collapsing everything to Internal.
+type CommandError struct {
+	Code    codes.Code
+	Message string
+}
+
+// Error implements the error interface.
+func (e *CommandError) Error() string {
+	return e.Message
+}
+
+// GRPCStatus translates the error for the gRPC transport.
+func (e *CommandError) GRPCStatus() *status.Status {
+	return status.New(e.Code, e.Message)
+}
+
+// InvalidArgument rejects a command whose payload is malformed regardless
+// of aggregate state.
+func InvalidArgument(msg string) *CommandError {
+	return &CommandError{Code: codes.InvalidArgument, Message: msg}
+}
+
+// FailedPrecondition rejects a command the aggregate's current state
+// cannot accept (e.g. dealing cards into a finished hand).
+func FailedPrecondition(msg string) *CommandError {
+	return &CommandError{Code: codes.FailedPrecondition, Message: msg}
+}
+
+// NotFound rejects a command addressing an aggregate or entity that does
+// not exist (e.g. redeeming points for an unknown customer).
+func NotFound(msg string) *CommandError {
+	return &CommandError{Code: codes.NotFound, Message: msg}
+}
+
+// AlreadyExists rejects a command that would create a duplicate (e.g.
+// registering a customer twice).
+func AlreadyExists(msg string) *CommandError {
+	return &CommandError{Code: codes.AlreadyExists, Message: msg}
+}
+
+// Aborted rejects a command that lost a concurrency race and may succeed
+// if retried against fresh state.
+func Aborted(msg string) *CommandError {
+	return &CommandError{Code: codes.Aborted, Message: msg}
+}
+
+// FieldError rejects a command with codes.InvalidArgument and a
+// google.rpc.BadRequest detail naming the offending field, so clients can
+// surface validation failures against the right input instead of parsing
+// the message text.
+func FieldError(field, reason string) error {
+	st := status.New(codes.InvalidArgument, field+": "+reason)
+	detailed, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       field,
+			Description: reason,
+		}},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// Require returns nil when cond holds and FieldError(field, reason)
+// otherwise, collapsing the common validate-and-reject pattern to one
+// line:
+//
+//	if err := angzarr.Require(cmd.Amount > 0, "amount", "must be positive"); err != nil {
+//		return nil, err
+//	}
+func Require(cond bool, field, reason string) error {
+	if cond {
+		return nil
+	}
+	return FieldError(field, reason)
+}
+
+// MapCommandError translates a handler error for the transport, in one
+// place instead of a mapError copy per service: a CommandError (anywhere
+// in the wrap chain) becomes its status, an error already carrying a gRPC
+// status passes through unchanged, and anything else is an unclassified
+// handler failure surfaced as codes.Internal.  CommandRouter.Handle
+// applies this to every handler error, so handlers may return plain
+// errors, CommandErrors, or status errors interchangeably.
+func MapCommandError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.GRPCStatus().Err()
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,100 @@
+package angzarr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestCommandErrorCodes(t *testing.T) {
+	cases := []struct {
+		err  *CommandError
+		want codes.Code
+	}{
+		{InvalidArgument("bad payload"), codes.InvalidArgument},
+		{FailedPrecondition("hand finished"), codes.FailedPrecondition},
+		{NotFound("no such customer"), codes.NotFound},
+		{AlreadyExists("duplicate registration"), codes.AlreadyExists},
+		{Aborted("lost concurrency race"), codes.Aborted},
+	}
+	for _, tc := range cases {
+		if got := status.Code(tc.err); got != tc.want {
+			t.Errorf("status.Code(%q) = %v, want %v", tc.err.Message, got, tc.want)
+		}
+		if tc.err.Error() != tc.err.Message {
+			t.Errorf("Error() = %q, want %q", tc.err.Error(), tc.err.Message)
+		}
+	}
+}
+
+func TestMapCommandError(t *testing.T) {
+	if MapCommandError(nil) != nil {
+		t.Error("nil error should map to nil")
+	}
+	if got := status.Code(MapCommandError(NotFound("gone"))); got != codes.NotFound {
+		t.Errorf("CommandError mapped to %v, want NotFound", got)
+	}
+	// Wrapped CommandErrors are still found.
+	wrapped := fmt.Errorf("handling DealCards: %w", FailedPrecondition("hand finished"))
+	if got := status.Code(MapCommandError(wrapped)); got != codes.FailedPrecondition {
+		t.Errorf("wrapped CommandError mapped to %v, want FailedPrecondition", got)
+	}
+	// Existing status errors pass through unchanged.
+	passthrough := status.Error(codes.Unavailable, "downstream down")
+	if got := status.Code(MapCommandError(passthrough)); got != codes.Unavailable {
+		t.Errorf("status error mapped to %v, want Unavailable", got)
+	}
+	// Plain errors collapse to Internal.
+	if got := status.Code(MapCommandError(errors.New("boom"))); got != codes.Internal {
+		t.Errorf("plain error mapped to %v, want Internal", got)
+	}
+}
+
+func TestCommandRouterMapsHandlerErrors(t *testing.T) {
+	router := NewCommandRouter("customer").
+		On("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+			return nil, NotFound("no such customer")
+		})
+	_, err := router.Handle(context.Background(), commandFor(t, "customer", wrapperspb.String("redeem"), nil))
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("router surfaced %v, want NotFound", got)
+	}
+}
+
+func TestFieldErrorCarriesBadRequestDetail(t *testing.T) {
+	err := FieldError("amount", "must be positive")
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Fatalf("code = %v, want InvalidArgument", got)
+	}
+	st, _ := status.FromError(err)
+	var violation *errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badReq, ok := detail.(*errdetails.BadRequest); ok && len(badReq.FieldViolations) > 0 {
+			violation = badReq.FieldViolations[0]
+		}
+	}
+	if violation == nil {
+		t.Fatal("no BadRequest field violation attached")
+	}
+	if violation.Field != "amount" || violation.Description != "must be positive" {
+		t.Errorf("violation = %s/%s", violation.Field, violation.Description)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	if err := Require(true, "amount", "must be positive"); err != nil {
+		t.Errorf("satisfied condition returned %v", err)
+	}
+	if err := Require(false, "amount", "must be positive"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("failed condition returned %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package angzarr
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// EventLogFunc renders one unpacked domain event as structured log
+// fields.
+type EventLogFunc func(event proto.Message) []zap.Field
+
+// EventLogRegistry maps event type names to their log-field renderers,
+// replacing the per-projector switch statements that repeated the same
+// type → fields mapping in every log projector.  Register each event
+// type once:
+//
+//	registry := angzarr.NewEventLogRegistry().
+//		Register("FundsDeposited", func(event proto.Message) []zap.Field {
+//			deposited := event.(*commerce.FundsDeposited)
+//			return []zap.Field{zap.Int32("cents", deposited.GetCents())}
+//		})
+//
+// Events with no registration, and events that fail to unpack, fall back
+// to a raw byte count so nothing is dropped from the log.
+type EventLogRegistry struct {
+	entries map[string]EventLogFunc
+}
+
+// NewEventLogRegistry creates an empty registry.
+func NewEventLogRegistry() *EventLogRegistry {
+	return &EventLogRegistry{entries: make(map[string]EventLogFunc)}
+}
+
+// Register adds the renderer for the event with the given short type
+// name, replacing any earlier registration.
+func (r *EventLogRegistry) Register(typeName string, fields EventLogFunc) *EventLogRegistry {
+	r.entries[typeName] = fields
+	return r
+}
+
+// Fields renders the packed event through its registered renderer,
+// falling back to zap.Int("raw_bytes", ...) for unregistered types and
+// payloads that fail to unpack.
+func (r *EventLogRegistry) Fields(event *anypb.Any) []zap.Field {
+	fallback := []zap.Field{zap.Int("raw_bytes", len(event.GetValue()))}
+	render, ok := r.entries[typeNameOf(event.GetTypeUrl())]
+	if !ok {
+		return fallback
+	}
+	unpacked, err := event.UnmarshalNew()
+	if err != nil {
+		return fallback
+	}
+	return render(unpacked)
+}
+
+// NewLogProjector builds a projector that logs every subscribed event
+// with the standard envelope fields — domain, root, sequence, type —
+// plus whatever the registry renders for the event's type.  It produces
+// no projections; its output is the log itself.
+func NewLogProjector(name string, logger *zap.Logger, registry *EventLogRegistry, domains ...string) *ProjectorHandler {
+	return NewProjectorHandler(name, domains...).
+		WithHandleMulti(func(_ context.Context, events *pb.EventBook) ([]*pb.Projection, error) {
+			cover := events.GetCover()
+			for _, page := range events.GetPages() {
+				fields := []zap.Field{
+					zap.String("domain", cover.GetDomain()),
+					zap.String("root", RootIDText(cover)),
+					zap.Uint32("sequence", PageSequence(page)),
+					zap.String("type", typeNameOf(page.GetEvent().GetTypeUrl())),
+				}
+				fields = append(fields, registry.Fields(page.GetEvent())...)
+				logger.Info("event", fields...)
+			}
+			return nil, nil
+		})
+}
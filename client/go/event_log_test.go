@@ -0,0 +1,58 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestEventLogRegistryRendersRegisteredType(t *testing.T) {
+	registry := NewEventLogRegistry().
+		Register("StringValue", func(event proto.Message) []zap.Field {
+			return []zap.Field{zap.String("name", event.(*wrapperspb.StringValue).Value)}
+		})
+
+	book := testBook(t, 0)
+	fields := registry.Fields(book.GetPages()[0].GetEvent())
+	if len(fields) != 1 || fields[0].Key != "name" {
+		t.Fatalf("fields = %v, want one name field", fields)
+	}
+}
+
+func TestEventLogRegistryFallsBackToRawBytes(t *testing.T) {
+	fields := NewEventLogRegistry().Fields(testBook(t, 0).GetPages()[0].GetEvent())
+	if len(fields) != 1 || fields[0].Key != "raw_bytes" {
+		t.Fatalf("fields = %v, want the raw_bytes fallback", fields)
+	}
+}
+
+func TestLogProjectorLogsEnvelopeAndRegistryFields(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	registry := NewEventLogRegistry().
+		Register("StringValue", func(event proto.Message) []zap.Field {
+			return []zap.Field{zap.String("name", event.(*wrapperspb.StringValue).Value)}
+		})
+
+	handler := NewLogProjector("projector-log-player", zap.New(core), registry, "player")
+	if _, err := handler.HandleSync(context.Background(), testBook(t, 0, 1)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(entries))
+	}
+	ctx := entries[0].ContextMap()
+	if ctx["domain"] != "player" {
+		t.Errorf("domain field = %v", ctx["domain"])
+	}
+	if _, ok := ctx["name"]; !ok {
+		t.Error("registry field missing from log entry")
+	}
+	if ctx["type"] != "StringValue" {
+		t.Errorf("type field = %v", ctx["type"])
+	}
+}
@@ -0,0 +1,430 @@
+package angzarr
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// EventHandler reacts to a single event from the source domain, given the
+// full source book and the prepared destination books, and returns the
+// command books to emit.  ctx is the inbound request context; handlers
+// doing slow work should watch it for cancellation.
+type EventHandler func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) ([]*pb.CommandBook, error)
+
+// PrepareFunc inspects a source event book and names the destination covers
+// whose state the saga needs before its handlers run.
+type PrepareFunc func(source *pb.EventBook) []*pb.Cover
+
+// EventRouter dispatches source-domain events to saga handlers registered
+// by event type name.  The coordinator drives it in two phases: Prepare
+// names the destination aggregates to fetch, then Handle reacts with the
+// fetched destination state available.
+type EventRouter struct {
+	name              string
+	sourceDomain      string
+	sends             map[string][]string
+	subscriptions     map[string][]string
+	handlers          map[string]EventHandler
+	prepare           PrepareFunc
+	retry             SagaRetryFunc
+	rejectionHandlers map[string]SagaRejectionHandler
+	strictSends       bool
+	validateSeqs      bool
+	upcaster          *Upcaster
+	deadLetters       DeadLetterSink
+	tracer            trace.Tracer
+	metrics           *Metrics
+}
+
+// SagaRetryFunc reacts to the rejection of specific previously-emitted
+// commands — identified by cover and sequence in the notifications — and
+// returns the commands to re-issue for just those failures.
+type SagaRetryFunc func(rejected []*pb.Notification, destinations []*pb.EventBook) ([]*pb.CommandBook, error)
+
+// SagaRejectionHandler compensates for the downstream rejection of a
+// command this saga emitted, given the rejection notification and the
+// source book that originally triggered the command.
+type SagaRejectionHandler func(notification *pb.Notification, source *pb.EventBook) ([]*pb.CommandBook, error)
+
+// NewEventRouter creates an event router with the given saga name.  The
+// source domain is declared separately — explicitly with Domain, or
+// implicitly by the first Subscribes call — and RunSagaServer refuses to
+// serve a router that never declared one.
+//
+// Deprecated form: passing the source domain as a second argument still
+// works but should migrate to NewEventRouter(name).Domain(source); the
+// variadic remains only so existing sagas keep compiling.
+func NewEventRouter(name string, sourceDomain ...string) *EventRouter {
+	r := &EventRouter{
+		name:              name,
+		sends:             make(map[string][]string),
+		subscriptions:     make(map[string][]string),
+		handlers:          make(map[string]EventHandler),
+		rejectionHandlers: make(map[string]SagaRejectionHandler),
+	}
+	if len(sourceDomain) > 0 {
+		r.sourceDomain = sourceDomain[0]
+	}
+	return r
+}
+
+// Name returns the saga's registered name.
+func (r *EventRouter) Name() string {
+	return r.name
+}
+
+// Domain sets the source domain whose events the saga consumes.
+func (r *EventRouter) Domain(domain string) *EventRouter {
+	r.sourceDomain = domain
+	return r
+}
+
+// SourceDomain returns the domain whose events the saga consumes.
+func (r *EventRouter) SourceDomain() string {
+	return r.sourceDomain
+}
+
+// Subscribes declares the event types this saga consumes from a domain,
+// advertised to the coordinator.  The first subscribed domain also becomes
+// the source domain when none was set.  Repeated calls merge.
+func (r *EventRouter) Subscribes(domain string, events ...string) *EventRouter {
+	if r.sourceDomain == "" {
+		r.sourceDomain = domain
+	}
+	r.subscriptions[domain] = append(r.subscriptions[domain], events...)
+	return r
+}
+
+// Sends is the canonical output declaration: it names a target domain
+// this saga emits commands against and, optionally, the command types
+// within it.  A bare Sends(domain) declares the domain without
+// enumerating commands — under WithStrictSends that permits any command
+// type against the domain, while an enumerated list is enforced exactly.
+// Repeated calls merge.
+func (r *EventRouter) Sends(domain string, commands ...string) *EventRouter {
+	if _, ok := r.sends[domain]; !ok {
+		r.sends[domain] = nil
+	}
+	r.sends[domain] = append(r.sends[domain], commands...)
+	return r
+}
+
+// Output declares a target domain without enumerating its commands.
+//
+// Deprecated: call Sends(domain), which accepts the same bare-domain
+// form; Output remains only so existing sagas keep compiling.
+func (r *EventRouter) Output(domain string) *EventRouter {
+	return r.Sends(domain)
+}
+
+// Outputs returns a copy of the declared output topology: target domain
+// to command types, a nil slice meaning the domain was declared without
+// enumerating commands.  It is the Sends counterpart of Subscriptions.
+func (r *EventRouter) Outputs() map[string][]string {
+	outputs := make(map[string][]string, len(r.sends))
+	for domain, commands := range r.sends {
+		outputs[domain] = append([]string(nil), commands...)
+	}
+	return outputs
+}
+
+// WithStrictSends turns the Sends declarations from documentation into a
+// contract: every emitted command book is checked against them, and a
+// handler targeting an undeclared domain or emitting an undeclared
+// command type fails with codes.Internal — drift between the declared
+// topology and the implementation is a saga bug, not bad input.  Without
+// this, undeclared emissions pass silently as before.
+func (r *EventRouter) WithStrictSends() *EventRouter {
+	r.strictSends = true
+	return r
+}
+
+// WithSequenceValidation rejects source books whose pages are gapped or
+// out of order (see ValidateSequences) with codes.InvalidArgument before
+// any handler runs.  A missing event then surfaces as a delivery error
+// the coordinator can retry, instead of the saga silently reacting to
+// partial history.  Off by default.
+func (r *EventRouter) WithSequenceValidation() *EventRouter {
+	r.validateSeqs = true
+	return r
+}
+
+// validateSends enforces the Sends declarations against emitted command
+// books when strict mode is on.
+func (r *EventRouter) validateSends(commands []*pb.CommandBook) error {
+	if !r.strictSends || len(r.sends) == 0 {
+		return nil
+	}
+	for _, book := range commands {
+		domain := book.GetCover().GetDomain()
+		declared, ok := r.sends[domain]
+		if !ok {
+			return status.Errorf(codes.Internal,
+				"saga %q emitted a command against undeclared domain %q", r.name, domain)
+		}
+		if len(declared) == 0 {
+			// Bare-domain declaration: any command type may target it.
+			continue
+		}
+		for _, page := range book.GetPages() {
+			if page.GetCommand() == nil {
+				continue
+			}
+			name := typeNameOf(page.GetCommand().GetTypeUrl())
+			found := false
+			for _, cmd := range declared {
+				if cmd == name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return status.Errorf(codes.Internal,
+					"saga %q emitted undeclared command type %q against domain %q",
+					r.name, name, domain)
+			}
+		}
+	}
+	return nil
+}
+
+// Subscriptions returns a copy of the declared domain → event-type
+// subscriptions, for runtime topology introspection.
+func (r *EventRouter) Subscriptions() map[string][]string {
+	subs := make(map[string][]string, len(r.subscriptions))
+	for domain, events := range r.subscriptions {
+		subs[domain] = append([]string(nil), events...)
+	}
+	return subs
+}
+
+// On registers a handler for the source event with the given short type
+// name.
+func (r *EventRouter) On(name string, handler EventHandler) *EventRouter {
+	r.handlers[name] = handler
+	return r
+}
+
+// OnTyped registers a handler that receives the event already unmarshaled
+// into its concrete type, sparing every functional saga the same
+// unmarshal guard.  It is a package-level function only because Go
+// methods cannot be generic; it reads like On:
+//
+//	angzarr.OnTyped(router, "PotAwarded",
+//		func(ctx context.Context, source *pb.EventBook, event *poker.PotAwarded, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+//			...
+//		})
+//
+// A payload that fails to unmarshal into T fails the dispatch with the
+// unmarshal error.
+func OnTyped[T any, PT interface {
+	*T
+	proto.Message
+}](r *EventRouter, name string, handler func(ctx context.Context, source *pb.EventBook, event PT, destinations []*pb.EventBook) ([]*pb.CommandBook, error)) *EventRouter {
+	return r.On(name, func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+		typed := PT(new(T))
+		if err := event.UnmarshalTo(typed); err != nil {
+			return nil, err
+		}
+		return handler(ctx, source, typed, destinations)
+	})
+}
+
+// WithPrepare registers an explicit prepare phase.  Sagas whose destination
+// covers are derivable from the triggering event can skip this entirely:
+// without a registered prepare, PrepareCovers dry-runs the handlers with no
+// destination state and collects the covers of the commands they return,
+// and Handle later assigns command sequences from the fetched destination
+// books (see Handle).
+func (r *EventRouter) WithPrepare(prepare PrepareFunc) *EventRouter {
+	r.prepare = prepare
+	return r
+}
+
+// OnRejected registers a compensation handler invoked when a command this
+// saga emitted against the given domain is rejected downstream, the
+// saga-side symmetric of CommandRouter.OnRejected.
+func (r *EventRouter) OnRejected(domain, command string, handler SagaRejectionHandler) *EventRouter {
+	r.rejectionHandlers[domain+"/"+command] = handler
+	return r
+}
+
+// HandleRejection dispatches a downstream rejection notification to the
+// compensation handler registered for its domain and command, assigning
+// destination sequences to the emitted commands as Handle does when
+// destination state accompanies the notification.
+func (r *EventRouter) HandleRejection(notification *pb.Notification, source *pb.EventBook, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+	handler, ok := r.rejectionHandlers[notification.GetDomain()+"/"+notification.GetCommand()]
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "saga %q has no rejection handler for %s/%s",
+			r.name, notification.GetDomain(), notification.GetCommand())
+	}
+	commands, err := handler(notification, source)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateSends(commands); err != nil {
+		return nil, err
+	}
+	assignDestinationSequences(commands, destinations)
+	return commands, nil
+}
+
+// OnRetry registers the partial-failure reaction: when some of a trigger's
+// emitted commands are rejected downstream, Retry hands the handler only
+// those rejections so compensations that already succeeded are not re-run.
+func (r *EventRouter) OnRetry(retry SagaRetryFunc) *EventRouter {
+	r.retry = retry
+	return r
+}
+
+// Retry re-issues commands for the given rejections through the handler
+// registered with OnRetry, assigning destination sequences to the result
+// as Handle does.  Without a registered handler it fails with
+// codes.Unimplemented rather than silently re-running the whole trigger.
+func (r *EventRouter) Retry(rejected []*pb.Notification, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+	if r.retry == nil {
+		return nil, status.Errorf(codes.Unimplemented, "saga %q has no retry handler", r.name)
+	}
+	commands, err := r.retry(rejected, destinations)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateSends(commands); err != nil {
+		return nil, err
+	}
+	assignDestinationSequences(commands, destinations)
+	return commands, nil
+}
+
+// PrepareCovers returns the destination covers to fetch before Handle runs.
+// With an explicit prepare registered it is used directly; otherwise the
+// handlers are dry-run with nil destinations and the covers of the commands
+// they emit form the prepare set, deduplicated in first-seen order.
+func (r *EventRouter) PrepareCovers(ctx context.Context, source *pb.EventBook) ([]*pb.Cover, error) {
+	if r.prepare != nil {
+		return r.prepare(source), nil
+	}
+	commands, err := r.dispatch(ctx, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var covers []*pb.Cover
+	for _, book := range commands {
+		key := coverKey(book.GetCover())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		covers = append(covers, book.GetCover())
+	}
+	return covers, nil
+}
+
+// Handle dispatches each subscribed page of the source book to its handler
+// and returns the emitted command books in order.  For prepare-less sagas,
+// command pages the handler left at sequence 0 are assigned sequences from
+// the matching destination book (NextSequence onward), so handlers never
+// need to know destination sequencing.  ctx is threaded through to every
+// handler; callers without a request context pass context.Background().
+func (r *EventRouter) Handle(ctx context.Context, source *pb.EventBook, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+	commands, err := r.dispatch(ctx, source, destinations)
+	if err != nil {
+		return nil, err
+	}
+	assignDestinationSequences(commands, destinations)
+	return commands, nil
+}
+
+// WithUpcaster rewrites source events to their latest schema shapes
+// before dispatch, so handlers only ever see current event formats; see
+// Upcaster.
+func (r *EventRouter) WithUpcaster(upcaster *Upcaster) *EventRouter {
+	r.upcaster = upcaster
+	return r
+}
+
+// WithDeadLetterSink copies unprocessable source books to the sink when
+// a handler fails, so operators can inspect and replay them; the error
+// still propagates.  ServerConfig.DeadLetterSink installs this.
+func (r *EventRouter) WithDeadLetterSink(sink DeadLetterSink) *EventRouter {
+	r.deadLetters = sink
+	return r
+}
+
+// dispatch routes each page with a registered handler, accumulating emitted
+// commands.
+func (r *EventRouter) dispatch(ctx context.Context, source *pb.EventBook, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+	if r.validateSeqs {
+		if err := ValidateSequences(source); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "saga %q: %v", r.name, err)
+		}
+	}
+	if r.upcaster != nil {
+		var err error
+		if source, err = r.upcaster.ApplyBook(source); err != nil {
+			return nil, err
+		}
+	}
+	var commands []*pb.CommandBook
+	for _, page := range source.GetPages() {
+		handler, ok := r.handlers[typeNameOf(page.GetEvent().GetTypeUrl())]
+		if !ok {
+			continue
+		}
+		handlerCtx, finishSpan := startDispatchSpan(ctx, r.tracer,
+			r.name+"/"+typeNameOf(page.GetEvent().GetTypeUrl()), source.GetCover())
+		start := time.Now()
+		emitted, err := handler(handlerCtx, source, page.GetEvent(), destinations)
+		finishSpan(err)
+		r.metrics.observeDispatch("saga", r.name, time.Since(start), err)
+		if err != nil {
+			deadLetter(r.deadLetters, r.name, source, err)
+			return nil, err
+		}
+		if err := r.validateSends(emitted); err != nil {
+			return nil, err
+		}
+		injectTraceContext(handlerCtx, emitted)
+		for _, book := range emitted {
+			propagateMetadata(book.GetCover(), source.GetCover().GetMetadata())
+		}
+		commands = append(commands, emitted...)
+	}
+	return commands, nil
+}
+
+// assignDestinationSequences fills in sequence numbers for command pages
+// left at 0, using the destination book matching each command's cover.
+func assignDestinationSequences(commands []*pb.CommandBook, destinations []*pb.EventBook) {
+	if len(destinations) == 0 {
+		return
+	}
+	next := make(map[string]uint32, len(destinations))
+	for _, dest := range destinations {
+		next[coverKey(dest.GetCover())] = NextSequence(dest)
+	}
+	for _, book := range commands {
+		key := coverKey(book.GetCover())
+		seq, ok := next[key]
+		if !ok {
+			continue
+		}
+		for _, page := range book.GetPages() {
+			if page.GetSequence() == 0 {
+				page.Sequence = seq
+				seq++
+			}
+		}
+		next[key] = seq
+	}
+}
@@ -0,0 +1,275 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// dealCardsSaga models saga-table-hand: the destination hand root is
+// derivable from the triggering event, so no explicit prepare is needed.
+// StringValue stands in for HandStarted, carrying the hand root.
+func dealCardsSaga() *EventRouter {
+	return NewEventRouter("saga-table-hand").Domain("table").
+		Sends("hand", "DealCards").
+		On("StringValue", func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+			var handRoot wrapperspb.StringValue
+			if err := event.UnmarshalTo(&handRoot); err != nil {
+				return nil, err
+			}
+			return []*pb.CommandBook{{
+				Cover: &pb.Cover{Domain: "hand", Root: &pb.UUID{Value: []byte(handRoot.Value)}},
+				Pages: []*pb.CommandPage{{}},
+			}}, nil
+		})
+}
+
+func TestEventRouterPrepareLessDerivesCovers(t *testing.T) {
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("handroot00000000"))
+
+	covers, err := dealCardsSaga().PrepareCovers(context.Background(), source)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(covers) != 1 {
+		t.Fatalf("expected 1 derived cover, got %d", len(covers))
+	}
+	if covers[0].GetDomain() != "hand" || string(covers[0].GetRoot().GetValue()) != "handroot00000000" {
+		t.Errorf("derived cover = %s/%s", covers[0].GetDomain(), covers[0].GetRoot().GetValue())
+	}
+}
+
+func TestEventRouterPrepareLessAssignsSequences(t *testing.T) {
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("handroot00000000"))
+	destination := testBook(t, 0, 1, 2)
+	destination.Cover = &pb.Cover{Domain: "hand", Root: &pb.UUID{Value: []byte("handroot00000000")}}
+
+	commands, err := dealCardsSaga().Handle(context.Background(), source, []*pb.EventBook{destination})
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(commands) != 1 {
+		t.Fatalf("expected 1 command, got %d", len(commands))
+	}
+	if got := commands[0].Pages[0].GetSequence(); got != 3 {
+		t.Errorf("command sequence = %d, want 3 (destination NextSequence)", got)
+	}
+}
+
+func TestEventRouterExplicitPrepareWins(t *testing.T) {
+	router := dealCardsSaga().WithPrepare(func(source *pb.EventBook) []*pb.Cover {
+		return []*pb.Cover{{Domain: "hand", Root: &pb.UUID{Value: []byte("explicit00000000")}}}
+	})
+	covers, err := router.PrepareCovers(context.Background(), triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("handroot00000000")))
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(covers) != 1 || string(covers[0].GetRoot().GetValue()) != "explicit00000000" {
+		t.Fatalf("explicit prepare not used: %v", covers)
+	}
+}
+
+func TestEventRouterSkipsUnregisteredEvents(t *testing.T) {
+	commands, err := dealCardsSaga().Handle(context.Background(), triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.Bool(true)), nil)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(commands) != 0 {
+		t.Fatalf("expected no commands for unregistered event, got %d", len(commands))
+	}
+}
+
+func TestEventRouterRetryTargetsOnlyRejected(t *testing.T) {
+	router := dealCardsSaga().OnRetry(func(rejected []*pb.Notification, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+		// Re-issue one command per rejection, against the rejected cover.
+		var books []*pb.CommandBook
+		for _, n := range rejected {
+			books = append(books, &pb.CommandBook{
+				Cover: n.GetCover(),
+				Pages: []*pb.CommandPage{{}},
+			})
+		}
+		return books, nil
+	})
+
+	destination := testBook(t, 0, 1)
+	destination.Cover = &pb.Cover{Domain: "hand", Root: &pb.UUID{Value: []byte("handroot00000000")}}
+	rejected := []*pb.Notification{{
+		Cover:   destination.Cover,
+		Domain:  "hand",
+		Command: "DealCards",
+		Reason:  "hand already dealt",
+	}}
+
+	commands, err := router.Retry(rejected, []*pb.EventBook{destination})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(commands) != 1 {
+		t.Fatalf("expected 1 retried command, got %d", len(commands))
+	}
+	if got := commands[0].Pages[0].GetSequence(); got != 2 {
+		t.Errorf("retried command sequence = %d, want destination NextSequence 2", got)
+	}
+}
+
+func TestEventRouterRetryWithoutHandler(t *testing.T) {
+	if _, err := dealCardsSaga().Retry(nil, nil); err == nil {
+		t.Fatal("expected Unimplemented error without a retry handler")
+	}
+}
+
+func TestEventRouterRejectionCompensation(t *testing.T) {
+	router := dealCardsSaga().
+		OnRejected("hand", "DealCards", func(notification *pb.Notification, source *pb.EventBook) ([]*pb.CommandBook, error) {
+			return []*pb.CommandBook{{
+				Cover: source.GetCover(),
+				Pages: []*pb.CommandPage{{}},
+			}}, nil
+		})
+
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("handroot00000000"))
+	notification := &pb.Notification{Domain: "hand", Command: "DealCards", Reason: "table closed"}
+
+	commands, err := router.HandleRejection(notification, source, nil)
+	if err != nil {
+		t.Fatalf("handle rejection: %v", err)
+	}
+	if len(commands) != 1 || commands[0].GetCover().GetDomain() != "table" {
+		t.Fatalf("expected 1 compensating command against table, got %v", commands)
+	}
+
+	// An unregistered domain/command pair is surfaced, not swallowed.
+	if _, err := router.HandleRejection(&pb.Notification{Domain: "player", Command: "DepositFunds"}, source, nil); err == nil {
+		t.Fatal("expected error for unregistered rejection pair")
+	}
+}
+
+// sendingSaga emits a packed command of the given message type against
+// the given domain, for exercising Sends validation.
+func sendingSaga(t *testing.T, domain string, cmd proto.Message) *EventRouter {
+	t.Helper()
+	return NewEventRouter("saga-strict").Domain("table").
+		On("StringValue", func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+			packed, err := anypb.New(cmd)
+			if err != nil {
+				return nil, err
+			}
+			return []*pb.CommandBook{{
+				Cover: &pb.Cover{Domain: domain, Root: testRoot()},
+				Pages: []*pb.CommandPage{{Payload: &pb.CommandPage_Command{Command: packed}}},
+			}}, nil
+		})
+}
+
+func TestEventRouterStrictSendsAcceptsDeclared(t *testing.T) {
+	router := sendingSaga(t, "player", wrapperspb.Bool(true)).
+		Sends("player", "BoolValue").
+		WithStrictSends()
+
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("go"))
+	if _, err := router.Handle(context.Background(), source, nil); err != nil {
+		t.Fatalf("declared emission rejected: %v", err)
+	}
+}
+
+func TestEventRouterStrictSendsRejectsUndeclaredCommand(t *testing.T) {
+	router := sendingSaga(t, "player", wrapperspb.Int32(7)).
+		Sends("player", "BoolValue").
+		WithStrictSends()
+
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("go"))
+	if _, err := router.Handle(context.Background(), source, nil); err == nil {
+		t.Fatal("expected undeclared command type to be rejected")
+	}
+}
+
+func TestEventRouterStrictSendsRejectsUndeclaredDomain(t *testing.T) {
+	router := sendingSaga(t, "bank", wrapperspb.Bool(true)).
+		Sends("player", "BoolValue").
+		WithStrictSends()
+
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("go"))
+	if _, err := router.Handle(context.Background(), source, nil); err == nil {
+		t.Fatal("expected undeclared target domain to be rejected")
+	}
+}
+
+func TestEventRouterStrictSendsAllowsBareDomainDeclaration(t *testing.T) {
+	router := sendingSaga(t, "player", wrapperspb.Int32(7)).
+		Sends("player").
+		WithStrictSends()
+
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("go"))
+	if _, err := router.Handle(context.Background(), source, nil); err != nil {
+		t.Fatalf("bare-domain declaration rejected emission: %v", err)
+	}
+}
+
+func TestEventRouterOutputsMergesDeclarations(t *testing.T) {
+	router := NewEventRouter("saga-outputs").Domain("table").
+		Output("hand").
+		Sends("player", "DepositFunds")
+
+	outputs := router.Outputs()
+	if commands, ok := outputs["hand"]; !ok || commands != nil {
+		t.Errorf("hand output = %v, %v; want declared with no command list", commands, ok)
+	}
+	if commands := outputs["player"]; len(commands) != 1 || commands[0] != "DepositFunds" {
+		t.Errorf("player output = %v, want [DepositFunds]", commands)
+	}
+}
+
+func TestEventRouterSendsNotStrictByDefault(t *testing.T) {
+	router := sendingSaga(t, "bank", wrapperspb.Bool(true)).
+		Sends("player", "BoolValue")
+
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("go"))
+	if _, err := router.Handle(context.Background(), source, nil); err != nil {
+		t.Fatalf("non-strict router rejected emission: %v", err)
+	}
+}
+
+func TestOnTypedUnmarshalsBeforeHandler(t *testing.T) {
+	router := NewEventRouter("saga-typed").Domain("table")
+	OnTyped(router, "StringValue",
+		func(ctx context.Context, source *pb.EventBook, event *wrapperspb.StringValue, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+			return []*pb.CommandBook{{
+				Cover: &pb.Cover{Domain: "hand", Root: &pb.UUID{Value: []byte(event.Value)}},
+				Pages: []*pb.CommandPage{{}},
+			}}, nil
+		})
+
+	commands, err := router.Handle(context.Background(), triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("handroot00000000")), nil)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(commands) != 1 || string(commands[0].GetCover().GetRoot().GetValue()) != "handroot00000000" {
+		t.Fatalf("typed handler did not see the unmarshaled event: %v", commands)
+	}
+}
+
+func TestOnTypedSurfacesUnmarshalFailure(t *testing.T) {
+	router := NewEventRouter("saga-typed").Domain("table")
+	OnTyped(router, "StringValue",
+		func(ctx context.Context, source *pb.EventBook, event *wrapperspb.Int32Value, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+			t.Fatal("handler ran despite type mismatch")
+			return nil, nil
+		})
+
+	if _, err := router.Handle(context.Background(), triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("not an int")), nil); err == nil {
+		t.Fatal("expected unmarshal error")
+	}
+}
+
+func TestRunSagaServerRequiresSourceDomain(t *testing.T) {
+	err := RunSagaServer(ServerConfig{}, NewEventRouter("saga-undeclared"))
+	if err == nil {
+		t.Fatal("saga with no source domain was served")
+	}
+}
@@ -0,0 +1,68 @@
+package angzarr
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// ErrSequenceConflict reports an Append whose expected sequence no longer
+// matches the stream — another writer got there first.  Callers detect it
+// with errors.Is and retry from a fresh Load.
+var ErrSequenceConflict = errors.New("append sequence conflict")
+
+// EventStore persists aggregate event streams.  Implementations back the
+// aggregate server's history reads and event writes when the coordinator
+// is not supplying prior events itself; see ServerConfig.EventStore.
+type EventStore interface {
+	// Load returns the stream for the aggregate at domain/root: its
+	// snapshot, if one has been taken, and the pages after it.  A stream
+	// with no history returns a nil book, not an error.
+	Load(domain string, root []byte) (*pb.EventBook, error)
+	// Append adds the book's pages to the stream at domain/root.
+	// expectedSeq is the sequence the caller believes the stream assigns
+	// next (NextSequence of the loaded book); an Append against a stream
+	// that has moved past it fails with ErrSequenceConflict, so two
+	// concurrent commands cannot both commit against the same history.
+	Append(domain string, root []byte, events *pb.EventBook, expectedSeq uint32) error
+}
+
+// MemoryEventStore is an EventStore holding streams in process memory,
+// for tests and single-process deployments.  Load returns the stored
+// book itself, not a copy — callers must treat it as read-only, the same
+// contract StateCache places on cached state.
+type MemoryEventStore struct {
+	mu    sync.Mutex
+	books map[string]*pb.EventBook
+}
+
+// NewMemoryEventStore creates an empty in-memory event store.
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{books: make(map[string]*pb.EventBook)}
+}
+
+func (s *MemoryEventStore) Load(domain string, root []byte) (*pb.EventBook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.books[storeKey(domain, root)], nil
+}
+
+func (s *MemoryEventStore) Append(domain string, root []byte, events *pb.EventBook, expectedSeq uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := storeKey(domain, root)
+	prior := s.books[key]
+	if next := NextSequence(prior); next != expectedSeq {
+		return fmt.Errorf("%s at sequence %d, expected %d: %w",
+			key, next, expectedSeq, ErrSequenceConflict)
+	}
+	s.books[key] = mergeBooks(prior, events)
+	return nil
+}
+
+// storeKey names a stream the way coverKey names a cover.
+func storeKey(domain string, root []byte) string {
+	return coverKey(&pb.Cover{Domain: domain, Root: &pb.UUID{Value: root}})
+}
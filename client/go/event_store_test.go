@@ -0,0 +1,102 @@
+package angzarr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestMemoryEventStoreRoundTrip(t *testing.T) {
+	store := NewMemoryEventStore()
+	root := testRoot().GetValue()
+
+	if err := store.Append("transaction", root, deltaBook(t, 0, 10, 5), 0); err != nil {
+		t.Fatalf("first append: %v", err)
+	}
+	if err := store.Append("transaction", root, deltaBook(t, 2, 7), 2); err != nil {
+		t.Fatalf("second append: %v", err)
+	}
+	book, err := store.Load("transaction", root)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(book.GetPages()) != 3 {
+		t.Errorf("loaded %d pages, want 3", len(book.GetPages()))
+	}
+	if NextSequence(book) != 3 {
+		t.Errorf("next sequence = %d, want 3", NextSequence(book))
+	}
+}
+
+func TestMemoryEventStoreRejectsStaleAppend(t *testing.T) {
+	store := NewMemoryEventStore()
+	root := testRoot().GetValue()
+	if err := store.Append("transaction", root, deltaBook(t, 0, 10), 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	err := store.Append("transaction", root, deltaBook(t, 0, 5), 0)
+	if !errors.Is(err, ErrSequenceConflict) {
+		t.Errorf("stale append got %v, want ErrSequenceConflict", err)
+	}
+}
+
+func TestMemoryEventStoreEmptyStreamLoadsNil(t *testing.T) {
+	store := NewMemoryEventStore()
+	book, err := store.Load("transaction", testRoot().GetValue())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if book != nil {
+		t.Errorf("empty stream loaded %v, want nil", book)
+	}
+}
+
+func TestAggregateServerPersistsThroughEventStore(t *testing.T) {
+	store := NewMemoryEventStore()
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	srv := &businessLogicServer{router: router, exec: NewKeyedExecutor(), store: store}
+
+	for _, cmd := range []string{"register", "rename"} {
+		// No prior events in the request: the server loads them itself.
+		if _, err := srv.Handle(context.Background(), commandFor(t, "player", wrapperspb.String(cmd), nil)); err != nil {
+			t.Fatalf("handle %q: %v", cmd, err)
+		}
+	}
+
+	book, err := store.Load("player", testRoot().GetValue())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(book.GetPages()) != 2 {
+		t.Fatalf("stored %d pages, want 2", len(book.GetPages()))
+	}
+	if num, _ := pageNum(book.GetPages()[1]); num != 1 {
+		t.Errorf("second page at sequence %d, want 1 (handler saw loaded history)", num)
+	}
+}
+
+func TestAggregateServerSurfacesAppendConflict(t *testing.T) {
+	store := NewMemoryEventStore()
+	router := NewCommandRouter("player").
+		On("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+			// Simulate another process committing mid-dispatch.
+			if err := store.Append("player", cmdBook.GetCover().GetRoot().GetValue(), deltaBook(t, seq, 1), seq); err != nil {
+				return nil, err
+			}
+			return echoHandler(ctx, cmdBook, cmd, nil, seq)
+		})
+	srv := &businessLogicServer{router: router, exec: NewKeyedExecutor(), store: store}
+
+	_, err := srv.Handle(context.Background(), commandFor(t, "player", wrapperspb.String("register"), nil))
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("conflicting append got %v, want Aborted", err)
+	}
+}
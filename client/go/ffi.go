@@ -0,0 +1,42 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// FFIHandle dispatches a serialized ContextualCommand through the router
+// and returns the serialized emitted EventBook, for c-shared builds that
+// embed the Go business logic as a library.  The //export Handle shim
+// reduces to decoding its C buffer into a byte slice and delegating here,
+// getting the router's typed dispatch, sequence handling, and error
+// mapping instead of reimplementing them.  domain guards against a shim
+// wired to the wrong router: a command whose cover names another domain
+// fails with codes.InvalidArgument before any dispatch.
+//
+// Both buffers use the mirror types' canonical in-process encoding — the
+// same codec the test cluster speaks — since the embedding and embedded
+// sides are built from the same types.
+func FFIHandle(router *CommandRouter, domain string, cmdBytes []byte) ([]byte, error) {
+	var cc pb.ContextualCommand
+	if err := (clusterCodec{}).Unmarshal(cmdBytes, &cc); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decoding contextual command: %v", err)
+	}
+	if got := cc.GetCommand().GetCover().GetDomain(); got != domain {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"command for domain %q routed to %q", got, domain)
+	}
+	events, err := router.Handle(context.Background(), &cc)
+	if err != nil {
+		return nil, err
+	}
+	out, err := clusterCodec{}.Marshal(events)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encoding emitted events: %v", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// encodeCommand serializes a ContextualCommand the way an FFI shim would.
+func encodeCommand(t *testing.T, cc *pb.ContextualCommand) []byte {
+	t.Helper()
+	data, err := clusterCodec{}.Marshal(cc)
+	if err != nil {
+		t.Fatalf("encoding command: %v", err)
+	}
+	return data
+}
+
+func TestFFIHandleRoundTrip(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	cmdBytes := encodeCommand(t, commandFor(t, "player", wrapperspb.String("register"), nil))
+
+	out, err := FFIHandle(router, "player", cmdBytes)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	var events pb.EventBook
+	if err := (clusterCodec{}).Unmarshal(out, &events); err != nil {
+		t.Fatalf("decoding events: %v", err)
+	}
+	if len(events.GetPages()) != 1 {
+		t.Fatalf("emitted %d pages, want 1", len(events.GetPages()))
+	}
+	if got := typeNameOf(events.GetPages()[0].GetEvent().GetTypeUrl()); got != "StringValue" {
+		t.Errorf("emitted type = %q, want StringValue", got)
+	}
+}
+
+func TestFFIHandleRejectsWrongDomain(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	cmdBytes := encodeCommand(t, commandFor(t, "transaction", wrapperspb.String("register"), nil))
+
+	_, err := FFIHandle(router, "player", cmdBytes)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("cross-domain command got %v, want InvalidArgument", err)
+	}
+}
+
+func TestFFIHandleRejectsGarbageBytes(t *testing.T) {
+	router := NewCommandRouter("player")
+	_, err := FFIHandle(router, "player", []byte("not a command"))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("garbage input got %v, want InvalidArgument", err)
+	}
+}
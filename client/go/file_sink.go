@@ -0,0 +1,127 @@
+package angzarr
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"time"
+)
+
+// Flusher is anything with buffered output the server should flush
+// during graceful shutdown; see ServerConfig.FlushOnShutdown.
+type Flusher interface {
+	Flush() error
+}
+
+// defaultFlushInterval is the periodic flush cadence when a FileSink's
+// option leaves it unset.
+const defaultFlushInterval = time.Second
+
+// FileSink is a buffered append-only line writer for log-style
+// projectors: writes land in memory and reach the file on a periodic
+// tick, when the buffer fills, or on an explicit Flush — one syscall per
+// batch instead of one per event.  Register it in
+// ServerConfig.FlushOnShutdown so the final buffered lines survive
+// graceful stop.  Safe for concurrent use.
+type FileSink struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	ticker *time.Ticker
+	done   chan struct{}
+}
+
+// FileSinkOption configures a FileSink.
+type FileSinkOption func(*fileSinkConfig)
+
+type fileSinkConfig struct {
+	flushInterval time.Duration
+	bufferSize    int
+}
+
+// WithFlushInterval sets the periodic flush cadence.  Zero or negative
+// disables the timer, leaving only size-based and explicit flushes.
+func WithFlushInterval(d time.Duration) FileSinkOption {
+	return func(c *fileSinkConfig) { c.flushInterval = d }
+}
+
+// WithBufferSize sets the in-memory buffer size in bytes; the sink
+// flushes whenever a write would overflow it.  Zero keeps bufio's
+// default.
+func WithBufferSize(bytes int) FileSinkOption {
+	return func(c *fileSinkConfig) { c.bufferSize = bytes }
+}
+
+// NewFileSink opens (creating or appending) the file at path.
+func NewFileSink(path string, opts ...FileSinkOption) (*FileSink, error) {
+	cfg := fileSinkConfig{flushInterval: defaultFlushInterval}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	writer := bufio.NewWriter(file)
+	if cfg.bufferSize > 0 {
+		writer = bufio.NewWriterSize(file, cfg.bufferSize)
+	}
+	s := &FileSink{file: file, writer: writer, done: make(chan struct{})}
+	if cfg.flushInterval > 0 {
+		s.ticker = time.NewTicker(cfg.flushInterval)
+		go s.flushLoop()
+	}
+	return s, nil
+}
+
+// flushLoop flushes on each tick until Close.
+func (s *FileSink) flushLoop() {
+	for {
+		select {
+		case <-s.ticker.C:
+			s.Flush()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Write appends p to the buffer, implementing io.Writer.
+func (s *FileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writer.Write(p)
+}
+
+// WriteLine appends line plus a trailing newline to the buffer.
+func (s *FileSink) WriteLine(line string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.writer.WriteString(line); err != nil {
+		return err
+	}
+	return s.writer.WriteByte('\n')
+}
+
+// Flush forces buffered bytes to the file.
+func (s *FileSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writer.Flush()
+}
+
+// Close stops the periodic flush, flushes remaining bytes, and closes
+// the file.
+func (s *FileSink) Close() error {
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+	close(s.done)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flushErr := s.writer.Flush()
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	return flushErr
+}
@@ -0,0 +1,69 @@
+package angzarr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSinkBuffersUntilFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := NewFileSink(path, WithFlushInterval(0))
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.WriteLine("first"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("unflushed write reached the file: %q", data)
+	}
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(data) != "first\n" {
+		t.Errorf("file contents = %q, want %q", data, "first\n")
+	}
+}
+
+func TestFileSinkCloseFlushesRemainder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := NewFileSink(path, WithFlushInterval(0))
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	sink.WriteLine("last event")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "last event\n" {
+		t.Errorf("file contents after close = %q", data)
+	}
+}
+
+func TestFileSinkPeriodicFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := NewFileSink(path, WithFlushInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	defer sink.Close()
+
+	sink.WriteLine("tick")
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if data, _ := os.ReadFile(path); string(data) == "tick\n" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("periodic flush never reached the file")
+}
@@ -0,0 +1,40 @@
+package angzarr
+
+// Group is one bucket produced by GroupBy: the shared key and the items
+// that mapped to it, in input order.
+type Group[K comparable, T any] struct {
+	Key   K
+	Items []T
+}
+
+// GroupBy buckets items by key, returning the groups in first-seen key
+// order so emission order stays deterministic.  Sagas fanning out one
+// command per distinct entry use it to merge duplicates first — a player
+// winning both a main and a side pot gets one summed deposit instead of
+// two commands:
+//
+//	for _, group := range angzarr.GroupBy(potAwarded.Winners, winnerKey) {
+//		total := int64(0)
+//		for _, winner := range group.Items {
+//			total += winner.Amount
+//		}
+//		// emit one DepositFunds for group.Key at amount total
+//	}
+//
+// Byte-slice keys (aggregate roots) are not comparable; hex-encode them
+// in the key function.
+func GroupBy[K comparable, T any](items []T, key func(T) K) []Group[K, T] {
+	index := make(map[K]int, len(items))
+	var groups []Group[K, T]
+	for _, item := range items {
+		k := key(item)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group[K, T]{Key: k})
+		}
+		groups[i].Items = append(groups[i].Items, item)
+	}
+	return groups
+}
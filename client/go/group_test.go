@@ -0,0 +1,51 @@
+package angzarr
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// potWinner stands in for a PotAwarded winner entry: a player root and
+// the amount won from one pot.
+type potWinner struct {
+	root   []byte
+	amount int64
+}
+
+func TestGroupBySumsSplitPotWinners(t *testing.T) {
+	alice := []byte("aliceroot0000000")
+	bob := []byte("bobroot000000000")
+	// Alice wins the main pot and a side pot; she must get one summed
+	// deposit, not two commands.
+	winners := []potWinner{
+		{root: alice, amount: 100},
+		{root: bob, amount: 50},
+		{root: alice, amount: 25},
+	}
+
+	groups := GroupBy(winners, func(w potWinner) string {
+		return hex.EncodeToString(w.root)
+	})
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	if groups[0].Key != hex.EncodeToString(alice) {
+		t.Errorf("first group key = %q, want alice", groups[0].Key)
+	}
+	var aliceTotal int64
+	for _, w := range groups[0].Items {
+		aliceTotal += w.amount
+	}
+	if aliceTotal != 125 {
+		t.Errorf("alice total = %d, want 125", aliceTotal)
+	}
+	if len(groups[1].Items) != 1 || groups[1].Items[0].amount != 50 {
+		t.Errorf("bob group = %v", groups[1].Items)
+	}
+}
+
+func TestGroupByEmptyInput(t *testing.T) {
+	if groups := GroupBy(nil, func(w potWinner) string { return "" }); groups != nil {
+		t.Errorf("empty input produced %v", groups)
+	}
+}
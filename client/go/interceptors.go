@@ -0,0 +1,124 @@
+package angzarr
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// LoggingInterceptor returns a unary interceptor that logs each request's
+// method, domain, payload type, and duration, replacing the ad-hoc
+// logger.Info calls scattered through handlers.  Install it through
+// ServerConfig.UnaryInterceptors.
+func LoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.Duration("duration", time.Since(start)),
+		}
+		if domain, payload := requestSummary(req); domain != "" || payload != "" {
+			fields = append(fields,
+				zap.String("domain", domain), zap.String("type", payload))
+		}
+		if err != nil {
+			logger.Error("request failed", append(fields, zap.Error(err))...)
+		} else {
+			logger.Info("request handled", fields...)
+		}
+		return resp, err
+	}
+}
+
+// PanicRecoveryInterceptor returns a unary interceptor that converts a
+// handler panic into codes.Internal, logging the panic value and stack,
+// so a handler bug — a nil map write, a bad index — fails one RPC
+// instead of taking down the process.  The Run* helpers install it by
+// default; opt out with ServerConfig.DisablePanicRecovery.
+func PanicRecoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("handler panicked",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()))
+				err = status.Errorf(codes.Internal, "handler panicked: %v", r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// PanicRecoveryStreamInterceptor is the streaming counterpart of
+// PanicRecoveryInterceptor.
+func PanicRecoveryStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("stream handler panicked",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()))
+				err = status.Errorf(codes.Internal, "handler panicked: %v", r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// ConcurrencyLimitInterceptor returns a unary interceptor that admits at
+// most max requests at once.  Excess requests wait for a slot; one whose
+// context expires while waiting fails with codes.ResourceExhausted, so a
+// replay hammering a slow projector backs off instead of piling up
+// unbounded goroutines.  ServerConfig.MaxConcurrentHandlers installs
+// this automatically.
+func ConcurrencyLimitInterceptor(max int) grpc.UnaryServerInterceptor {
+	sem := make(chan struct{}, max)
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, status.Errorf(codes.ResourceExhausted,
+				"server at concurrency limit (%d): %v", max, ctx.Err())
+		}
+		defer func() { <-sem }()
+		return handler(ctx, req)
+	}
+}
+
+// requestSummary extracts the domain and payload type name from the
+// request shapes the Run* servers receive.
+func requestSummary(req any) (domain, payload string) {
+	switch r := req.(type) {
+	case *pb.ContextualCommand:
+		domain = r.GetCommand().GetCover().GetDomain()
+		for _, page := range r.GetCommand().GetPages() {
+			if cmd := page.GetCommand(); cmd != nil {
+				payload = typeNameOf(cmd.GetTypeUrl())
+				break
+			}
+		}
+	case *pb.EventBook:
+		domain = r.GetCover().GetDomain()
+		for _, page := range r.GetPages() {
+			if event := page.GetEvent(); event != nil {
+				payload = typeNameOf(event.GetTypeUrl())
+				break
+			}
+		}
+	case *pb.SagaTrigger:
+		return requestSummary(r.GetSource())
+	case *pb.ProcessManagerTrigger:
+		return requestSummary(r.GetTrigger())
+	}
+	return domain, payload
+}
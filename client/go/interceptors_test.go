@@ -0,0 +1,87 @@
+package angzarr
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimitAdmitsUpToMax(t *testing.T) {
+	limit := ConcurrencyLimitInterceptor(2)
+	release := make(chan struct{})
+	entered := make(chan struct{}, 2)
+	handler := func(ctx context.Context, req any) (any, error) {
+		entered <- struct{}{}
+		<-release
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-entered:
+		case <-time.After(time.Second):
+			t.Fatal("requests under the limit were blocked")
+		}
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimitRejectsExpiredWaiters(t *testing.T) {
+	limit := ConcurrencyLimitInterceptor(1)
+	release := make(chan struct{})
+	occupied := make(chan struct{})
+	go limit(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) {
+			close(occupied)
+			<-release
+			return nil, nil
+		})
+	<-occupied
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := limit(ctx, nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) { return nil, nil })
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("waiter past deadline got %v, want ResourceExhausted", err)
+	}
+}
+
+func TestPanicRecoveryConvertsPanicToInternal(t *testing.T) {
+	recoverer := PanicRecoveryInterceptor(zap.NewNop())
+	_, err := recoverer(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/angzarr.BusinessLogic/Handle"},
+		func(ctx context.Context, req any) (any, error) {
+			var names map[string]string
+			names["player"] = "boom" // nil map write
+			return nil, nil
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("panicking handler returned %v, want Internal", err)
+	}
+}
+
+func TestPanicRecoveryPassesThroughNormalResults(t *testing.T) {
+	recoverer := PanicRecoveryInterceptor(zap.NewNop())
+	resp, err := recoverer(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) { return "ok", nil })
+	if err != nil || resp != "ok" {
+		t.Errorf("got (%v, %v), want (ok, nil)", resp, err)
+	}
+}
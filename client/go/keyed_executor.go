@@ -0,0 +1,65 @@
+package angzarr
+
+import "sync"
+
+// KeyedExecutor serializes work per key while letting different keys run
+// concurrently: two commands against the same aggregate root (or two
+// triggers for the same correlation) are applied in order, but unrelated
+// roots never contend.  The Run* servers key dispatch by cover, so two
+// hands progress concurrently without a global lock.  A nil executor
+// runs work directly, unserialized.
+type KeyedExecutor struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock is a per-key mutex with a reference count, so idle keys are
+// dropped from the map instead of accumulating forever.
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewKeyedExecutor creates an empty keyed executor.
+func NewKeyedExecutor() *KeyedExecutor {
+	return &KeyedExecutor{locks: make(map[string]*keyLock)}
+}
+
+// Do runs fn holding the key's lock: calls with equal keys execute one at
+// a time in arrival order, calls with different keys run concurrently.
+func (e *KeyedExecutor) Do(key string, fn func() error) error {
+	if e == nil {
+		return fn()
+	}
+	lock := e.acquire(key)
+	lock.mu.Lock()
+	defer func() {
+		lock.mu.Unlock()
+		e.release(key, lock)
+	}()
+	return fn()
+}
+
+// acquire fetches or creates the key's lock and takes a reference.
+func (e *KeyedExecutor) acquire(key string) *keyLock {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	lock, ok := e.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		e.locks[key] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+// release drops a reference, removing the lock once no caller holds or
+// awaits it.
+func (e *KeyedExecutor) release(key string, lock *keyLock) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(e.locks, key)
+	}
+}
@@ -0,0 +1,82 @@
+package angzarr
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedExecutorSerializesSameKey(t *testing.T) {
+	exec := NewKeyedExecutor()
+	var inside, maxInside int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exec.Do("hand-1", func() error {
+				mu.Lock()
+				inside++
+				if inside > maxInside {
+					maxInside = inside
+				}
+				mu.Unlock()
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				inside--
+				mu.Unlock()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if maxInside != 1 {
+		t.Errorf("saw %d concurrent executions for one key, want 1", maxInside)
+	}
+	exec.mu.Lock()
+	remaining := len(exec.locks)
+	exec.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("%d key locks leaked after all work finished", remaining)
+	}
+}
+
+func TestKeyedExecutorAllowsDistinctKeysConcurrently(t *testing.T) {
+	exec := NewKeyedExecutor()
+	release := make(chan struct{})
+	entered := make(chan string, 2)
+	var wg sync.WaitGroup
+	for _, key := range []string{"hand-1", "hand-2"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			exec.Do(key, func() error {
+				entered <- key
+				<-release
+				return nil
+			})
+		}(key)
+	}
+	// Both keys must be inside their critical sections at once.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-entered:
+		case <-time.After(time.Second):
+			t.Fatal("distinct keys blocked each other")
+		}
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestNilKeyedExecutorRunsDirectly(t *testing.T) {
+	var exec *KeyedExecutor
+	ran := false
+	if err := exec.Do("any", func() error { ran = true; return nil }); err != nil {
+		t.Fatalf("nil executor: %v", err)
+	}
+	if !ran {
+		t.Error("nil executor did not run the work")
+	}
+}
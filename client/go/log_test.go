@@ -0,0 +1,42 @@
+package angzarr
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogServerStartEmitsStandardFields(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	LogServerStart(zap.New(core), "saga", "deal-cards", "50301", []string{"hand/HandOpened"})
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	if entries[0].Message != "server started" {
+		t.Errorf("message = %q, want %q", entries[0].Message, "server started")
+	}
+	fields := entries[0].ContextMap()
+	for key, want := range map[string]string{
+		"component": "saga", "name": "deal-cards", "port": "50301",
+	} {
+		if fields[key] != want {
+			t.Errorf("field %q = %v, want %q", key, fields[key], want)
+		}
+	}
+	subscribes, ok := fields["subscribes"].([]any)
+	if !ok || len(subscribes) != 1 || subscribes[0] != "hand/HandOpened" {
+		t.Errorf("subscribes = %v, want [hand/HandOpened]", fields["subscribes"])
+	}
+}
+
+func TestLogServerStartOmitsEmptySubscriptions(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	LogServerStart(zap.New(core), "aggregate", "player", "50201", nil)
+
+	if _, present := logs.All()[0].ContextMap()["subscribes"]; present {
+		t.Error("aggregate with no subscriptions logged a subscribes field")
+	}
+}
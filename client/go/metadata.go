@@ -0,0 +1,44 @@
+package angzarr
+
+import (
+	"context"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// metadataKey is the context key for cover metadata.
+type metadataKey struct{}
+
+// WithMetadata returns a context carrying the given cover metadata —
+// tenant id, actor, and similar request-scoped pairs.  Clients attach it
+// before issuing commands; servers attach the incoming command's
+// metadata before invoking business logic so handlers can read it back
+// with MetadataFromContext.
+func WithMetadata(ctx context.Context, md map[string]string) context.Context {
+	return context.WithValue(ctx, metadataKey{}, md)
+}
+
+// MetadataFromContext returns the cover metadata carried by the context,
+// or nil when none is attached.  The returned map is shared, not a copy;
+// treat it as read-only.
+func MetadataFromContext(ctx context.Context) map[string]string {
+	md, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return md
+}
+
+// propagateMetadata copies entries from src into dst's metadata map,
+// allocating it on first write and never overwriting keys dst already
+// set.  A nil dst or empty src is a no-op.
+func propagateMetadata(dst *pb.Cover, src map[string]string) {
+	if dst == nil || len(src) == 0 {
+		return
+	}
+	if dst.Metadata == nil {
+		dst.Metadata = make(map[string]string, len(src))
+	}
+	for k, v := range src {
+		if _, ok := dst.Metadata[k]; !ok {
+			dst.Metadata[k] = v
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestMetadataContextRoundTrip(t *testing.T) {
+	md := map[string]string{"tenant": "acme", "actor": "svc-portal"}
+	ctx := WithMetadata(context.Background(), md)
+	got := MetadataFromContext(ctx)
+	if got["tenant"] != "acme" || got["actor"] != "svc-portal" {
+		t.Errorf("metadata = %v", got)
+	}
+	if MetadataFromContext(context.Background()) != nil {
+		t.Error("bare context reported metadata")
+	}
+}
+
+func TestCommandMetadataPropagatesToEvents(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+
+	cc := commandFor(t, "player", wrapperspb.String("register"), nil)
+	cc.Command.Cover.Metadata = map[string]string{"tenant": "acme"}
+
+	events, err := router.Handle(context.Background(), cc)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if got := events.GetCover().GetMetadata()["tenant"]; got != "acme" {
+		t.Errorf("event cover tenant = %q, want %q", got, "acme")
+	}
+}
+
+func TestEventMetadataPropagatesToSagaCommands(t *testing.T) {
+	source := triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("handroot00000000"))
+	source.Cover.Metadata = map[string]string{"tenant": "acme"}
+
+	commands, err := dealCardsSaga().Handle(context.Background(), source, nil)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(commands) != 1 {
+		t.Fatalf("got %d commands, want 1", len(commands))
+	}
+	if got := commands[0].GetCover().GetMetadata()["tenant"]; got != "acme" {
+		t.Errorf("command cover tenant = %q, want %q", got, "acme")
+	}
+}
+
+func TestPropagateMetadataKeepsExistingKeys(t *testing.T) {
+	cover := &pb.Cover{Metadata: map[string]string{"tenant": "globex"}}
+	propagateMetadata(cover, map[string]string{"tenant": "acme", "actor": "svc"})
+	if cover.Metadata["tenant"] != "globex" {
+		t.Error("propagation overwrote an explicitly set key")
+	}
+	if cover.Metadata["actor"] != "svc" {
+		t.Error("propagation dropped a new key")
+	}
+}
+
+func TestAggregateClientStampsContextMetadata(t *testing.T) {
+	fake := &fakeBusinessLogic{responses: []*pb.EventBook{testBook(t, 0)}}
+	client := &AggregateClient{logic: fake}
+
+	ctx := WithMetadata(context.Background(), map[string]string{"tenant": "acme"})
+	if _, err := client.Execute(ctx, "player", testRoot().GetValue(), wrapperspb.String("register")); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	got := fake.calls[0].GetCommand().GetCover().GetMetadata()
+	if got["tenant"] != "acme" {
+		t.Errorf("command cover metadata = %v", got)
+	}
+}
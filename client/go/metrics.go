@@ -0,0 +1,100 @@
+package angzarr
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics holds the Prometheus instruments the routers update around
+// dispatch: command and event throughput, handler latency, and rejections
+// by status code.  Build one with NewMetrics and install it on routers
+// with WithMetrics, or set ServerConfig.MetricsRegistry to have the Run*
+// helpers do both.
+type Metrics struct {
+	commandsHandled *prometheus.CounterVec
+	eventsEmitted   *prometheus.CounterVec
+	handlerDuration *prometheus.HistogramVec
+	rejections      *prometheus.CounterVec
+}
+
+// NewMetrics creates the instrument set registered on reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+	return &Metrics{
+		commandsHandled: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "angzarr_commands_handled_total",
+			Help: "Commands dispatched through a CommandRouter, by domain and command type.",
+		}, []string{"domain", "command"}),
+		eventsEmitted: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "angzarr_events_emitted_total",
+			Help: "Event pages emitted by handlers, by domain.",
+		}, []string{"domain"}),
+		handlerDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "angzarr_handler_duration_seconds",
+			Help:    "Handler dispatch latency, by component kind and name.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"component", "name"}),
+		rejections: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "angzarr_rejections_total",
+			Help: "Dispatch failures, by domain and gRPC status code.",
+		}, []string{"domain", "code"}),
+	}
+}
+
+// observeCommand records one command dispatch outcome.
+func (m *Metrics) observeCommand(domain, command string, emittedPages int, elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.commandsHandled.WithLabelValues(domain, command).Inc()
+	m.handlerDuration.WithLabelValues("aggregate", domain).Observe(elapsed.Seconds())
+	if err != nil {
+		m.rejections.WithLabelValues(domain, status.Code(err).String()).Inc()
+		return
+	}
+	m.eventsEmitted.WithLabelValues(domain).Add(float64(emittedPages))
+}
+
+// observeDispatch records one saga or projector dispatch outcome.
+func (m *Metrics) observeDispatch(component, name string, elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.handlerDuration.WithLabelValues(component, name).Observe(elapsed.Seconds())
+	if err != nil {
+		m.rejections.WithLabelValues(name, status.Code(err).String()).Inc()
+	}
+}
+
+// WithMetrics installs the instrument set on the command router.
+func (r *CommandRouter) WithMetrics(m *Metrics) *CommandRouter {
+	r.metrics = m
+	return r
+}
+
+// WithMetrics installs the instrument set on the event router.
+func (r *EventRouter) WithMetrics(m *Metrics) *EventRouter {
+	r.metrics = m
+	return r
+}
+
+// WithMetrics installs the instrument set on the projector handler.
+func (h *ProjectorHandler) WithMetrics(m *Metrics) *ProjectorHandler {
+	h.metrics = m
+	return h
+}
+
+// RunMetricsServer serves the registry's metrics over HTTP at /metrics on
+// addr, blocking while serving.  Run it alongside a Run* gRPC helper:
+//
+//	go angzarr.RunMetricsServer(":9090", registry)
+func RunMetricsServer(addr string, registry *prometheus.Registry) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,42 @@
+package angzarr
+
+import "math"
+
+// LineItem is the shape shared by generated line-item messages: a
+// quantity and a unit price in cents.  Any message with the matching
+// getters satisfies it, so the money helpers work across domains without
+// depending on a particular example's types.
+type LineItem interface {
+	GetQuantity() int32
+	GetUnitPriceCents() int32
+}
+
+// LineTotal returns quantity × unit price for one line item, computed in
+// int64 so the product cannot silently wrap.  A total outside the int32
+// cents range is rejected with codes.InvalidArgument — a quantity large
+// enough to overflow is bad input, not a math mode.
+func LineTotal(item LineItem) (int32, error) {
+	total := int64(item.GetQuantity()) * int64(item.GetUnitPriceCents())
+	if total > math.MaxInt32 || total < math.MinInt32 {
+		return 0, InvalidArgument("line total overflows int32 cents")
+	}
+	return int32(total), nil
+}
+
+// Subtotal sums LineTotal across the items with the same overflow
+// checking, so transaction creation, receipt projection, and cart
+// checkout all agree on one computation instead of reimplementing it.
+func Subtotal[T LineItem](items []T) (int32, error) {
+	var sum int64
+	for _, item := range items {
+		total, err := LineTotal(item)
+		if err != nil {
+			return 0, err
+		}
+		sum += int64(total)
+		if sum > math.MaxInt32 || sum < math.MinInt32 {
+			return 0, InvalidArgument("subtotal overflows int32 cents")
+		}
+	}
+	return int32(sum), nil
+}
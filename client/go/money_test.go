@@ -0,0 +1,60 @@
+package angzarr
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testLineItem is a stand-in for a generated line-item message.
+type testLineItem struct {
+	quantity       int32
+	unitPriceCents int32
+}
+
+func (i *testLineItem) GetQuantity() int32       { return i.quantity }
+func (i *testLineItem) GetUnitPriceCents() int32 { return i.unitPriceCents }
+
+func TestLineTotal(t *testing.T) {
+	got, err := LineTotal(&testLineItem{quantity: 3, unitPriceCents: 250})
+	if err != nil {
+		t.Fatalf("line total: %v", err)
+	}
+	if got != 750 {
+		t.Errorf("line total = %d, want 750", got)
+	}
+}
+
+func TestLineTotalOverflow(t *testing.T) {
+	_, err := LineTotal(&testLineItem{quantity: math.MaxInt32, unitPriceCents: 2})
+	if status.Code(MapCommandError(err)) != codes.InvalidArgument {
+		t.Errorf("overflowing line total error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestSubtotal(t *testing.T) {
+	items := []*testLineItem{
+		{quantity: 2, unitPriceCents: 199},
+		{quantity: 1, unitPriceCents: 500},
+	}
+	got, err := Subtotal(items)
+	if err != nil {
+		t.Fatalf("subtotal: %v", err)
+	}
+	if got != 898 {
+		t.Errorf("subtotal = %d, want 898", got)
+	}
+}
+
+func TestSubtotalOverflowAcrossItems(t *testing.T) {
+	// Each line fits in int32; the sum does not.
+	items := []*testLineItem{
+		{quantity: 1, unitPriceCents: math.MaxInt32},
+		{quantity: 1, unitPriceCents: math.MaxInt32},
+	}
+	if _, err := Subtotal(items); err == nil {
+		t.Error("expected subtotal overflow to be rejected")
+	}
+}
@@ -0,0 +1,53 @@
+package angzarr
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// typeNameOf extracts the short message name from a type URL or
+// fully-qualified name: the segment after the final '/' and '.'.
+func typeNameOf(typeURL string) string {
+	name := typeURL
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// Name returns the short message name of m (e.g. "CardsDealt"), matching
+// the names routers use to register handlers.
+func Name(m proto.Message) string {
+	return typeNameOf(string(m.ProtoReflect().Descriptor().FullName()))
+}
+
+// TypeURL returns the full type URL anypb.New assigns when packing m
+// (e.g. "type.googleapis.com/examples.InitializeStock").  Registering
+// handlers with this URL guarantees registration and dispatch line up
+// exactly even across packages that reuse short message names; Name keeps
+// the short-suffix style for the common case.
+func TypeURL(m proto.Message) string {
+	return "type.googleapis.com/" + string(m.ProtoReflect().Descriptor().FullName())
+}
+
+// TypeURLMatches reports whether the type URL refers to the named message,
+// using suffix matching against the URL.
+//
+// Deprecated: suffix matching false-positives on names that are suffixes of
+// other names (e.g. "Table" matches a "JoinTable" URL).  Use TypeNameEquals,
+// which the routers dispatch with.
+func TypeURLMatches(typeURL, name string) bool {
+	return strings.HasSuffix(typeURL, name)
+}
+
+// TypeNameEquals reports whether the type URL's final name segment — the
+// text after the last '/' and '.' — is exactly name.  Unlike suffix
+// matching it cannot confuse "Table" with "JoinTable" or "DealCards" with
+// "RedealCards".  Router dispatch compares names this way.
+func TypeNameEquals(typeURL, name string) bool {
+	return typeNameOf(typeURL) == name
+}
@@ -0,0 +1,30 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTypeNameEquals(t *testing.T) {
+	const joinTable = "type.googleapis.com/examples.JoinTable"
+	if !TypeNameEquals(joinTable, "JoinTable") {
+		t.Error("exact name did not match")
+	}
+	// The suffix-matching failure mode this replaces.
+	if TypeNameEquals(joinTable, "Table") {
+		t.Error("JoinTable matched a command named Table")
+	}
+	if TypeNameEquals("type.googleapis.com/examples.RedealCards", "DealCards") {
+		t.Error("RedealCards matched DealCards")
+	}
+	if !TypeURLMatches(joinTable, "Table") {
+		t.Error("expected deprecated suffix matching to exhibit the false positive")
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name(wrapperspb.String("x")); got != "StringValue" {
+		t.Errorf("Name = %q, want StringValue", got)
+	}
+}
@@ -0,0 +1,22 @@
+package angzarr
+
+import (
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// NewNotification builds a rejection notification: domain and command name
+// the rejected command's target and type, reason carries the
+// human-readable explanation, and cover identifies the rejected command
+// book so the issuer can correlate the rejection with its own stream.
+// Read the fields back through the mirror's accessors — GetDomain,
+// GetCommand, GetReason, GetCover — which are nil-safe.  Rejection
+// handlers registered with OnRejected receive notifications in this
+// shape, and the TestAggregate harness accepts one in WhenRejected.
+func NewNotification(domain, command, reason string, cover *pb.Cover) *pb.Notification {
+	return &pb.Notification{
+		Cover:   cover,
+		Domain:  domain,
+		Command: command,
+		Reason:  reason,
+	}
+}
@@ -0,0 +1,35 @@
+package angzarr
+
+import (
+	"testing"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestNewNotificationSetsFields(t *testing.T) {
+	cover := &pb.Cover{Domain: "player", Root: testRoot()}
+	n := NewNotification("table", "JoinTable", "table full", cover)
+
+	if got := n.GetDomain(); got != "table" {
+		t.Errorf("domain = %q, want table", got)
+	}
+	if got := n.GetCommand(); got != "JoinTable" {
+		t.Errorf("command = %q, want JoinTable", got)
+	}
+	if got := n.GetReason(); got != "table full" {
+		t.Errorf("reason = %q, want table full", got)
+	}
+	if got := n.GetCover().GetDomain(); got != "player" {
+		t.Errorf("cover domain = %q, want player", got)
+	}
+}
+
+func TestNewNotificationAcceptsNilCover(t *testing.T) {
+	n := NewNotification("table", "JoinTable", "", nil)
+	if n.GetCover() != nil {
+		t.Errorf("cover = %v, want nil", n.GetCover())
+	}
+	if got := n.GetReason(); got != "" {
+		t.Errorf("reason = %q, want empty", got)
+	}
+}
@@ -0,0 +1,97 @@
+package angzarr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// PMTest deterministically replays a scripted sequence of trigger event
+// books through a process manager so flow logic can be unit tested without
+// a coordinator.  Destination state is served from a fake in-memory map and
+// timestamps come from a fake clock advanced by the harness, so the emitted
+// commands and accumulated process state are identical on every run.
+type PMTest struct {
+	handler      *ProcessManagerHandler
+	destinations map[string]*pb.EventBook
+	now          time.Time
+	state        *pb.EventBook
+	stateSeq     uint32
+}
+
+// NewPMTest creates a harness around the given process manager handler.
+// The fake clock starts at a fixed instant; use AdvanceClock to move it.
+func NewPMTest(handler *ProcessManagerHandler) *PMTest {
+	return &PMTest{
+		handler:      handler,
+		destinations: make(map[string]*pb.EventBook),
+		now:          time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// WithDestination registers a destination event book served to the process
+// manager when Prepare names its cover.  The book's own cover determines
+// the registration key.
+func (h *PMTest) WithDestination(book *pb.EventBook) *PMTest {
+	h.destinations[coverKey(book.GetCover())] = book
+	return h
+}
+
+// AdvanceClock moves the fake clock forward by d.
+func (h *PMTest) AdvanceClock(d time.Duration) *PMTest {
+	h.now = h.now.Add(d)
+	return h
+}
+
+// Feed runs one trigger through the full Prepare and Handle path: covers
+// returned by Prepare are resolved against the registered destinations,
+// Handle is invoked with them and the accumulated process state, and any
+// state events the handler returns are appended to that state with
+// sequences and timestamps assigned by the harness.  It returns the
+// commands the handler emitted for this step.
+func (h *PMTest) Feed(trigger *pb.EventBook) ([]*pb.CommandBook, error) {
+	var destinations []*pb.EventBook
+	for _, cover := range h.handler.Prepare(trigger) {
+		book, ok := h.destinations[coverKey(cover)]
+		if !ok {
+			return nil, fmt.Errorf("prepare requested unregistered destination %s/%s",
+				cover.GetDomain(), hex.EncodeToString(cover.GetRoot().GetValue()))
+		}
+		destinations = append(destinations, book)
+	}
+	commands, stateEvents, err := h.handler.Handle(trigger, destinations, h.state)
+	if err != nil {
+		return nil, err
+	}
+	h.appendState(stateEvents)
+	return commands, nil
+}
+
+// State returns the process manager's accumulated state stream, or nil when
+// no state events have been emitted.
+func (h *PMTest) State() *pb.EventBook {
+	return h.state
+}
+
+// appendState folds handler-emitted state events into the harness-held
+// process state, assigning contiguous sequences and fake-clock timestamps.
+func (h *PMTest) appendState(events *pb.EventBook) {
+	if events == nil || len(events.GetPages()) == 0 {
+		return
+	}
+	if h.state == nil {
+		h.state = &pb.EventBook{Cover: events.GetCover()}
+	}
+	for _, page := range events.GetPages() {
+		h.state.Pages = append(h.state.Pages, &pb.EventPage{
+			Sequence:  &pb.EventPage_Num{Num: h.stateSeq},
+			CreatedAt: timestamppb.New(h.now),
+			Event:     page.GetEvent(),
+		})
+		h.stateSeq++
+	}
+}
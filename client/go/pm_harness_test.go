@@ -0,0 +1,115 @@
+package angzarr
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// triggerBook packs msg as the single page of an event book for domain.
+func triggerBook(t *testing.T, domain string, root []byte, seq uint32, msg proto.Message) *pb.EventBook {
+	t.Helper()
+	event, err := anypb.New(msg)
+	if err != nil {
+		t.Fatalf("packing trigger: %v", err)
+	}
+	return &pb.EventBook{
+		Cover: &pb.Cover{Domain: domain, Root: &pb.UUID{Value: root}},
+		Pages: []*pb.EventPage{{
+			Sequence:  &pb.EventPage_Num{Num: seq},
+			CreatedAt: timestamppb.Now(),
+			Event:     event,
+		}},
+	}
+}
+
+// TestPMTestScriptedFlow scripts a three-step flow through a process
+// manager that waits for two distinct event types before emitting a
+// command, tracking progress via its own state stream.  StringValue and
+// Int32Value stand in for the first two flow events, BoolValue for an
+// unsubscribed type.
+func TestPMTestScriptedFlow(t *testing.T) {
+	handRoot := []byte("handroot00000000")
+	pm := NewProcessManagerHandler("pm-test-flow").
+		ListenTo("hand", "StringValue", "Int32Value").
+		WithPrepare(func(trigger *pb.EventBook) []*pb.Cover {
+			return []*pb.Cover{{Domain: "hand", Root: &pb.UUID{Value: handRoot}}}
+		})
+
+	// Track progress in process state and emit one command once both flow
+	// events have arrived.
+	pm.WithHandle(func(trigger *pb.EventBook, destinations []*pb.EventBook, state *pb.EventBook) ([]*pb.CommandBook, *pb.EventBook, error) {
+		seen := len(state.GetPages())
+		marker, err := anypb.New(wrapperspb.String("seen"))
+		if err != nil {
+			return nil, nil, err
+		}
+		stateEvents := &pb.EventBook{
+			Cover: &pb.Cover{Domain: "pm-test-flow", Root: trigger.GetCover().GetRoot()},
+			Pages: []*pb.EventPage{{Event: marker}},
+		}
+		if seen+1 < 2 {
+			return nil, stateEvents, nil
+		}
+		cmd := &pb.CommandBook{
+			Cover: trigger.GetCover(),
+			Pages: []*pb.CommandPage{{Sequence: uint32(seen + 1)}},
+		}
+		return []*pb.CommandBook{cmd}, stateEvents, nil
+	})
+
+	harness := NewPMTest(pm).WithDestination(&pb.EventBook{
+		Cover: &pb.Cover{Domain: "hand", Root: &pb.UUID{Value: handRoot}},
+	})
+
+	commands, err := harness.Feed(triggerBook(t, "hand", handRoot, 0, wrapperspb.String("started")))
+	if err != nil {
+		t.Fatalf("step 1: %v", err)
+	}
+	if len(commands) != 0 {
+		t.Fatalf("step 1: expected no commands, got %d", len(commands))
+	}
+
+	// An unsubscribed event type must not reach the handler or advance state.
+	harness.AdvanceClock(time.Second)
+	commands, err = harness.Feed(triggerBook(t, "hand", handRoot, 1, wrapperspb.Bool(true)))
+	if err != nil {
+		t.Fatalf("step 2: %v", err)
+	}
+	if len(commands) != 0 || len(harness.State().GetPages()) != 1 {
+		t.Fatalf("step 2: unsubscribed event reached handler")
+	}
+
+	harness.AdvanceClock(time.Second)
+	commands, err = harness.Feed(triggerBook(t, "hand", handRoot, 2, wrapperspb.Int32(7)))
+	if err != nil {
+		t.Fatalf("step 3: %v", err)
+	}
+	if len(commands) != 1 {
+		t.Fatalf("step 3: expected 1 command, got %d", len(commands))
+	}
+	if got := len(harness.State().GetPages()); got != 2 {
+		t.Errorf("final state has %d pages, want 2", got)
+	}
+	if got := harness.State().Pages[1].GetNum(); got != 1 {
+		t.Errorf("state sequences not contiguous: second page at %d", got)
+	}
+}
+
+func TestPMTestUnregisteredDestination(t *testing.T) {
+	pm := NewProcessManagerHandler("pm-test-missing").
+		ListenTo("hand").
+		WithPrepare(func(trigger *pb.EventBook) []*pb.Cover {
+			return []*pb.Cover{{Domain: "hand", Root: &pb.UUID{Value: []byte("missingroot00000")}}}
+		})
+	_, err := NewPMTest(pm).Feed(triggerBook(t, "hand", []byte("handroot00000000"), 0, wrapperspb.String("x")))
+	if err == nil {
+		t.Fatal("expected error for unregistered destination")
+	}
+}
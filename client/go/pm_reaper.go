@@ -0,0 +1,118 @@
+package angzarr
+
+import (
+	"time"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// PMStateEvictor is the optional eviction side of a PMStateStore.  Stores
+// that implement it have expired process streams removed by the reaper;
+// stores that don't keep their streams and only the in-memory activity
+// tracking is dropped.
+type PMStateEvictor interface {
+	Evict(name, correlationID string) error
+}
+
+// WithReaper evicts processes that have gone maxAge without a trigger — a
+// hand whose player disconnected before PotAwarded, say — so the activity
+// map and any persisted state cannot grow without bound.  The server
+// sweeps periodically (see WithReaperInterval) while serving; each sweep
+// drops expired correlations from tracking, runs the reap hook if one is
+// registered, and evicts persisted state when the store supports it.
+func (h *ProcessManagerHandler) WithReaper(maxAge time.Duration) *ProcessManagerHandler {
+	h.reaperMaxAge = maxAge
+	if h.lastSeen == nil {
+		h.lastSeen = make(map[string]time.Time)
+	}
+	return h
+}
+
+// WithReaperInterval overrides how often the reaper sweeps.  The default
+// is a tenth of the max age, floored at one second.
+func (h *ProcessManagerHandler) WithReaperInterval(interval time.Duration) *ProcessManagerHandler {
+	h.reaperInterval = interval
+	return h
+}
+
+// WithReapHook runs the hook for each process being evicted, with its
+// correlation id and last persisted state (nil without a state store),
+// before the state is dropped.  This is where a PM emits a timeout
+// command or event so the rest of the system learns the process died
+// rather than completed.
+func (h *ProcessManagerHandler) WithReapHook(hook func(correlationID string, state *pb.EventBook)) *ProcessManagerHandler {
+	h.reapHook = hook
+	return h
+}
+
+// touchProcess records trigger activity for a correlation.
+func (h *ProcessManagerHandler) touchProcess(correlation string) {
+	h.seenMu.Lock()
+	defer h.seenMu.Unlock()
+	h.lastSeen[correlation] = time.Now()
+}
+
+// sweep evicts every tracked process idle longer than the max age, as of
+// now.  It is the reaper's unit of work, factored out so tests can drive
+// it with a chosen time instead of waiting out real intervals.
+func (h *ProcessManagerHandler) sweep(now time.Time) {
+	h.seenMu.Lock()
+	var expired []string
+	for correlation, seen := range h.lastSeen {
+		if now.Sub(seen) > h.reaperMaxAge {
+			expired = append(expired, correlation)
+			delete(h.lastSeen, correlation)
+		}
+	}
+	h.seenMu.Unlock()
+
+	for _, correlation := range expired {
+		var state *pb.EventBook
+		if h.states != nil {
+			state, _ = h.states.Load(h.name, correlation)
+		}
+		if h.reapHook != nil {
+			h.reapHook(correlation, state)
+		}
+		if evictor, ok := h.states.(PMStateEvictor); ok {
+			evictor.Evict(h.name, correlation)
+		}
+	}
+}
+
+// reaperTick returns the sweep cadence.
+func (h *ProcessManagerHandler) reaperTick() time.Duration {
+	if h.reaperInterval > 0 {
+		return h.reaperInterval
+	}
+	tick := h.reaperMaxAge / 10
+	if tick < time.Second {
+		tick = time.Second
+	}
+	return tick
+}
+
+// startReaper launches the periodic sweep, returning a stop function, or
+// nil when no reaper is configured.  RunProcessManagerServer calls this
+// for the lifetime of the server.
+func (h *ProcessManagerHandler) startReaper() (stop func()) {
+	if h.reaperMaxAge <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(h.reaperTick())
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				h.sweep(time.Now())
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,84 @@
+package angzarr
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestReaperEvictsIdleProcesses(t *testing.T) {
+	var sawIn []int
+	store := NewMemoryPMStateStore()
+	pm := statefulPM(t, &sawIn).
+		WithStateStore(store).
+		WithReaper(time.Minute)
+
+	trigger := triggerBook(t, "hand", []byte("handroot00000000"), 0, wrapperspb.String("flop"))
+	trigger.Cover.CorrelationId = "hand-flow-idle"
+	if _, _, err := pm.Handle(trigger, nil, nil); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	pm.sweep(time.Now().Add(2 * time.Minute))
+
+	if stored, _ := store.Load("pm-state-test", "hand-flow-idle"); stored != nil {
+		t.Error("expired process state survived the sweep")
+	}
+	pm.seenMu.Lock()
+	defer pm.seenMu.Unlock()
+	if len(pm.lastSeen) != 0 {
+		t.Errorf("activity map still tracks %d processes", len(pm.lastSeen))
+	}
+}
+
+func TestReaperKeepsActiveProcesses(t *testing.T) {
+	var sawIn []int
+	store := NewMemoryPMStateStore()
+	pm := statefulPM(t, &sawIn).
+		WithStateStore(store).
+		WithReaper(time.Minute)
+
+	trigger := triggerBook(t, "hand", []byte("handroot00000000"), 0, wrapperspb.String("flop"))
+	trigger.Cover.CorrelationId = "hand-flow-live"
+	if _, _, err := pm.Handle(trigger, nil, nil); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	pm.sweep(time.Now().Add(30 * time.Second))
+
+	if stored, _ := store.Load("pm-state-test", "hand-flow-live"); stored == nil {
+		t.Error("active process was reaped before its max age")
+	}
+}
+
+func TestReapHookSeesStateBeforeEviction(t *testing.T) {
+	var sawIn []int
+	var reaped []string
+	var reapedPages int
+	store := NewMemoryPMStateStore()
+	pm := statefulPM(t, &sawIn).
+		WithStateStore(store).
+		WithReaper(time.Minute).
+		WithReapHook(func(correlation string, state *pb.EventBook) {
+			reaped = append(reaped, correlation)
+			reapedPages = len(state.GetPages())
+		})
+
+	trigger := triggerBook(t, "hand", []byte("handroot00000000"), 0, wrapperspb.String("flop"))
+	trigger.Cover.CorrelationId = "hand-flow-hook"
+	if _, _, err := pm.Handle(trigger, nil, nil); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	pm.sweep(time.Now().Add(2 * time.Minute))
+
+	if len(reaped) != 1 || reaped[0] != "hand-flow-hook" {
+		t.Fatalf("reaped correlations = %v, want [hand-flow-hook]", reaped)
+	}
+	if reapedPages != 1 {
+		t.Errorf("hook saw %d state pages, want 1", reapedPages)
+	}
+}
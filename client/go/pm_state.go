@@ -0,0 +1,74 @@
+package angzarr
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// ProcessRoot derives the process manager's own aggregate root from a
+// correlation id: the first 16 bytes of the id's SHA-256.  The mapping is
+// deterministic, so every trigger carrying the same correlation id lands
+// on the same state stream without the coordinator tracking a lookup
+// table.
+func ProcessRoot(correlationID string) *pb.UUID {
+	sum := sha256.Sum256([]byte(correlationID))
+	return &pb.UUID{Value: sum[:16]}
+}
+
+// PMStateStore persists a process manager's own event stream, keyed by
+// the PM's name and the triggering correlation id.  Load returns nil
+// (not an error) for a process with no history yet.
+type PMStateStore interface {
+	Load(name, correlationID string) (*pb.EventBook, error)
+	Append(name, correlationID string, events *pb.EventBook) error
+}
+
+// MemoryPMStateStore keeps process state in memory, for tests and
+// single-instance deployments.  Safe for concurrent use.
+type MemoryPMStateStore struct {
+	mu      sync.Mutex
+	streams map[string]*pb.EventBook
+}
+
+// NewMemoryPMStateStore creates an empty in-memory state store.
+func NewMemoryPMStateStore() *MemoryPMStateStore {
+	return &MemoryPMStateStore{streams: make(map[string]*pb.EventBook)}
+}
+
+func pmStreamKey(name, correlationID string) string {
+	return name + "/" + correlationID
+}
+
+// Load returns the accumulated state stream for the process, or nil when
+// nothing has been appended.
+func (s *MemoryPMStateStore) Load(name, correlationID string) (*pb.EventBook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams[pmStreamKey(name, correlationID)], nil
+}
+
+// Append adds the pages of events to the process's stream, creating it on
+// first write.
+func (s *MemoryPMStateStore) Append(name, correlationID string, events *pb.EventBook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := pmStreamKey(name, correlationID)
+	stream := s.streams[key]
+	if stream == nil {
+		stream = &pb.EventBook{Cover: events.GetCover()}
+		s.streams[key] = stream
+	}
+	stream.Pages = append(stream.Pages, events.GetPages()...)
+	return nil
+}
+
+// Evict drops the process's stream, satisfying PMStateEvictor so the
+// reaper can reclaim expired processes.
+func (s *MemoryPMStateStore) Evict(name, correlationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.streams, pmStreamKey(name, correlationID))
+	return nil
+}
@@ -0,0 +1,111 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestProcessRootIsDeterministic(t *testing.T) {
+	a := ProcessRoot("order-42")
+	b := ProcessRoot("order-42")
+	if len(a.GetValue()) != 16 {
+		t.Fatalf("root is %d bytes, want 16", len(a.GetValue()))
+	}
+	if string(a.GetValue()) != string(b.GetValue()) {
+		t.Error("same correlation id derived different roots")
+	}
+	if string(a.GetValue()) == string(ProcessRoot("order-43").GetValue()) {
+		t.Error("distinct correlation ids collided")
+	}
+}
+
+// statefulPM counts triggers in its own stream: each invocation appends
+// one marker event and reports how many it saw coming in.
+func statefulPM(t *testing.T, sawIn *[]int) *ProcessManagerHandler {
+	t.Helper()
+	return NewProcessManagerHandler("pm-state-test").
+		ListenTo("hand", "StringValue").
+		WithHandle(func(trigger *pb.EventBook, destinations []*pb.EventBook, state *pb.EventBook) ([]*pb.CommandBook, *pb.EventBook, error) {
+			*sawIn = append(*sawIn, len(state.GetPages()))
+			marker, err := anypb.New(wrapperspb.String("seen"))
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, &pb.EventBook{Pages: []*pb.EventPage{{Event: marker}}}, nil
+		})
+}
+
+func TestPMStateStreamCarriesAcrossTriggers(t *testing.T) {
+	var sawIn []int
+	pm := statefulPM(t, &sawIn).WithStateStore(NewMemoryPMStateStore())
+
+	trigger := triggerBook(t, "hand", []byte("handroot00000000"), 0, wrapperspb.String("flop"))
+	trigger.Cover.CorrelationId = "hand-flow-1"
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := pm.Handle(trigger, nil, nil); err != nil {
+			t.Fatalf("handle %d: %v", i, err)
+		}
+	}
+	if len(sawIn) != 2 || sawIn[0] != 0 || sawIn[1] != 1 {
+		t.Errorf("state pages seen per trigger = %v, want [0 1]", sawIn)
+	}
+}
+
+func TestPMStateEventsStampedForOwnStream(t *testing.T) {
+	var sawIn []int
+	store := NewMemoryPMStateStore()
+	pm := statefulPM(t, &sawIn).WithStateStore(store)
+
+	trigger := triggerBook(t, "hand", []byte("handroot00000000"), 0, wrapperspb.String("flop"))
+	trigger.Cover.CorrelationId = "hand-flow-2"
+
+	_, events, err := pm.Handle(trigger, nil, nil)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if got := events.GetCover().GetDomain(); got != "pm-state-test" {
+		t.Errorf("state stream domain = %q, want the PM's name", got)
+	}
+	wantRoot := ProcessRoot("hand-flow-2").GetValue()
+	if string(events.GetCover().GetRoot().GetValue()) != string(wantRoot) {
+		t.Error("state stream root not derived from the correlation id")
+	}
+	if got := events.GetPages()[0].GetNum(); got != 0 {
+		t.Errorf("first state event sequence = %d, want 0", got)
+	}
+
+	if _, events, err = pm.Handle(trigger, nil, nil); err != nil {
+		t.Fatalf("second handle: %v", err)
+	}
+	if got := events.GetPages()[0].GetNum(); got != 1 {
+		t.Errorf("second state event sequence = %d, want 1", got)
+	}
+
+	stored, err := store.Load("pm-state-test", "hand-flow-2")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(stored.GetPages()) != 2 {
+		t.Errorf("stored stream has %d pages, want 2", len(stored.GetPages()))
+	}
+}
+
+func TestPMWithoutCorrelationSkipsStateStore(t *testing.T) {
+	var sawIn []int
+	store := NewMemoryPMStateStore()
+	pm := statefulPM(t, &sawIn).WithStateStore(store)
+
+	trigger := triggerBook(t, "hand", []byte("handroot00000000"), 0, wrapperspb.String("flop"))
+	if _, _, err := pm.Handle(trigger, nil, nil); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	stored, _ := store.Load("pm-state-test", "")
+	if stored != nil {
+		t.Error("uncorrelated trigger wrote to the state store")
+	}
+}
@@ -0,0 +1,208 @@
+// Package poker evaluates Texas hold'em hands, shared by the hand
+// aggregate's AwardPot handler and the feature tests so winner
+// determination cannot drift between production and test code.
+package poker
+
+import "sort"
+
+// Card is the shape shared by generated card messages: a rank from 2
+// (deuce) through 14 (ace) and a suit name.  Any message with the
+// matching getters satisfies it, so the evaluator works against proto
+// types without depending on a particular example's package.
+type Card interface {
+	GetRank() int32
+	GetSuit() string
+}
+
+// Category orders hand categories from weakest to strongest; a higher
+// category always beats a lower one regardless of ranks.
+type Category int32
+
+const (
+	HighCard Category = iota
+	Pair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+	RoyalFlush
+)
+
+// String names the category for logs and notifications.
+func (c Category) String() string {
+	names := [...]string{"high card", "pair", "two pair", "three of a kind",
+		"straight", "flush", "full house", "four of a kind",
+		"straight flush", "royal flush"}
+	if int(c) < len(names) {
+		return names[c]
+	}
+	return "unknown"
+}
+
+// HandRanking is an evaluated hand: its category and the ranks that break
+// ties within it, most significant first — the pair rank before the
+// kickers, the high card of a straight, the five flush ranks descending.
+type HandRanking struct {
+	Category Category
+	Ranks    []int32
+}
+
+// Evaluate returns the ranking of the best five-card hand assembled from
+// the hole and community cards.  All rank types are covered, including
+// the wheel (A-2-3-4-5, a straight with high card 5) and the royal flush.
+func Evaluate[C Card](hole, community []C) *HandRanking {
+	cards := make([]Card, 0, len(hole)+len(community))
+	for _, c := range hole {
+		cards = append(cards, c)
+	}
+	for _, c := range community {
+		cards = append(cards, c)
+	}
+	var best *HandRanking
+	forEachFive(cards, func(five []Card) {
+		ranking := evaluateFive(five)
+		if best == nil || Compare(ranking, best) > 0 {
+			best = ranking
+		}
+	})
+	return best
+}
+
+// Score packs the ranking into one integer that orders exactly as
+// Compare does, for storage in projections and cross-language
+// comparison.  The layout is fixed: the category in bits 20–23, then
+// each tie-break rank in descending significance, four bits apiece —
+// Ranks[0] in bits 16–19 down to Ranks[4] in bits 0–3.  Positions a
+// category does not use (a straight carries only its high card) are
+// zero.  A rank always fits four bits (2–14), so two one-pair hands
+// differing only in the last kicker — AA K Q J against AA K Q T —
+// produce distinct scores instead of the tie the old
+// category-plus-pair-rank scheme reported.
+func (r *HandRanking) Score() int32 {
+	score := int32(r.Category) << 20
+	for i, rank := range r.Ranks {
+		if i >= 5 {
+			break
+		}
+		score |= rank << (16 - 4*i)
+	}
+	return score
+}
+
+// Compare orders two rankings: positive when a beats b, negative when b
+// beats a, zero for an exact tie (a split pot).  It is defined as the
+// ordering of Score, so the packed form and the comparison can never
+// disagree.
+func Compare(a, b *HandRanking) int {
+	switch as, bs := a.Score(), b.Score(); {
+	case as > bs:
+		return 1
+	case as < bs:
+		return -1
+	}
+	return 0
+}
+
+// forEachFive visits every five-card combination of cards.
+func forEachFive(cards []Card, visit func([]Card)) {
+	n := len(cards)
+	if n <= 5 {
+		visit(cards)
+		return
+	}
+	five := make([]Card, 5)
+	var choose func(start, depth int)
+	choose = func(start, depth int) {
+		if depth == 5 {
+			visit(five)
+			return
+		}
+		for i := start; i <= n-(5-depth); i++ {
+			five[depth] = cards[i]
+			choose(i+1, depth+1)
+		}
+	}
+	choose(0, 0)
+}
+
+// evaluateFive ranks exactly five cards.
+func evaluateFive(cards []Card) *HandRanking {
+	ranks := make([]int32, len(cards))
+	flush := true
+	for i, c := range cards {
+		ranks[i] = c.GetRank()
+		if c.GetSuit() != cards[0].GetSuit() {
+			flush = false
+		}
+	}
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i] > ranks[j] })
+
+	straightHigh, straight := straightHighCard(ranks)
+	switch {
+	case straight && flush && straightHigh == 14:
+		return &HandRanking{Category: RoyalFlush, Ranks: []int32{straightHigh}}
+	case straight && flush:
+		return &HandRanking{Category: StraightFlush, Ranks: []int32{straightHigh}}
+	}
+
+	// Group ranks by multiplicity: quads, trips, pairs, singles.
+	counts := map[int32]int32{}
+	for _, r := range ranks {
+		counts[r]++
+	}
+	byCount := func(n int32) []int32 {
+		var out []int32
+		for r, c := range counts {
+			if c == n {
+				out = append(out, r)
+			}
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i] > out[j] })
+		return out
+	}
+	quads, trips, pairs, singles := byCount(4), byCount(3), byCount(2), byCount(1)
+
+	switch {
+	case len(quads) == 1:
+		return &HandRanking{Category: FourOfAKind, Ranks: append(quads, singles...)}
+	case len(trips) == 1 && len(pairs) == 1:
+		return &HandRanking{Category: FullHouse, Ranks: []int32{trips[0], pairs[0]}}
+	case flush:
+		return &HandRanking{Category: Flush, Ranks: ranks}
+	case straight:
+		return &HandRanking{Category: Straight, Ranks: []int32{straightHigh}}
+	case len(trips) == 1:
+		return &HandRanking{Category: ThreeOfAKind, Ranks: append(trips, singles...)}
+	case len(pairs) == 2:
+		return &HandRanking{Category: TwoPair, Ranks: append(pairs, singles...)}
+	case len(pairs) == 1:
+		return &HandRanking{Category: Pair, Ranks: append(pairs, singles...)}
+	}
+	return &HandRanking{Category: HighCard, Ranks: ranks}
+}
+
+// straightHighCard reports whether the descending ranks form a straight
+// and, if so, its high card.  The wheel (A-5-4-3-2) counts with high
+// card 5, the ace playing low.
+func straightHighCard(desc []int32) (int32, bool) {
+	if len(desc) != 5 {
+		return 0, false
+	}
+	run := true
+	for i := 1; i < 5; i++ {
+		if desc[i] != desc[i-1]-1 {
+			run = false
+			break
+		}
+	}
+	if run {
+		return desc[0], true
+	}
+	if desc[0] == 14 && desc[1] == 5 && desc[2] == 4 && desc[3] == 3 && desc[4] == 2 {
+		return 5, true
+	}
+	return 0, false
+}
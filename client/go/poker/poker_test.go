@@ -0,0 +1,179 @@
+package poker
+
+import (
+	"strings"
+	"testing"
+)
+
+// card is a stand-in for a generated card message.
+type card struct {
+	rank int32
+	suit string
+}
+
+func (c card) GetRank() int32  { return c.rank }
+func (c card) GetSuit() string { return c.suit }
+
+var rankChars = map[byte]int32{
+	'2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7, '8': 8, '9': 9,
+	'T': 10, 'J': 11, 'Q': 12, 'K': 13, 'A': 14,
+}
+
+// hand parses space-separated cards like "AH KD 5C".
+func hand(t *testing.T, spec string) []card {
+	t.Helper()
+	var cards []card
+	for _, field := range strings.Fields(spec) {
+		rank, ok := rankChars[field[0]]
+		if !ok || len(field) != 2 {
+			t.Fatalf("bad card %q", field)
+		}
+		cards = append(cards, card{rank: rank, suit: field[1:]})
+	}
+	return cards
+}
+
+// evaluate splits a seven-card spec into two hole cards and five
+// community cards and runs the evaluator.
+func evaluate(t *testing.T, spec string) *HandRanking {
+	t.Helper()
+	cards := hand(t, spec)
+	return Evaluate(cards[:2], cards[2:])
+}
+
+func TestEvaluateCategories(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want Category
+	}{
+		{name: "royal flush", spec: "AH KH QH JH TH 2C 3D", want: RoyalFlush},
+		{name: "straight flush", spec: "9H KH QH JH TH 2C 3D", want: StraightFlush},
+		{name: "four of a kind", spec: "9H 9C 9D 9S TH 2C 3D", want: FourOfAKind},
+		{name: "full house", spec: "9H 9C 9D TS TH 2C 3D", want: FullHouse},
+		{name: "flush", spec: "2H 5H 9H JH KH 3C 4D", want: Flush},
+		{name: "straight", spec: "5H 6C 7D 8S 9H 2C AD", want: Straight},
+		{name: "wheel straight", spec: "AH 2C 3D 4S 5H 9C JD", want: Straight},
+		{name: "three of a kind", spec: "9H 9C 9D 2S TH 4C 6D", want: ThreeOfAKind},
+		{name: "two pair", spec: "9H 9C TD TS 2H 4C 6D", want: TwoPair},
+		{name: "pair", spec: "9H 9C TD 2S 4H 6C KD", want: Pair},
+		{name: "high card", spec: "2H 4C 6D 8S TH QC AD", want: HighCard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluate(t, tt.spec); got.Category != tt.want {
+				t.Errorf("category = %v, want %v", got.Category, tt.want)
+			}
+		})
+	}
+}
+
+func TestWheelStraightRanksBelowSixHigh(t *testing.T) {
+	wheel := evaluate(t, "AH 2C 3D 4S 5H 9C JD")
+	sixHigh := evaluate(t, "2H 3C 4D 5S 6H 9C JD")
+	if Compare(sixHigh, wheel) <= 0 {
+		t.Error("six-high straight should beat the wheel")
+	}
+	if wheel.Ranks[0] != 5 {
+		t.Errorf("wheel high card = %d, want 5 (ace plays low)", wheel.Ranks[0])
+	}
+}
+
+func TestCompareBreaksTiesOnKickers(t *testing.T) {
+	tests := []struct {
+		name     string
+		stronger string
+		weaker   string
+	}{
+		{
+			name:     "pair kicker",
+			stronger: "KH KC AD 8S 5H 3C 2D",
+			weaker:   "KD KS QD 8C 5S 3H 2C",
+		},
+		{
+			name:     "second kicker",
+			stronger: "KH KC AD 9S 5H 3C 2D",
+			weaker:   "KD KS AH 8C 5S 3H 2C",
+		},
+		{
+			name:     "two pair kicker",
+			stronger: "KH KC 9D 9S AH 3C 2D",
+			weaker:   "KD KS 9H 9C QS 3H 2C",
+		},
+		{
+			name:     "quads kicker",
+			stronger: "9H 9C 9D 9S AH 3C 2D",
+			weaker:   "9H 9C 9D 9S KH 3C 2D",
+		},
+		{
+			name:     "flush second card",
+			stronger: "AH KH 9H 5H 2H 3C 4D",
+			weaker:   "AS QS 9S 5S 2S 3H 4C",
+		},
+		{
+			name:     "high card last kicker",
+			stronger: "AH QC TD 8S 5H 2C 3D",
+			weaker:   "AD QS TH 8C 4S 2H 3C",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stronger, weaker := evaluate(t, tt.stronger), evaluate(t, tt.weaker)
+			if stronger.Category != weaker.Category {
+				t.Fatalf("categories differ: %v vs %v", stronger.Category, weaker.Category)
+			}
+			if Compare(stronger, weaker) <= 0 {
+				t.Errorf("Compare(%v, %v) = %d, want > 0", stronger.Ranks, weaker.Ranks, Compare(stronger, weaker))
+			}
+		})
+	}
+}
+
+func TestCompareTiesOnIdenticalRanks(t *testing.T) {
+	// Both players play the board's broadway straight: a split pot.
+	a := evaluate(t, "2H 3C TD JS QH KC AD")
+	b := evaluate(t, "2S 3D TD JS QH KC AD")
+	if Compare(a, b) != 0 {
+		t.Errorf("identical hands compare %d, want 0", Compare(a, b))
+	}
+}
+
+func TestEvaluatePicksBestFiveOfSeven(t *testing.T) {
+	// The flush is there, but the full house in the same seven cards
+	// must win out.
+	got := evaluate(t, "9H 9C 9D TS TH 2H 3H")
+	if got.Category != FullHouse {
+		t.Errorf("category = %v, want %v", got.Category, FullHouse)
+	}
+}
+
+func TestScoreLayout(t *testing.T) {
+	// Pair of aces, K-Q-J kickers: category 1 in bits 20-23, then
+	// 14, 13, 12, 11 in descending four-bit fields.
+	ranking := &HandRanking{Category: Pair, Ranks: []int32{14, 13, 12, 11}}
+	want := int32(Pair)<<20 | 14<<16 | 13<<12 | 12<<8 | 11<<4
+	if got := ranking.Score(); got != want {
+		t.Errorf("score = %#x, want %#x", got, want)
+	}
+}
+
+func TestScoreResolvesLastKicker(t *testing.T) {
+	// From the showdown tables: both players hold aces with K-Q behind;
+	// the jack kicker must beat the ten.
+	jack := evaluate(t, "AH AC KD QS JH 3C 2D")
+	ten := evaluate(t, "AD AS KH QC TS 3H 2C")
+	if jack.Score() <= ten.Score() {
+		t.Errorf("AA-KQJ score %#x not above AA-KQT score %#x", jack.Score(), ten.Score())
+	}
+	if Compare(jack, ten) <= 0 {
+		t.Error("AA K Q J should beat AA K Q T")
+	}
+}
+
+func TestScoreTiesMatchCompareTies(t *testing.T) {
+	a := evaluate(t, "2H 3C TD JS QH KC AD")
+	b := evaluate(t, "2S 3D TD JS QH KC AD")
+	if a.Score() != b.Score() {
+		t.Errorf("split-pot hands scored %#x vs %#x", a.Score(), b.Score())
+	}
+}
@@ -0,0 +1,71 @@
+package poker
+
+import "sort"
+
+// Pot is one pot at showdown: its chips and the players whose hands can
+// win it.  The first pot computed for a hand is the main pot; each
+// all-in cap above it opens a side pot.
+type Pot struct {
+	// Type is "main" for the first pot and "side" for the rest,
+	// matching the PotType vocabulary of the PotAwarded events.
+	Type string
+	// Amount is the pot's chips.
+	Amount int64
+	// Eligible lists the players who contributed the full layer this
+	// pot covers, sorted for deterministic event payloads.  A pot with
+	// one eligible player is an uncalled bet, returned rather than
+	// contested.
+	Eligible []string
+}
+
+// ComputePots splits the hand's contributions into main and side pots.
+// Each all-in player's total contribution caps a layer: everyone pays
+// into a layer up to its cap, and only players who covered the full
+// layer are eligible to win it.  Chips contributed above the highest
+// all-in cap form the final pot.  Three players in for 50, 200, and 300
+// with the first two all-in yield a 150 main pot all three can win, a
+// 300 side pot between the larger two, and a 100 uncalled pot for the
+// biggest stack.
+func ComputePots(contributions map[string]int64, allIn map[string]bool) []Pot {
+	var caps []int64
+	seen := map[int64]bool{}
+	var highest int64
+	for player, chips := range contributions {
+		if chips > highest {
+			highest = chips
+		}
+		if allIn[player] && chips > 0 && !seen[chips] {
+			caps = append(caps, chips)
+			seen[chips] = true
+		}
+	}
+	sort.Slice(caps, func(i, j int) bool { return caps[i] < caps[j] })
+	if highest > 0 && (len(caps) == 0 || caps[len(caps)-1] < highest) {
+		caps = append(caps, highest)
+	}
+
+	var pots []Pot
+	floor := int64(0)
+	for _, cap := range caps {
+		pot := Pot{Type: "side"}
+		if len(pots) == 0 {
+			pot.Type = "main"
+		}
+		for player, chips := range contributions {
+			if chips > floor {
+				in := chips
+				if in > cap {
+					in = cap
+				}
+				pot.Amount += in - floor
+			}
+			if chips >= cap {
+				pot.Eligible = append(pot.Eligible, player)
+			}
+		}
+		sort.Strings(pot.Eligible)
+		pots = append(pots, pot)
+		floor = cap
+	}
+	return pots
+}
@@ -0,0 +1,61 @@
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputePotsSingleMainPot(t *testing.T) {
+	pots := ComputePots(map[string]int64{"alice": 100, "bob": 100}, nil)
+	want := []Pot{{Type: "main", Amount: 200, Eligible: []string{"alice", "bob"}}}
+	if !reflect.DeepEqual(pots, want) {
+		t.Errorf("pots = %+v, want %+v", pots, want)
+	}
+}
+
+func TestComputePotsThreeWayAllIns(t *testing.T) {
+	pots := ComputePots(
+		map[string]int64{"alice": 50, "bob": 200, "carol": 300},
+		map[string]bool{"alice": true, "bob": true},
+	)
+	want := []Pot{
+		{Type: "main", Amount: 150, Eligible: []string{"alice", "bob", "carol"}},
+		{Type: "side", Amount: 300, Eligible: []string{"bob", "carol"}},
+		{Type: "side", Amount: 100, Eligible: []string{"carol"}},
+	}
+	if !reflect.DeepEqual(pots, want) {
+		t.Errorf("pots = %+v, want %+v", pots, want)
+	}
+}
+
+func TestComputePotsEqualAllInsShareOneLayer(t *testing.T) {
+	pots := ComputePots(
+		map[string]int64{"alice": 100, "bob": 100, "carol": 250},
+		map[string]bool{"alice": true, "bob": true},
+	)
+	want := []Pot{
+		{Type: "main", Amount: 300, Eligible: []string{"alice", "bob", "carol"}},
+		{Type: "side", Amount: 150, Eligible: []string{"carol"}},
+	}
+	if !reflect.DeepEqual(pots, want) {
+		t.Errorf("pots = %+v, want %+v", pots, want)
+	}
+}
+
+func TestComputePotsAllInCoveringTheTable(t *testing.T) {
+	// The all-in player covered everyone: no side pot opens.
+	pots := ComputePots(
+		map[string]int64{"alice": 200, "bob": 200},
+		map[string]bool{"alice": true},
+	)
+	want := []Pot{{Type: "main", Amount: 400, Eligible: []string{"alice", "bob"}}}
+	if !reflect.DeepEqual(pots, want) {
+		t.Errorf("pots = %+v, want %+v", pots, want)
+	}
+}
+
+func TestComputePotsNoContributions(t *testing.T) {
+	if pots := ComputePots(nil, nil); pots != nil {
+		t.Errorf("empty hand produced pots %+v", pots)
+	}
+}
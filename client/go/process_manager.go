@@ -0,0 +1,232 @@
+package angzarr
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// Wildcard subscribes a process manager to every event type within a domain.
+// ListenTo with no event names is shorthand for the same thing.
+const Wildcard = "*"
+
+// PMPrepareFunc inspects a trigger event book and returns the covers of the
+// destination aggregates whose state the process manager needs before
+// handling.
+type PMPrepareFunc func(trigger *pb.EventBook) []*pb.Cover
+
+// PMHandleFunc reacts to a trigger event book given the prepared destination
+// state and the process manager's own state stream, returning the commands
+// to emit and optionally events to append to the process state.
+type PMHandleFunc func(trigger *pb.EventBook, destinations []*pb.EventBook, processState *pb.EventBook) ([]*pb.CommandBook, *pb.EventBook, error)
+
+// ProcessManagerHandler coordinates a long-running process that listens to
+// events from one or more domains and emits commands in response.  Build one
+// with NewProcessManagerHandler and register subscriptions with ListenTo.
+type ProcessManagerHandler struct {
+	name          string
+	subscriptions map[string]map[string]bool
+	prepare       PMPrepareFunc
+	handle        PMHandleFunc
+	deadLetters   DeadLetterSink
+	states        PMStateStore
+
+	reaperMaxAge   time.Duration
+	reaperInterval time.Duration
+	reapHook       func(correlationID string, state *pb.EventBook)
+	seenMu         sync.Mutex
+	lastSeen       map[string]time.Time
+}
+
+// NewProcessManagerHandler creates a process manager handler with the given
+// name.  The name identifies the PM to the coordinator and in logs.
+func NewProcessManagerHandler(name string) *ProcessManagerHandler {
+	return &ProcessManagerHandler{
+		name:          name,
+		subscriptions: make(map[string]map[string]bool),
+	}
+}
+
+// Name returns the process manager's registered name.
+func (h *ProcessManagerHandler) Name() string {
+	return h.name
+}
+
+// ListenTo subscribes the process manager to events from domain.  With an
+// explicit list of event type names only those types are delivered.  With no
+// event names, or with the single name Wildcard ("*"), every event type in
+// the domain is delivered; this avoids enumerating each type and silently
+// dropping ones added later.  Repeated calls for the same domain merge, and
+// a wildcard registration subsumes any explicit list.
+func (h *ProcessManagerHandler) ListenTo(domain string, events ...string) *ProcessManagerHandler {
+	set := h.subscriptions[domain]
+	if set == nil {
+		set = make(map[string]bool)
+		h.subscriptions[domain] = set
+	}
+	if len(events) == 0 {
+		set[Wildcard] = true
+		return h
+	}
+	for _, event := range events {
+		set[event] = true
+	}
+	return h
+}
+
+// ListensTo reports whether the process manager subscribes to the given
+// event type in the given domain, honoring wildcard registrations.
+func (h *ProcessManagerHandler) ListensTo(domain, typeName string) bool {
+	set, ok := h.subscriptions[domain]
+	if !ok {
+		return false
+	}
+	return set[Wildcard] || set[typeName]
+}
+
+// WithPrepare registers the prepare phase that names the destination covers
+// to fetch before Handle runs.
+func (h *ProcessManagerHandler) WithPrepare(prepare PMPrepareFunc) *ProcessManagerHandler {
+	h.prepare = prepare
+	return h
+}
+
+// WithHandle registers the reaction invoked for each subscribed trigger.
+func (h *ProcessManagerHandler) WithHandle(handle PMHandleFunc) *ProcessManagerHandler {
+	h.handle = handle
+	return h
+}
+
+// Prepare runs the registered prepare phase, returning no covers when none
+// is registered.  Covers naming the same destination — typically because
+// several trigger pages reference the same aggregate — are collapsed to
+// one, and the result is sorted by domain and root so the coordinator
+// fetches each destination once, in a deterministic order.
+func (h *ProcessManagerHandler) Prepare(trigger *pb.EventBook) []*pb.Cover {
+	if h.prepare == nil {
+		return nil
+	}
+	return dedupeCovers(h.prepare(trigger))
+}
+
+// dedupeCovers drops covers repeating an earlier domain+root pair and
+// sorts the survivors by that key.
+func dedupeCovers(covers []*pb.Cover) []*pb.Cover {
+	if len(covers) < 2 {
+		return covers
+	}
+	seen := make(map[string]bool, len(covers))
+	unique := make([]*pb.Cover, 0, len(covers))
+	for _, cover := range covers {
+		key := coverKey(cover)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, cover)
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		return coverKey(unique[i]) < coverKey(unique[j])
+	})
+	return unique
+}
+
+// WithDeadLetterSink copies unprocessable triggers to the sink when the
+// handler fails, so operators can inspect and replay them; the error
+// still propagates.  ServerConfig.DeadLetterSink installs this.
+func (h *ProcessManagerHandler) WithDeadLetterSink(sink DeadLetterSink) *ProcessManagerHandler {
+	h.deadLetters = sink
+	return h
+}
+
+// WithStateStore gives the process manager its own persisted event
+// stream.  Events returned by the handler are appended to a stream keyed
+// by the trigger's correlation id (root ProcessRoot(correlation), domain
+// the PM's name) and are fed back as processState on the next trigger for
+// the same correlation, so a process can remember decisions across
+// triggers without the coordinator carrying the state.
+func (h *ProcessManagerHandler) WithStateStore(store PMStateStore) *ProcessManagerHandler {
+	h.states = store
+	return h
+}
+
+// Handle runs the registered reaction.  Trigger pages whose event types the
+// process manager does not listen to are filtered out first; when no
+// subscribed pages remain the handler is not invoked at all.  With a
+// state store configured, an empty processState is loaded from the store
+// and returned events are appended to it before being passed through.
+func (h *ProcessManagerHandler) Handle(trigger *pb.EventBook, destinations []*pb.EventBook, processState *pb.EventBook) ([]*pb.CommandBook, *pb.EventBook, error) {
+	if h.handle == nil {
+		return nil, nil, nil
+	}
+	filtered := h.filterTrigger(trigger)
+	if filtered == nil {
+		return nil, nil, nil
+	}
+	correlation := trigger.GetCover().GetCorrelationId()
+	if h.reaperMaxAge > 0 && correlation != "" {
+		h.touchProcess(correlation)
+	}
+	if h.states != nil && len(processState.GetPages()) == 0 && correlation != "" {
+		stored, err := h.states.Load(h.name, correlation)
+		if err != nil {
+			return nil, nil, err
+		}
+		processState = stored
+	}
+	commands, events, err := h.handle(filtered, destinations, processState)
+	if err != nil {
+		deadLetter(h.deadLetters, h.name, filtered, err)
+		return commands, events, err
+	}
+	if h.states != nil && len(events.GetPages()) > 0 && correlation != "" {
+		events = h.stampProcessEvents(correlation, processState, events)
+		if err := h.states.Append(h.name, correlation, events); err != nil {
+			return nil, nil, err
+		}
+	}
+	return commands, events, err
+}
+
+// stampProcessEvents addresses handler-returned events to the PM's own
+// stream — domain the PM's name, root derived from the correlation id —
+// and assigns contiguous sequences continuing from the prior state.
+func (h *ProcessManagerHandler) stampProcessEvents(correlation string, prior, events *pb.EventBook) *pb.EventBook {
+	next := NextSequence(prior)
+	pages := make([]*pb.EventPage, len(events.GetPages()))
+	for i, page := range events.GetPages() {
+		stamped := *page
+		stamped.Sequence = &pb.EventPage_Num{Num: next + uint32(i)}
+		pages[i] = &stamped
+	}
+	return &pb.EventBook{
+		Cover: &pb.Cover{
+			Domain:        h.name,
+			Root:          ProcessRoot(correlation),
+			CorrelationId: correlation,
+		},
+		Pages: pages,
+	}
+}
+
+// filterTrigger returns a copy of trigger containing only the pages whose
+// event types the process manager subscribes to, or nil when none match.
+func (h *ProcessManagerHandler) filterTrigger(trigger *pb.EventBook) *pb.EventBook {
+	domain := trigger.GetCover().GetDomain()
+	var pages []*pb.EventPage
+	for _, page := range trigger.GetPages() {
+		if h.ListensTo(domain, typeNameOf(page.GetEvent().GetTypeUrl())) {
+			pages = append(pages, page)
+		}
+	}
+	if len(pages) == 0 {
+		return nil
+	}
+	return &pb.EventBook{
+		Cover:    trigger.GetCover(),
+		Snapshot: trigger.GetSnapshot(),
+		Pages:    pages,
+	}
+}
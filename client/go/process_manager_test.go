@@ -0,0 +1,36 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestPMPrepareDeduplicatesAndSortsCovers(t *testing.T) {
+	pm := NewProcessManagerHandler("pm-dedup-test").
+		ListenTo("hand").
+		WithPrepare(func(trigger *pb.EventBook) []*pb.Cover {
+			// Two pages referencing the same hand plus one other, out
+			// of order.
+			return []*pb.Cover{
+				{Domain: "hand", Root: &pb.UUID{Value: []byte("handroot00000002")}},
+				{Domain: "hand", Root: &pb.UUID{Value: []byte("handroot00000001")}},
+				{Domain: "hand", Root: &pb.UUID{Value: []byte("handroot00000001")}},
+				{Domain: "account", Root: &pb.UUID{Value: []byte("handroot00000001")}},
+			}
+		})
+
+	covers := pm.Prepare(triggerBook(t, "hand", []byte("handroot00000001"), 0, wrapperspb.String("flop")))
+	if len(covers) != 3 {
+		t.Fatalf("got %d covers, want 3 after dedup", len(covers))
+	}
+	if covers[0].GetDomain() != "account" {
+		t.Errorf("covers not sorted by domain: first is %q", covers[0].GetDomain())
+	}
+	if string(covers[1].GetRoot().GetValue()) != "handroot00000001" ||
+		string(covers[2].GetRoot().GetValue()) != "handroot00000002" {
+		t.Errorf("hand covers not sorted by root: %v, %v", covers[1], covers[2])
+	}
+}
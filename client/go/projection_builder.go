@@ -0,0 +1,46 @@
+package angzarr
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// ProjectionBuilder assembles a projection envelope from the book that
+// produced it, so projectors stop repeating the cover copy and sequence
+// extraction:
+//
+//	return angzarr.NewProjection(events, "projector-receipt").
+//		WithPayload(&examples.Receipt{...})
+//
+// The cover is taken from the book and the sequence from its last page,
+// the position the projection reflects.
+type ProjectionBuilder struct {
+	projection *pb.Projection
+}
+
+// NewProjection starts a projection for the given event book, attributed
+// to the named projector.
+func NewProjection(events *pb.EventBook, projector string) *ProjectionBuilder {
+	return &ProjectionBuilder{
+		projection: &pb.Projection{
+			Cover:     events.GetCover(),
+			Projector: projector,
+			Sequence:  LastSequence(events),
+		},
+	}
+}
+
+// WithPayload packs msg as the projection body and returns the finished
+// projection.
+func (b *ProjectionBuilder) WithPayload(msg proto.Message) (*pb.Projection, error) {
+	packed, err := anypb.New(msg)
+	if err != nil {
+		return nil, fmt.Errorf("projection %q: %v", b.projection.GetProjector(), err)
+	}
+	b.projection.Projection = packed
+	return b.projection, nil
+}
@@ -0,0 +1,28 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewProjectionFillsEnvelopeFromBook(t *testing.T) {
+	book := testBook(t, 0, 1, 2)
+	projection, err := NewProjection(book, "projector-receipt").
+		WithPayload(wrapperspb.String("receipt"))
+	if err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if projection.GetCover() != book.GetCover() {
+		t.Error("cover not copied from the book")
+	}
+	if projection.GetProjector() != "projector-receipt" {
+		t.Errorf("projector = %q", projection.GetProjector())
+	}
+	if projection.GetSequence() != 2 {
+		t.Errorf("sequence = %d, want 2", projection.GetSequence())
+	}
+	if got := typeNameOf(projection.GetProjection().GetTypeUrl()); got != "StringValue" {
+		t.Errorf("payload type = %q, want StringValue", got)
+	}
+}
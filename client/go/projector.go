@@ -0,0 +1,215 @@
+package angzarr
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// ProjectorHandleFunc derives a single projection from an event book.
+// Returning a nil projection (with a nil error) means the book produced
+// nothing to project.  ctx is the inbound request context; projectors
+// doing slow work should watch it for cancellation.
+type ProjectorHandleFunc func(ctx context.Context, events *pb.EventBook) (*pb.Projection, error)
+
+// ProjectorHandleMultiFunc derives any number of projections from one event
+// book, for projectors that fan a book out into several views (e.g. a
+// receipt plus a per-line-item tax record).
+type ProjectorHandleMultiFunc func(ctx context.Context, events *pb.EventBook) ([]*pb.Projection, error)
+
+// ProjectorHandler receives event books from the coordinator and turns them
+// into projections.  Build one with NewProjectorHandler, naming the domains
+// it subscribes to, and register a handler with WithHandle or
+// WithHandleMulti.
+type ProjectorHandler struct {
+	name         string
+	domains      []string
+	only         map[string]bool
+	handleMulti  ProjectorHandleMultiFunc
+	deadLetters  DeadLetterSink
+	validateSeqs bool
+	metrics      *Metrics
+}
+
+// NewProjectorHandler creates a projector handler with the given name,
+// subscribed to events from the listed domains.
+func NewProjectorHandler(name string, domains ...string) *ProjectorHandler {
+	return &ProjectorHandler{name: name, domains: domains}
+}
+
+// Name returns the projector's registered name.
+func (h *ProjectorHandler) Name() string {
+	return h.name
+}
+
+// Domains returns the domains the projector subscribes to.
+func (h *ProjectorHandler) Domains() []string {
+	return h.domains
+}
+
+// Only registers an event-type allow-list.  Pages whose event types are not
+// listed are dropped before the handler runs, so high-volume projectors do
+// not pay deserialization costs for events they ignore, and books left with
+// no subscribed pages never reach the handler (and so never advance the
+// projection sequence).  The list is also advertised to the coordinator via
+// EventTypes.  Repeated calls merge; without any call every event type is
+// delivered.
+func (h *ProjectorHandler) Only(events ...string) *ProjectorHandler {
+	if h.only == nil {
+		h.only = make(map[string]bool)
+	}
+	for _, event := range events {
+		h.only[event] = true
+	}
+	return h
+}
+
+// EventTypes returns the advertised allow-list registered via Only, or nil
+// when the projector subscribes to every event type in its domains.
+func (h *ProjectorHandler) EventTypes() []string {
+	if h.only == nil {
+		return nil
+	}
+	types := make([]string, 0, len(h.only))
+	for event := range h.only {
+		types = append(types, event)
+	}
+	return types
+}
+
+// filterPages returns events restricted to the allow-list, or nil when no
+// subscribed pages remain.  Without an allow-list the book passes through.
+func (h *ProjectorHandler) filterPages(events *pb.EventBook) *pb.EventBook {
+	if h.only == nil {
+		return events
+	}
+	var pages []*pb.EventPage
+	for _, page := range events.GetPages() {
+		if h.only[typeNameOf(page.GetEvent().GetTypeUrl())] {
+			pages = append(pages, page)
+		}
+	}
+	if len(pages) == 0 {
+		return nil
+	}
+	return &pb.EventBook{
+		Cover:    events.GetCover(),
+		Snapshot: events.GetSnapshot(),
+		Pages:    pages,
+	}
+}
+
+// WithHandle registers a handler producing at most one projection per event
+// book.  It is sugar over WithHandleMulti: a nil projection yields an empty
+// result rather than a nil entry.
+func (h *ProjectorHandler) WithHandle(handle ProjectorHandleFunc) *ProjectorHandler {
+	return h.WithHandleMulti(func(ctx context.Context, events *pb.EventBook) ([]*pb.Projection, error) {
+		projection, err := handle(ctx, events)
+		if err != nil || projection == nil {
+			return nil, err
+		}
+		return []*pb.Projection{projection}, nil
+	})
+}
+
+// WithHandleMulti registers a handler that may produce several projections
+// per event book.  RunProjectorServer returns every projection to the
+// coordinator, so single-projection handlers keep working unchanged while
+// fan-out projectors emit as many as they need.
+func (h *ProjectorHandler) WithHandleMulti(handle ProjectorHandleMultiFunc) *ProjectorHandler {
+	h.handleMulti = handle
+	return h
+}
+
+// JSONEventFunc receives one subscribed event marshaled to JSON, along
+// with the book's cover, the page's sequence, and the event's short type
+// name.  Implementations typically append NDJSON lines to a sink.
+type JSONEventFunc func(cover *pb.Cover, seq uint32, typeName string, event []byte) error
+
+// JSONOption adjusts how WithJSONOutput marshals events.
+type JSONOption func(*protojson.MarshalOptions)
+
+// WithProtoFieldNames emits original proto field names (snake_case)
+// instead of protojson's default lowerCamelCase.
+func WithProtoFieldNames() JSONOption {
+	return func(o *protojson.MarshalOptions) { o.UseProtoNames = true }
+}
+
+// WithJSONOutput registers a handler that receives each event as JSON
+// bytes rather than a packed Any, so a generic log projector can write
+// NDJSON without a type switch per concrete proto.  Events are marshaled
+// with protojson (camelCase field names unless WithProtoFieldNames is
+// given); the Any's type URL is resolved through the global registry, so
+// only linked-in message types marshal.  A JSON projector produces no
+// projections — its output is the side effect.
+func (h *ProjectorHandler) WithJSONOutput(handle JSONEventFunc, opts ...JSONOption) *ProjectorHandler {
+	var marshal protojson.MarshalOptions
+	for _, opt := range opts {
+		opt(&marshal)
+	}
+	return h.WithHandleMulti(func(_ context.Context, events *pb.EventBook) ([]*pb.Projection, error) {
+		for _, page := range events.GetPages() {
+			event := page.GetEvent()
+			if event == nil {
+				continue
+			}
+			data, err := marshal.Marshal(event)
+			if err != nil {
+				return nil, err
+			}
+			if err := handle(events.GetCover(), PageSequence(page), typeNameOf(event.GetTypeUrl()), data); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+}
+
+// HandleSync runs the registered handler for one event book and returns the
+// resulting projections.  ctx is threaded through to the handler; callers
+// without a request context pass context.Background().
+func (h *ProjectorHandler) HandleSync(ctx context.Context, events *pb.EventBook) ([]*pb.Projection, error) {
+	if h.handleMulti == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "projector %q has no handler registered", h.name)
+	}
+	if h.validateSeqs {
+		// Validate before filtering: dropping unsubscribed pages would
+		// manufacture gaps that aren't delivery failures.
+		if err := ValidateSequences(events); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "projector %q: %v", h.name, err)
+		}
+	}
+	filtered := h.filterPages(events)
+	if filtered == nil {
+		return nil, nil
+	}
+	start := time.Now()
+	projections, err := h.handleMulti(ctx, filtered)
+	h.metrics.observeDispatch("projector", h.name, time.Since(start), err)
+	if err != nil {
+		deadLetter(h.deadLetters, h.name, filtered, err)
+	}
+	return projections, err
+}
+
+// WithSequenceValidation rejects books whose pages are gapped or out of
+// order (see ValidateSequences) with codes.InvalidArgument before the
+// handler runs, so a missing event fails the delivery loudly instead of
+// projecting partial state.  Off by default.
+func (h *ProjectorHandler) WithSequenceValidation() *ProjectorHandler {
+	h.validateSeqs = true
+	return h
+}
+
+// WithDeadLetterSink copies unprocessable books to the sink when the
+// handler fails, so operators can inspect and replay them; the error
+// still propagates.  ServerConfig.DeadLetterSink installs this.
+func (h *ProjectorHandler) WithDeadLetterSink(sink DeadLetterSink) *ProjectorHandler {
+	h.deadLetters = sink
+	return h
+}
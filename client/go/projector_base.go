@@ -0,0 +1,110 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// ProjectFunc maps one event page's payload to a projection.  Returning
+// a nil projection (and nil error) skips the page.
+type ProjectFunc func(event *anypb.Any) (*pb.Projection, error)
+
+// AllProjectFunc maps any subscribed event to a projection, given its
+// short type name so the handler can route formatting internally.
+// Returning nil skips the page.
+type AllProjectFunc func(typeName string, event *anypb.Any) *pb.Projection
+
+// ProjectorBase is an embeddable base for method-style projector types,
+// the projector counterpart of SagaBase.  It wraps a ProjectorHandler
+// and adds per-event-type registration:
+//
+//	type OutputProjector struct {
+//		angzarr.ProjectorBase
+//	}
+//
+//	p := OutputProjector{ProjectorBase: angzarr.NewProjectorBase("prj-output", "hand")}
+//	p.Projects("PotAwarded", p.projectPotAwarded)
+//
+// A projector whose event handling is uniform — a log writer formatting
+// every type the same way — registers one ProjectsAll handler instead of
+// one Projects call per type, so new event types can't be forgotten.
+type ProjectorBase struct {
+	*ProjectorHandler
+	registrations *projectorRegistrations
+}
+
+// projectorRegistrations holds the mutable registration tables the
+// handler closure dispatches through, so registrations made after
+// construction are seen.
+type projectorRegistrations struct {
+	perType map[string]ProjectFunc
+	all     AllProjectFunc
+}
+
+// NewProjectorBase creates a projector base with the given name,
+// subscribed to events from the listed domains.
+func NewProjectorBase(name string, domains ...string) ProjectorBase {
+	regs := &projectorRegistrations{perType: make(map[string]ProjectFunc)}
+	handler := NewProjectorHandler(name, domains...).
+		WithHandleMulti(func(_ context.Context, events *pb.EventBook) ([]*pb.Projection, error) {
+			var projections []*pb.Projection
+			for _, page := range events.GetPages() {
+				projection, err := regs.dispatch(page)
+				if err != nil {
+					return nil, err
+				}
+				if projection == nil {
+					continue
+				}
+				fillProjectionDefaults(projection, name, events, page)
+				projections = append(projections, projection)
+			}
+			return projections, nil
+		})
+	return ProjectorBase{ProjectorHandler: handler, registrations: regs}
+}
+
+// dispatch routes one page through its typed handler, falling back to
+// the catch-all; pages with neither are skipped.
+func (r *projectorRegistrations) dispatch(page *pb.EventPage) (*pb.Projection, error) {
+	typeName := typeNameOf(page.GetEvent().GetTypeUrl())
+	if project, ok := r.perType[typeName]; ok {
+		return project(page.GetEvent())
+	}
+	if r.all != nil {
+		return r.all(typeName, page.GetEvent()), nil
+	}
+	return nil, nil
+}
+
+// Projects registers a typed handler for the event with the given short
+// type name.  Typed registrations win over a ProjectsAll catch-all.
+func (b ProjectorBase) Projects(name string, project ProjectFunc) ProjectorBase {
+	b.registrations.perType[name] = project
+	return b
+}
+
+// ProjectsAll registers one handler invoked for every subscribed event
+// that has no typed registration.
+func (b ProjectorBase) ProjectsAll(project AllProjectFunc) ProjectorBase {
+	b.registrations.all = project
+	return b
+}
+
+// fillProjectionDefaults populates the envelope attributes a handler
+// left unset: the source cover, the projector's name, and the page's
+// sequence.
+func fillProjectionDefaults(projection *pb.Projection, name string, events *pb.EventBook, page *pb.EventPage) {
+	if projection.Cover == nil {
+		projection.Cover = events.GetCover()
+	}
+	if projection.Projector == "" {
+		projection.Projector = name
+	}
+	if projection.Sequence == 0 {
+		projection.Sequence = PageSequence(page)
+	}
+}
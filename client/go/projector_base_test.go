@@ -0,0 +1,69 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestProjectorBaseProjectsAllCatchesEveryType(t *testing.T) {
+	var seen []string
+	p := NewProjectorBase("prj-output", "player").
+		ProjectsAll(func(typeName string, event *anypb.Any) *pb.Projection {
+			seen = append(seen, typeName)
+			return &pb.Projection{Projection: event}
+		})
+
+	projections, err := p.HandleSync(context.Background(), testBook(t, 0, 1))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(projections) != 2 {
+		t.Fatalf("got %d projections, want 2", len(projections))
+	}
+	if len(seen) != 2 || seen[0] != "StringValue" {
+		t.Errorf("catch-all saw %v", seen)
+	}
+	if projections[0].GetProjector() != "prj-output" {
+		t.Errorf("projector = %q, want prj-output", projections[0].GetProjector())
+	}
+	if projections[1].GetSequence() != 1 {
+		t.Errorf("sequence = %d, want 1", projections[1].GetSequence())
+	}
+}
+
+func TestProjectorBaseTypedRegistrationWinsOverCatchAll(t *testing.T) {
+	var typed, all int
+	p := NewProjectorBase("prj-output", "player").
+		ProjectsAll(func(string, *anypb.Any) *pb.Projection {
+			all++
+			return nil
+		}).
+		Projects("StringValue", func(event *anypb.Any) (*pb.Projection, error) {
+			typed++
+			return &pb.Projection{Projection: event}, nil
+		})
+
+	if _, err := p.HandleSync(context.Background(), testBook(t, 0)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if typed != 1 || all != 0 {
+		t.Errorf("typed = %d, all = %d; want 1 and 0", typed, all)
+	}
+}
+
+func TestProjectorBaseNilReturnSkipsPage(t *testing.T) {
+	p := NewProjectorBase("prj-output", "player").
+		ProjectsAll(func(string, *anypb.Any) *pb.Projection { return nil })
+
+	projections, err := p.HandleSync(context.Background(), testBook(t, 0))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(projections) != 0 {
+		t.Errorf("nil return produced %d projections", len(projections))
+	}
+}
@@ -0,0 +1,75 @@
+package angzarr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestProjectorWithJSONOutput(t *testing.T) {
+	type line struct {
+		seq      uint32
+		typeName string
+		payload  map[string]any
+	}
+	var lines []line
+	handler := NewProjectorHandler("prj-json", "player").
+		WithJSONOutput(func(cover *pb.Cover, seq uint32, typeName string, event []byte) error {
+			var payload map[string]any
+			if err := json.Unmarshal(event, &payload); err != nil {
+				return err
+			}
+			lines = append(lines, line{seq: seq, typeName: typeName, payload: payload})
+			return nil
+		})
+
+	projections, err := handler.HandleSync(context.Background(), testBook(t, 0, 1))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if projections != nil {
+		t.Errorf("JSON projector emitted %d projections, want none", len(projections))
+	}
+	if len(lines) != 2 {
+		t.Fatalf("got %d JSON lines, want 2", len(lines))
+	}
+	if lines[0].typeName != "StringValue" || lines[1].seq != 1 {
+		t.Errorf("lines = %+v", lines)
+	}
+	if _, ok := lines[0].payload["@type"]; !ok {
+		t.Error("marshaled event missing @type discriminator")
+	}
+}
+
+func TestProjectorJSONOutputStopsOnHandlerError(t *testing.T) {
+	calls := 0
+	handler := NewProjectorHandler("prj-json", "player").
+		WithJSONOutput(func(*pb.Cover, uint32, string, []byte) error {
+			calls++
+			return FailedPrecondition("sink closed")
+		})
+
+	if _, err := handler.HandleSync(context.Background(), testBook(t, 0, 1)); err == nil {
+		t.Fatal("expected handler error to surface")
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times after error, want 1", calls)
+	}
+}
+
+func TestProjectorSequenceValidationRejectsGappedBook(t *testing.T) {
+	handler := NewProjectorHandler("prj-receipt", "transaction").
+		WithSequenceValidation().
+		WithHandle(func(ctx context.Context, events *pb.EventBook) (*pb.Projection, error) {
+			return &pb.Projection{Cover: events.GetCover()}, nil
+		})
+
+	if _, err := handler.HandleSync(context.Background(), testBook(t, 0, 1)); err != nil {
+		t.Fatalf("contiguous book rejected: %v", err)
+	}
+	if _, err := handler.HandleSync(context.Background(), testBook(t, 0, 2)); err == nil {
+		t.Fatal("gapped book was projected")
+	}
+}
@@ -0,0 +1,826 @@
+// Package angzarr contains the message types exchanged between the angzarr
+// coordinator and business-logic services: covers, event books, command
+// books, projections, and notifications.  The definitions here mirror
+// proto/angzarr.proto; keep the two in sync when the schema changes.
+package angzarr
+
+import (
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UUID is a 16-byte aggregate root identifier.
+type UUID struct {
+	Value []byte
+}
+
+// GetValue returns the raw identifier bytes, or nil for a nil UUID.
+func (u *UUID) GetValue() []byte {
+	if u == nil {
+		return nil
+	}
+	return u.Value
+}
+
+// Cover addresses a single aggregate root: the domain it lives in and the
+// root identifier within that domain.  CorrelationId ties together all
+// traffic belonging to one logical flow across services.  Metadata
+// carries request-scoped key/value context — tenant id, actor, and the
+// like — that the framework propagates from commands to the events they
+// emit and on to saga-triggered commands.
+type Cover struct {
+	Domain        string
+	Root          *UUID
+	CorrelationId string
+	Metadata      map[string]string
+}
+
+// GetDomain returns the cover's domain, or "" for a nil cover.
+func (c *Cover) GetDomain() string {
+	if c == nil {
+		return ""
+	}
+	return c.Domain
+}
+
+// GetRoot returns the cover's root identifier, or nil for a nil cover.
+func (c *Cover) GetRoot() *UUID {
+	if c == nil {
+		return nil
+	}
+	return c.Root
+}
+
+// GetCorrelationId returns the correlation identifier, or "" for a nil cover.
+func (c *Cover) GetCorrelationId() string {
+	if c == nil {
+		return ""
+	}
+	return c.CorrelationId
+}
+
+// GetMetadata returns the cover's metadata map, or nil for a nil cover.
+func (c *Cover) GetMetadata() map[string]string {
+	if c == nil {
+		return nil
+	}
+	return c.Metadata
+}
+
+// isEventPage_Sequence is the interface satisfied by the EventPage sequence
+// oneof wrappers.
+type isEventPage_Sequence interface {
+	isEventPage_Sequence()
+}
+
+// EventPage_Num carries an explicit, caller-assigned sequence number.
+type EventPage_Num struct {
+	Num uint32
+}
+
+// EventPage_Force asks the coordinator to assign the next free sequence.
+type EventPage_Force struct {
+	Force bool
+}
+
+func (*EventPage_Num) isEventPage_Sequence()   {}
+func (*EventPage_Force) isEventPage_Sequence() {}
+
+// EventPage is a single event in an aggregate's stream.  Event carries the
+// packed domain event; a page with a nil Event is only valid when Marker is
+// set, indicating a structural placeholder rather than a lost payload.
+// SchemaVersion records the producer's event schema version (0 = unstamped)
+// so consumers can detect and upcast older shapes.
+type EventPage struct {
+	Sequence      isEventPage_Sequence
+	CreatedAt     *timestamppb.Timestamp
+	Event         *anypb.Any
+	Synchronous   bool
+	Marker        bool
+	SchemaVersion int32
+}
+
+// GetNum returns the explicit sequence number, or 0 when the sequence is
+// unset or force-assigned.
+func (p *EventPage) GetNum() uint32 {
+	if p == nil {
+		return 0
+	}
+	if n, ok := p.Sequence.(*EventPage_Num); ok {
+		return n.Num
+	}
+	return 0
+}
+
+// GetForce reports whether the page requests coordinator-assigned sequencing.
+func (p *EventPage) GetForce() bool {
+	if p == nil {
+		return false
+	}
+	if f, ok := p.Sequence.(*EventPage_Force); ok {
+		return f.Force
+	}
+	return false
+}
+
+// GetCreatedAt returns the page timestamp, or nil for a nil page.
+func (p *EventPage) GetCreatedAt() *timestamppb.Timestamp {
+	if p == nil {
+		return nil
+	}
+	return p.CreatedAt
+}
+
+// GetEvent returns the packed event payload, or nil for a nil page.
+func (p *EventPage) GetEvent() *anypb.Any {
+	if p == nil {
+		return nil
+	}
+	return p.Event
+}
+
+// GetSynchronous reports whether downstream processing should be awaited.
+func (p *EventPage) GetSynchronous() bool {
+	if p == nil {
+		return false
+	}
+	return p.Synchronous
+}
+
+// GetSchemaVersion returns the producer's schema version, or 0 when the
+// page is unstamped or nil.
+func (p *EventPage) GetSchemaVersion() int32 {
+	if p == nil {
+		return 0
+	}
+	return p.SchemaVersion
+}
+
+// GetMarker reports whether the page is a structural marker with no payload.
+func (p *EventPage) GetMarker() bool {
+	if p == nil {
+		return false
+	}
+	return p.Marker
+}
+
+// Snapshot is a point-in-time materialization of aggregate state.  Sequence
+// is the sequence of the last event folded into State.
+type Snapshot struct {
+	Sequence uint32
+	State    *anypb.Any
+}
+
+// GetSequence returns the snapshot sequence, or 0 for a nil snapshot.
+func (s *Snapshot) GetSequence() uint32 {
+	if s == nil {
+		return 0
+	}
+	return s.Sequence
+}
+
+// GetState returns the packed snapshot state, or nil for a nil snapshot.
+func (s *Snapshot) GetState() *anypb.Any {
+	if s == nil {
+		return nil
+	}
+	return s.State
+}
+
+// EventBook is the unit of event transfer: a cover identifying the root, an
+// optional snapshot, and the pages at sequences after the snapshot.
+type EventBook struct {
+	Cover    *Cover
+	Snapshot *Snapshot
+	Pages    []*EventPage
+}
+
+// GetCover returns the book's cover, or nil for a nil book.
+func (b *EventBook) GetCover() *Cover {
+	if b == nil {
+		return nil
+	}
+	return b.Cover
+}
+
+// GetSnapshot returns the book's snapshot, or nil for a nil book.
+func (b *EventBook) GetSnapshot() *Snapshot {
+	if b == nil {
+		return nil
+	}
+	return b.Snapshot
+}
+
+// GetPages returns the book's pages, or nil for a nil book.
+func (b *EventBook) GetPages() []*EventPage {
+	if b == nil {
+		return nil
+	}
+	return b.Pages
+}
+
+// isCommandPage_Payload is the interface satisfied by the CommandPage
+// payload oneof wrappers.
+type isCommandPage_Payload interface {
+	isCommandPage_Payload()
+}
+
+// CommandPage_Command carries a packed command message.
+type CommandPage_Command struct {
+	Command *anypb.Any
+}
+
+func (*CommandPage_Command) isCommandPage_Payload() {}
+
+// CommandPage is a single command addressed at the sequence the issuer
+// believes the aggregate is at; the coordinator rejects stale sequences.
+type CommandPage struct {
+	Sequence    uint32
+	Synchronous bool
+	Payload     isCommandPage_Payload
+}
+
+// GetSequence returns the expected aggregate sequence, or 0 for a nil page.
+func (p *CommandPage) GetSequence() uint32 {
+	if p == nil {
+		return 0
+	}
+	return p.Sequence
+}
+
+// GetSynchronous reports whether the issuer awaits downstream processing.
+func (p *CommandPage) GetSynchronous() bool {
+	if p == nil {
+		return false
+	}
+	return p.Synchronous
+}
+
+// GetCommand returns the packed command payload, or nil when unset.
+func (p *CommandPage) GetCommand() *anypb.Any {
+	if p == nil {
+		return nil
+	}
+	if c, ok := p.Payload.(*CommandPage_Command); ok {
+		return c.Command
+	}
+	return nil
+}
+
+// CommandBook is the unit of command transfer: a cover identifying the
+// target root and the command pages to apply.
+type CommandBook struct {
+	Cover *Cover
+	Pages []*CommandPage
+	// Metadata carries transport-level key/value pairs alongside the
+	// command, such as W3C trace context injected by a traced saga.
+	Metadata map[string]string
+}
+
+// GetMetadata returns the book's metadata, or nil for a nil book.
+func (b *CommandBook) GetMetadata() map[string]string {
+	if b == nil {
+		return nil
+	}
+	return b.Metadata
+}
+
+// GetCover returns the book's cover, or nil for a nil book.
+func (b *CommandBook) GetCover() *Cover {
+	if b == nil {
+		return nil
+	}
+	return b.Cover
+}
+
+// GetPages returns the book's pages, or nil for a nil book.
+func (b *CommandBook) GetPages() []*CommandPage {
+	if b == nil {
+		return nil
+	}
+	return b.Pages
+}
+
+// ContextualCommand pairs an inbound command with the prior events of the
+// aggregate it targets, so business logic can rebuild state before handling.
+type ContextualCommand struct {
+	Command *CommandBook
+	Events  *EventBook
+}
+
+// GetCommand returns the command book, or nil for a nil command.
+func (c *ContextualCommand) GetCommand() *CommandBook {
+	if c == nil {
+		return nil
+	}
+	return c.Command
+}
+
+// GetEvents returns the prior event book, or nil for a nil command.
+func (c *ContextualCommand) GetEvents() *EventBook {
+	if c == nil {
+		return nil
+	}
+	return c.Events
+}
+
+// HandleChunk is one message of the client-streaming Handle variant.  The
+// first chunk carries the command book (and the stream's snapshot, when
+// one exists); subsequent chunks carry batches of prior event pages, so a
+// long history never travels as one message.
+type HandleChunk struct {
+	Command  *CommandBook
+	Snapshot *Snapshot
+	Pages    []*EventPage
+}
+
+// GetCommand returns the command book, or nil for a nil chunk.
+func (c *HandleChunk) GetCommand() *CommandBook {
+	if c == nil {
+		return nil
+	}
+	return c.Command
+}
+
+// GetSnapshot returns the stream snapshot, or nil for a nil chunk.
+func (c *HandleChunk) GetSnapshot() *Snapshot {
+	if c == nil {
+		return nil
+	}
+	return c.Snapshot
+}
+
+// GetPages returns the chunk's event pages, or nil for a nil chunk.
+func (c *HandleChunk) GetPages() []*EventPage {
+	if c == nil {
+		return nil
+	}
+	return c.Pages
+}
+
+// BusinessResponse is the aggregate's answer to a command: the events to
+// append, or a revocation when the command must be compensated upstream.
+type BusinessResponse struct {
+	Events     *EventBook
+	Revocation *CommandBook
+}
+
+// GetEvents returns the emitted events, or nil for a nil response.
+func (r *BusinessResponse) GetEvents() *EventBook {
+	if r == nil {
+		return nil
+	}
+	return r.Events
+}
+
+// GetRevocation returns the revocation commands, or nil for a nil response.
+func (r *BusinessResponse) GetRevocation() *CommandBook {
+	if r == nil {
+		return nil
+	}
+	return r.Revocation
+}
+
+// Projection is a projector's derived view of an aggregate at a sequence.
+type Projection struct {
+	Cover      *Cover
+	Projector  string
+	Sequence   uint32
+	Projection *anypb.Any
+}
+
+// GetCover returns the projection's cover, or nil for a nil projection.
+func (p *Projection) GetCover() *Cover {
+	if p == nil {
+		return nil
+	}
+	return p.Cover
+}
+
+// GetProjector returns the emitting projector's name, or "" when nil.
+func (p *Projection) GetProjector() string {
+	if p == nil {
+		return ""
+	}
+	return p.Projector
+}
+
+// GetSequence returns the sequence the projection reflects, or 0 when nil.
+func (p *Projection) GetSequence() uint32 {
+	if p == nil {
+		return 0
+	}
+	return p.Sequence
+}
+
+// GetProjection returns the packed projection payload, or nil when nil.
+func (p *Projection) GetProjection() *anypb.Any {
+	if p == nil {
+		return nil
+	}
+	return p.Projection
+}
+
+// Projections carries the repeated projections a projector derives from
+// one event book.
+type Projections struct {
+	Projections []*Projection
+}
+
+// GetProjections returns the contained projections, or nil when nil.
+func (p *Projections) GetProjections() []*Projection {
+	if p == nil {
+		return nil
+	}
+	return p.Projections
+}
+
+// Notification reports the rejection of a previously issued command back to
+// its issuer so compensating action can be taken.
+type Notification struct {
+	Cover   *Cover
+	Domain  string
+	Command string
+	Reason  string
+}
+
+// GetCover returns the cover of the rejected command, or nil when nil.
+func (n *Notification) GetCover() *Cover {
+	if n == nil {
+		return nil
+	}
+	return n.Cover
+}
+
+// GetDomain returns the domain the rejected command targeted, or "" when nil.
+func (n *Notification) GetDomain() string {
+	if n == nil {
+		return ""
+	}
+	return n.Domain
+}
+
+// GetCommand returns the rejected command's type name, or "" when nil.
+func (n *Notification) GetCommand() string {
+	if n == nil {
+		return ""
+	}
+	return n.Command
+}
+
+// GetReason returns the human-readable rejection reason, or "" when nil.
+func (n *Notification) GetReason() string {
+	if n == nil {
+		return ""
+	}
+	return n.Reason
+}
+
+// CloudEvent mirrors the CloudEvents 1.0 attributes for events exported to
+// external consumers over HTTP or Kafka.
+type CloudEvent struct {
+	Id          string
+	Source      string
+	SpecVersion string
+	Type        string
+	Time        *timestamppb.Timestamp
+	Data        *anypb.Any
+	Extensions  map[string]string
+}
+
+// GetId returns the event identifier, or "" for a nil event.
+func (e *CloudEvent) GetId() string {
+	if e == nil {
+		return ""
+	}
+	return e.Id
+}
+
+// GetSource returns the event source, or "" for a nil event.
+func (e *CloudEvent) GetSource() string {
+	if e == nil {
+		return ""
+	}
+	return e.Source
+}
+
+// GetSpecVersion returns the CloudEvents spec version, or "" for nil.
+func (e *CloudEvent) GetSpecVersion() string {
+	if e == nil {
+		return ""
+	}
+	return e.SpecVersion
+}
+
+// GetType returns the event type, or "" for a nil event.
+func (e *CloudEvent) GetType() string {
+	if e == nil {
+		return ""
+	}
+	return e.Type
+}
+
+// GetTime returns the event timestamp, or nil for a nil event.
+func (e *CloudEvent) GetTime() *timestamppb.Timestamp {
+	if e == nil {
+		return nil
+	}
+	return e.Time
+}
+
+// GetData returns the packed event payload, or nil for a nil event.
+func (e *CloudEvent) GetData() *anypb.Any {
+	if e == nil {
+		return nil
+	}
+	return e.Data
+}
+
+// GetExtensions returns the extension attributes, or nil for a nil event.
+func (e *CloudEvent) GetExtensions() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Extensions
+}
+
+// StateQueryRequest asks a state-router query server for its current
+// projection state.
+type StateQueryRequest struct{}
+
+// StateQueryResponse carries a state router's current projection state,
+// JSON-encoded for consumption by tooling regardless of the state's Go
+// shape.
+type StateQueryResponse struct {
+	Json []byte
+}
+
+// GetJson returns the encoded state, or nil for a nil response.
+func (r *StateQueryResponse) GetJson() []byte {
+	if r == nil {
+		return nil
+	}
+	return r.Json
+}
+
+// Query addresses an aggregate's event stream for a read.  LowerBound,
+// when non-zero, restricts the result to pages at or above that
+// sequence.
+type Query struct {
+	Domain     string
+	Root       *UUID
+	LowerBound uint32
+}
+
+// GetDomain returns the queried domain, or "" for a nil query.
+func (q *Query) GetDomain() string {
+	if q == nil {
+		return ""
+	}
+	return q.Domain
+}
+
+// GetRoot returns the queried root, or nil for a nil query.
+func (q *Query) GetRoot() *UUID {
+	if q == nil {
+		return nil
+	}
+	return q.Root
+}
+
+// GetLowerBound returns the lower sequence bound, or 0 for a nil query.
+func (q *Query) GetLowerBound() uint32 {
+	if q == nil {
+		return 0
+	}
+	return q.LowerBound
+}
+
+// SagaResponse carries the command books a saga emits in reaction to an
+// event book.
+type SagaResponse struct {
+	Commands []*CommandBook
+}
+
+// Preparation names the destination covers whose state a saga or process
+// manager needs fetched before handling a trigger.
+type Preparation struct {
+	Covers []*Cover
+}
+
+// GetCovers returns the requested covers, or nil for a nil preparation.
+func (p *Preparation) GetCovers() []*Cover {
+	if p == nil {
+		return nil
+	}
+	return p.Covers
+}
+
+// SagaRetryRequest identifies the specific previously-emitted commands
+// that were rejected downstream — one notification per rejected command,
+// matched by cover and sequence — together with refreshed destination
+// state, so a saga can retry only the failures.
+type SagaRetryRequest struct {
+	Rejected     []*Notification
+	Destinations []*EventBook
+}
+
+// GetRejected returns the rejection notifications, or nil when nil.
+func (r *SagaRetryRequest) GetRejected() []*Notification {
+	if r == nil {
+		return nil
+	}
+	return r.Rejected
+}
+
+// GetDestinations returns the refreshed destination books, or nil when nil.
+func (r *SagaRetryRequest) GetDestinations() []*EventBook {
+	if r == nil {
+		return nil
+	}
+	return r.Destinations
+}
+
+// SagaRejection notifies a saga that a command it emitted was rejected
+// downstream, carrying the rejection, the source book that triggered the
+// command, and destination state for sequencing compensations.
+type SagaRejection struct {
+	Notification *Notification
+	Source       *EventBook
+	Destinations []*EventBook
+}
+
+// GetNotification returns the rejection notification, or nil when nil.
+func (r *SagaRejection) GetNotification() *Notification {
+	if r == nil {
+		return nil
+	}
+	return r.Notification
+}
+
+// GetSource returns the originating source book, or nil when nil.
+func (r *SagaRejection) GetSource() *EventBook {
+	if r == nil {
+		return nil
+	}
+	return r.Source
+}
+
+// GetDestinations returns the destination books, or nil when nil.
+func (r *SagaRejection) GetDestinations() []*EventBook {
+	if r == nil {
+		return nil
+	}
+	return r.Destinations
+}
+
+// SagaTrigger pairs a source event book with the destination state fetched
+// during the prepare phase.
+type SagaTrigger struct {
+	Source       *EventBook
+	Destinations []*EventBook
+}
+
+// GetSource returns the triggering book, or nil for a nil trigger.
+func (t *SagaTrigger) GetSource() *EventBook {
+	if t == nil {
+		return nil
+	}
+	return t.Source
+}
+
+// GetDestinations returns the fetched destination books, or nil when nil.
+func (t *SagaTrigger) GetDestinations() []*EventBook {
+	if t == nil {
+		return nil
+	}
+	return t.Destinations
+}
+
+// ProcessManagerTrigger pairs a trigger event book with fetched destination
+// state and the process manager's own state stream.
+type ProcessManagerTrigger struct {
+	Trigger      *EventBook
+	Destinations []*EventBook
+	ProcessState *EventBook
+}
+
+// GetTrigger returns the triggering book, or nil for a nil trigger.
+func (t *ProcessManagerTrigger) GetTrigger() *EventBook {
+	if t == nil {
+		return nil
+	}
+	return t.Trigger
+}
+
+// GetDestinations returns the fetched destination books, or nil when nil.
+func (t *ProcessManagerTrigger) GetDestinations() []*EventBook {
+	if t == nil {
+		return nil
+	}
+	return t.Destinations
+}
+
+// GetProcessState returns the PM's own state stream, or nil when nil.
+func (t *ProcessManagerTrigger) GetProcessState() *EventBook {
+	if t == nil {
+		return nil
+	}
+	return t.ProcessState
+}
+
+// ProcessManagerResponse carries the commands a process manager emits and
+// any events to append to its own state stream.
+type ProcessManagerResponse struct {
+	Commands []*CommandBook
+	Events   *EventBook
+}
+
+// GetCommands returns the emitted commands, or nil for a nil response.
+func (r *ProcessManagerResponse) GetCommands() []*CommandBook {
+	if r == nil {
+		return nil
+	}
+	return r.Commands
+}
+
+// GetEvents returns the state events to append, or nil for a nil response.
+func (r *ProcessManagerResponse) GetEvents() *EventBook {
+	if r == nil {
+		return nil
+	}
+	return r.Events
+}
+
+// GetCommands returns the emitted command books, or nil for a nil response.
+func (r *SagaResponse) GetCommands() []*CommandBook {
+	if r == nil {
+		return nil
+	}
+	return r.Commands
+}
+
+// DescribeRequest asks a running service to report its routing table.
+type DescribeRequest struct{}
+
+// ServiceDescription reports what a running service handles: its name
+// and domain, the command or event types it routes, the rejection pairs
+// it compensates, and (for sagas and process managers) the domains it
+// emits commands against.  Topology tooling reads these to validate that
+// a deployment wires up consistently.
+type ServiceDescription struct {
+	Name       string
+	Domain     string
+	Commands   []string
+	Events     []string
+	Rejections []string
+	Outputs    []string
+}
+
+// GetName returns the service name, or "" for a nil description.
+func (d *ServiceDescription) GetName() string {
+	if d == nil {
+		return ""
+	}
+	return d.Name
+}
+
+// GetDomain returns the served domain, or "" for a nil description.
+func (d *ServiceDescription) GetDomain() string {
+	if d == nil {
+		return ""
+	}
+	return d.Domain
+}
+
+// GetCommands returns the handled command types, or nil when nil.
+func (d *ServiceDescription) GetCommands() []string {
+	if d == nil {
+		return nil
+	}
+	return d.Commands
+}
+
+// GetEvents returns the subscribed event types, or nil when nil.
+func (d *ServiceDescription) GetEvents() []string {
+	if d == nil {
+		return nil
+	}
+	return d.Events
+}
+
+// GetRejections returns the handled rejection pairs, or nil when nil.
+func (d *ServiceDescription) GetRejections() []string {
+	if d == nil {
+		return nil
+	}
+	return d.Rejections
+}
+
+// GetOutputs returns the command target domains, or nil when nil.
+func (d *ServiceDescription) GetOutputs() []string {
+	if d == nil {
+		return nil
+	}
+	return d.Outputs
+}
@@ -0,0 +1,693 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// BusinessLogicClient is the client API for the BusinessLogic service.
+type BusinessLogicClient interface {
+	// Handle processes a contextual command and returns the events to
+	// append to the aggregate's stream.
+	Handle(ctx context.Context, in *ContextualCommand, opts ...grpc.CallOption) (*EventBook, error)
+	// HandleSync processes a contextual command and returns the full
+	// business response, including any revocation.
+	HandleSync(ctx context.Context, in *ContextualCommand, opts ...grpc.CallOption) (*BusinessResponse, error)
+	// Speculate processes a contextual command without persisting the
+	// result, returning the events the command would emit.
+	Speculate(ctx context.Context, in *ContextualCommand, opts ...grpc.CallOption) (*EventBook, error)
+	// HandleStream processes a command whose prior events arrive as a
+	// stream of chunks, for aggregates whose history is too large to
+	// carry in one ContextualCommand.
+	HandleStream(ctx context.Context, opts ...grpc.CallOption) (BusinessLogic_HandleStreamClient, error)
+}
+
+type businessLogicClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewBusinessLogicClient creates a BusinessLogic client on cc.
+func NewBusinessLogicClient(cc grpc.ClientConnInterface) BusinessLogicClient {
+	return &businessLogicClient{cc}
+}
+
+func (c *businessLogicClient) Handle(ctx context.Context, in *ContextualCommand, opts ...grpc.CallOption) (*EventBook, error) {
+	out := new(EventBook)
+	err := c.cc.Invoke(ctx, "/angzarr.BusinessLogic/Handle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessLogicClient) HandleSync(ctx context.Context, in *ContextualCommand, opts ...grpc.CallOption) (*BusinessResponse, error) {
+	out := new(BusinessResponse)
+	err := c.cc.Invoke(ctx, "/angzarr.BusinessLogic/HandleSync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessLogicClient) Speculate(ctx context.Context, in *ContextualCommand, opts ...grpc.CallOption) (*EventBook, error) {
+	out := new(EventBook)
+	err := c.cc.Invoke(ctx, "/angzarr.BusinessLogic/Speculate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessLogicClient) HandleStream(ctx context.Context, opts ...grpc.CallOption) (BusinessLogic_HandleStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BusinessLogic_ServiceDesc.Streams[0], "/angzarr.BusinessLogic/HandleStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &businessLogicHandleStreamClient{stream}, nil
+}
+
+// BusinessLogic_HandleStreamClient is the client side of the HandleStream
+// stream: send chunks, then CloseAndRecv the emitted events.
+type BusinessLogic_HandleStreamClient interface {
+	Send(*HandleChunk) error
+	CloseAndRecv() (*EventBook, error)
+	grpc.ClientStream
+}
+
+type businessLogicHandleStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *businessLogicHandleStreamClient) Send(m *HandleChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *businessLogicHandleStreamClient) CloseAndRecv() (*EventBook, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(EventBook)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BusinessLogicServer is the server API for the BusinessLogic service: the
+// contract the coordinator calls to hand commands to an aggregate.
+type BusinessLogicServer interface {
+	// Handle processes a contextual command and returns the events to
+	// append to the aggregate's stream.
+	Handle(ctx context.Context, cc *ContextualCommand) (*EventBook, error)
+	// HandleSync processes a contextual command and returns the full
+	// business response, including any revocation.
+	HandleSync(ctx context.Context, cc *ContextualCommand) (*BusinessResponse, error)
+	// Speculate processes a contextual command without persisting the
+	// result.
+	Speculate(ctx context.Context, cc *ContextualCommand) (*EventBook, error)
+	// HandleStream processes a command whose prior events arrive as a
+	// stream of chunks.
+	HandleStream(stream BusinessLogic_HandleStreamServer) error
+}
+
+// RegisterBusinessLogicServer registers srv on s.
+func RegisterBusinessLogicServer(s grpc.ServiceRegistrar, srv BusinessLogicServer) {
+	s.RegisterService(&BusinessLogic_ServiceDesc, srv)
+}
+
+func _BusinessLogic_Handle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContextualCommand)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessLogicServer).Handle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.BusinessLogic/Handle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessLogicServer).Handle(ctx, req.(*ContextualCommand))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessLogic_HandleSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContextualCommand)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessLogicServer).HandleSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.BusinessLogic/HandleSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessLogicServer).HandleSync(ctx, req.(*ContextualCommand))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessLogic_Speculate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContextualCommand)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessLogicServer).Speculate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.BusinessLogic/Speculate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessLogicServer).Speculate(ctx, req.(*ContextualCommand))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessLogic_HandleStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BusinessLogicServer).HandleStream(&businessLogicHandleStreamServer{stream})
+}
+
+// BusinessLogic_HandleStreamServer is the server side of the HandleStream
+// stream: receive chunks until io.EOF, then SendAndClose the emitted
+// events.
+type BusinessLogic_HandleStreamServer interface {
+	SendAndClose(*EventBook) error
+	Recv() (*HandleChunk, error)
+	grpc.ServerStream
+}
+
+type businessLogicHandleStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *businessLogicHandleStreamServer) SendAndClose(m *EventBook) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *businessLogicHandleStreamServer) Recv() (*HandleChunk, error) {
+	m := new(HandleChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ProjectorClient is the client API for the Projector service.
+type ProjectorClient interface {
+	// HandleSync projects an event book and returns the derived
+	// projections.
+	HandleSync(ctx context.Context, in *EventBook, opts ...grpc.CallOption) (*Projections, error)
+}
+
+type projectorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewProjectorClient creates a Projector client on cc.
+func NewProjectorClient(cc grpc.ClientConnInterface) ProjectorClient {
+	return &projectorClient{cc}
+}
+
+func (c *projectorClient) HandleSync(ctx context.Context, in *EventBook, opts ...grpc.CallOption) (*Projections, error) {
+	out := new(Projections)
+	err := c.cc.Invoke(ctx, "/angzarr.Projector/HandleSync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProjectorServer is the server API for the Projector service: the
+// contract the coordinator calls to deliver event books to a projector.
+type ProjectorServer interface {
+	// HandleSync projects an event book and returns the derived
+	// projections.
+	HandleSync(ctx context.Context, events *EventBook) (*Projections, error)
+}
+
+// RegisterProjectorServer registers srv on s.
+func RegisterProjectorServer(s grpc.ServiceRegistrar, srv ProjectorServer) {
+	s.RegisterService(&Projector_ServiceDesc, srv)
+}
+
+func _Projector_HandleSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventBook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectorServer).HandleSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.Projector/HandleSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectorServer).HandleSync(ctx, req.(*EventBook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Projector_ServiceDesc is the grpc.ServiceDesc for the Projector service.
+var Projector_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "angzarr.Projector",
+	HandlerType: (*ProjectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "HandleSync", Handler: _Projector_HandleSync_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "angzarr.proto",
+}
+
+// StateQueryServer is the server API for the StateQuery service: a read
+// method exposing a state router's current projection state.
+type StateQueryServer interface {
+	// Get returns the current projection state.
+	Get(ctx context.Context, req *StateQueryRequest) (*StateQueryResponse, error)
+}
+
+// RegisterStateQueryServer registers srv on s.
+func RegisterStateQueryServer(s grpc.ServiceRegistrar, srv StateQueryServer) {
+	s.RegisterService(&StateQuery_ServiceDesc, srv)
+}
+
+func _StateQuery_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateQueryServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.StateQuery/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateQueryServer).Get(ctx, req.(*StateQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StateQuery_ServiceDesc is the grpc.ServiceDesc for the StateQuery
+// service.
+var StateQuery_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "angzarr.StateQuery",
+	HandlerType: (*StateQueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Get", Handler: _StateQuery_Get_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "angzarr.proto",
+}
+
+// EventQueryClient is the client API for the EventQuery service.
+type EventQueryClient interface {
+	// GetEvents returns the event book matching the query, starting from
+	// the latest snapshot when one covers the requested range.
+	GetEvents(ctx context.Context, in *Query, opts ...grpc.CallOption) (*EventBook, error)
+}
+
+type eventQueryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewEventQueryClient creates an EventQuery client on cc.
+func NewEventQueryClient(cc grpc.ClientConnInterface) EventQueryClient {
+	return &eventQueryClient{cc}
+}
+
+func (c *eventQueryClient) GetEvents(ctx context.Context, in *Query, opts ...grpc.CallOption) (*EventBook, error) {
+	out := new(EventBook)
+	err := c.cc.Invoke(ctx, "/angzarr.EventQuery/GetEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EventQueryServer is the server API for the EventQuery service: the read
+// side of the event store, serving history for rebuilds and sequence
+// checks.
+type EventQueryServer interface {
+	// GetEvents returns the event book matching the query.
+	GetEvents(ctx context.Context, in *Query) (*EventBook, error)
+}
+
+// RegisterEventQueryServer registers srv on s.
+func RegisterEventQueryServer(s grpc.ServiceRegistrar, srv EventQueryServer) {
+	s.RegisterService(&EventQuery_ServiceDesc, srv)
+}
+
+func _EventQuery_GetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventQueryServer).GetEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.EventQuery/GetEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventQueryServer).GetEvents(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EventQuery_ServiceDesc is the grpc.ServiceDesc for the EventQuery
+// service.
+var EventQuery_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "angzarr.EventQuery",
+	HandlerType: (*EventQueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetEvents", Handler: _EventQuery_GetEvents_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "angzarr.proto",
+}
+
+// DescriptionClient is the client API for the Description service.
+type DescriptionClient interface {
+	// Describe returns the service's routing table.
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*ServiceDescription, error)
+}
+
+type descriptionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewDescriptionClient creates a Description client on cc.
+func NewDescriptionClient(cc grpc.ClientConnInterface) DescriptionClient {
+	return &descriptionClient{cc}
+}
+
+func (c *descriptionClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*ServiceDescription, error) {
+	out := new(ServiceDescription)
+	err := c.cc.Invoke(ctx, "/angzarr.Description/Describe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DescriptionServer is the server API for the Description service: a
+// runtime view of a service's routing table for topology tooling.
+type DescriptionServer interface {
+	// Describe returns the service's routing table.
+	Describe(ctx context.Context, in *DescribeRequest) (*ServiceDescription, error)
+}
+
+// RegisterDescriptionServer registers srv on s.
+func RegisterDescriptionServer(s grpc.ServiceRegistrar, srv DescriptionServer) {
+	s.RegisterService(&Description_ServiceDesc, srv)
+}
+
+func _Description_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DescriptionServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.Description/Describe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DescriptionServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Description_ServiceDesc is the grpc.ServiceDesc for the Description
+// service.
+var Description_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "angzarr.Description",
+	HandlerType: (*DescriptionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Describe", Handler: _Description_Describe_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "angzarr.proto",
+}
+
+// SagaClient is the client API for the Saga service.
+type SagaClient interface {
+	// Prepare returns the destination covers to fetch before Handle.
+	Prepare(ctx context.Context, in *EventBook, opts ...grpc.CallOption) (*Preparation, error)
+	// Handle reacts to the trigger and returns the commands to issue.
+	Handle(ctx context.Context, in *SagaTrigger, opts ...grpc.CallOption) (*SagaResponse, error)
+	// Retry re-issues commands for the specific rejections in the
+	// request, leaving already-succeeded commands alone.
+	Retry(ctx context.Context, in *SagaRetryRequest, opts ...grpc.CallOption) (*SagaResponse, error)
+	// HandleRejection compensates for the downstream rejection of a
+	// command this saga emitted.
+	HandleRejection(ctx context.Context, in *SagaRejection, opts ...grpc.CallOption) (*SagaResponse, error)
+}
+
+type sagaClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewSagaClient creates a Saga client on cc.
+func NewSagaClient(cc grpc.ClientConnInterface) SagaClient {
+	return &sagaClient{cc}
+}
+
+func (c *sagaClient) Prepare(ctx context.Context, in *EventBook, opts ...grpc.CallOption) (*Preparation, error) {
+	out := new(Preparation)
+	err := c.cc.Invoke(ctx, "/angzarr.Saga/Prepare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaClient) Handle(ctx context.Context, in *SagaTrigger, opts ...grpc.CallOption) (*SagaResponse, error) {
+	out := new(SagaResponse)
+	err := c.cc.Invoke(ctx, "/angzarr.Saga/Handle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaClient) Retry(ctx context.Context, in *SagaRetryRequest, opts ...grpc.CallOption) (*SagaResponse, error) {
+	out := new(SagaResponse)
+	err := c.cc.Invoke(ctx, "/angzarr.Saga/Retry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaClient) HandleRejection(ctx context.Context, in *SagaRejection, opts ...grpc.CallOption) (*SagaResponse, error) {
+	out := new(SagaResponse)
+	err := c.cc.Invoke(ctx, "/angzarr.Saga/HandleRejection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SagaServer is the server API for the Saga service: the two-phase
+// contract the coordinator calls to let a saga name the destination state
+// it needs, then handle a trigger with that state fetched.
+type SagaServer interface {
+	// Prepare returns the destination covers to fetch before Handle.
+	Prepare(ctx context.Context, source *EventBook) (*Preparation, error)
+	// Handle reacts to the trigger and returns the commands to issue.
+	Handle(ctx context.Context, trigger *SagaTrigger) (*SagaResponse, error)
+	// Retry re-issues commands for the specific rejections in the
+	// request, leaving already-succeeded commands alone.
+	Retry(ctx context.Context, req *SagaRetryRequest) (*SagaResponse, error)
+	// HandleRejection compensates for the downstream rejection of a
+	// command this saga emitted.
+	HandleRejection(ctx context.Context, rejection *SagaRejection) (*SagaResponse, error)
+}
+
+// RegisterSagaServer registers srv on s.
+func RegisterSagaServer(s grpc.ServiceRegistrar, srv SagaServer) {
+	s.RegisterService(&Saga_ServiceDesc, srv)
+}
+
+func _Saga_Prepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventBook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaServer).Prepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.Saga/Prepare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaServer).Prepare(ctx, req.(*EventBook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Saga_Handle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SagaTrigger)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaServer).Handle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.Saga/Handle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaServer).Handle(ctx, req.(*SagaTrigger))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Saga_Retry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SagaRetryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaServer).Retry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.Saga/Retry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaServer).Retry(ctx, req.(*SagaRetryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Saga_HandleRejection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SagaRejection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaServer).HandleRejection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.Saga/HandleRejection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaServer).HandleRejection(ctx, req.(*SagaRejection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Saga_ServiceDesc is the grpc.ServiceDesc for the Saga service.
+var Saga_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "angzarr.Saga",
+	HandlerType: (*SagaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Prepare", Handler: _Saga_Prepare_Handler},
+		{MethodName: "Handle", Handler: _Saga_Handle_Handler},
+		{MethodName: "Retry", Handler: _Saga_Retry_Handler},
+		{MethodName: "HandleRejection", Handler: _Saga_HandleRejection_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "angzarr.proto",
+}
+
+// ProcessManagerServer is the server API for the ProcessManager service:
+// like Saga but with the PM's own state stream threaded through Handle.
+type ProcessManagerServer interface {
+	// Prepare returns the destination covers to fetch before Handle.
+	Prepare(ctx context.Context, trigger *EventBook) (*Preparation, error)
+	// Handle reacts to the trigger and returns commands plus events to
+	// append to the process manager's state stream.
+	Handle(ctx context.Context, trigger *ProcessManagerTrigger) (*ProcessManagerResponse, error)
+}
+
+// RegisterProcessManagerServer registers srv on s.
+func RegisterProcessManagerServer(s grpc.ServiceRegistrar, srv ProcessManagerServer) {
+	s.RegisterService(&ProcessManager_ServiceDesc, srv)
+}
+
+func _ProcessManager_Prepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventBook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessManagerServer).Prepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.ProcessManager/Prepare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessManagerServer).Prepare(ctx, req.(*EventBook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessManager_Handle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessManagerTrigger)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessManagerServer).Handle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/angzarr.ProcessManager/Handle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessManagerServer).Handle(ctx, req.(*ProcessManagerTrigger))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProcessManager_ServiceDesc is the grpc.ServiceDesc for the
+// ProcessManager service.
+var ProcessManager_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "angzarr.ProcessManager",
+	HandlerType: (*ProcessManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Prepare", Handler: _ProcessManager_Prepare_Handler},
+		{MethodName: "Handle", Handler: _ProcessManager_Handle_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "angzarr.proto",
+}
+
+// BusinessLogic_ServiceDesc is the grpc.ServiceDesc for the BusinessLogic
+// service.
+var BusinessLogic_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "angzarr.BusinessLogic",
+	HandlerType: (*BusinessLogicServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Handle", Handler: _BusinessLogic_Handle_Handler},
+		{MethodName: "HandleSync", Handler: _BusinessLogic_HandleSync_Handler},
+		{MethodName: "Speculate", Handler: _BusinessLogic_Speculate_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "HandleStream",
+			Handler:       _BusinessLogic_HandleStream_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "angzarr.proto",
+}
@@ -0,0 +1,55 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// QueryClient reads aggregate event history over gRPC.  Reads are
+// snapshot-aware: the server returns the latest snapshot plus the pages
+// after it when that satisfies the requested range, so callers rebuild
+// from a short book rather than the full stream.  Build one with
+// NewQueryClient.
+type QueryClient struct {
+	query pb.EventQueryClient
+}
+
+// NewQueryClient creates a query client on an established connection.
+func NewQueryClient(conn *grpc.ClientConn) *QueryClient {
+	return &QueryClient{query: pb.NewEventQueryClient(conn)}
+}
+
+// GetEvents returns the aggregate's full history, possibly compacted
+// behind its latest snapshot.
+func (c *QueryClient) GetEvents(ctx context.Context, domain string, root []byte) (*pb.EventBook, error) {
+	return c.query.GetEvents(ctx, &pb.Query{
+		Domain: domain,
+		Root:   &pb.UUID{Value: root},
+	})
+}
+
+// GetEventsSince returns the aggregate's pages at or above seq.  A
+// snapshot is included only when its sequence falls inside the requested
+// range.
+func (c *QueryClient) GetEventsSince(ctx context.Context, domain string, root []byte, seq uint32) (*pb.EventBook, error) {
+	return c.query.GetEvents(ctx, &pb.Query{
+		Domain:     domain,
+		Root:       &pb.UUID{Value: root},
+		LowerBound: seq,
+	})
+}
+
+// CurrentSequence returns the next free sequence in the aggregate's
+// stream — the value an outgoing command should carry for optimistic
+// concurrency.  It satisfies SequenceSource, so it plugs straight into
+// AggregateClient.WithSequenceSource.
+func (c *QueryClient) CurrentSequence(ctx context.Context, domain string, root []byte) (uint32, error) {
+	book, err := c.GetEvents(ctx, domain, root)
+	if err != nil {
+		return 0, err
+	}
+	return NextSequence(book), nil
+}
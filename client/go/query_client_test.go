@@ -0,0 +1,68 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// fakeEventQuery returns a canned book and records the last query.
+type fakeEventQuery struct {
+	last *pb.Query
+	book *pb.EventBook
+}
+
+func (f *fakeEventQuery) GetEvents(_ context.Context, in *pb.Query, _ ...grpc.CallOption) (*pb.EventBook, error) {
+	f.last = in
+	return f.book, nil
+}
+
+func TestQueryClientGetEventsSinceSetsLowerBound(t *testing.T) {
+	fake := &fakeEventQuery{book: testBook(t, 3, 4)}
+	client := &QueryClient{query: fake}
+
+	book, err := client.GetEventsSince(context.Background(), "player", testRoot().GetValue(), 3)
+	if err != nil {
+		t.Fatalf("get events since: %v", err)
+	}
+	if fake.last.GetLowerBound() != 3 {
+		t.Errorf("lower bound = %d, want 3", fake.last.GetLowerBound())
+	}
+	if fake.last.GetDomain() != "player" {
+		t.Errorf("domain = %q, want %q", fake.last.GetDomain(), "player")
+	}
+	if len(book.GetPages()) != 2 {
+		t.Errorf("got %d pages, want 2", len(book.GetPages()))
+	}
+}
+
+func TestQueryClientCurrentSequence(t *testing.T) {
+	fake := &fakeEventQuery{book: testBook(t, 0, 1, 2)}
+	client := &QueryClient{query: fake}
+
+	seq, err := client.CurrentSequence(context.Background(), "player", testRoot().GetValue())
+	if err != nil {
+		t.Fatalf("current sequence: %v", err)
+	}
+	if seq != 3 {
+		t.Errorf("current sequence = %d, want 3", seq)
+	}
+}
+
+func TestQueryClientCurrentSequenceSnapshotAware(t *testing.T) {
+	book := testBook(t, 5)
+	book.Snapshot = &pb.Snapshot{Sequence: 4}
+	fake := &fakeEventQuery{book: book}
+	client := &QueryClient{query: fake}
+
+	seq, err := client.CurrentSequence(context.Background(), "player", testRoot().GetValue())
+	if err != nil {
+		t.Fatalf("current sequence: %v", err)
+	}
+	if seq != 6 {
+		t.Errorf("current sequence = %d, want 6", seq)
+	}
+}
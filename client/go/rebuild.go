@@ -0,0 +1,54 @@
+package angzarr
+
+import (
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// RebuildWithSnapshot folds an event book into state of type S, starting
+// from the book's snapshot when it has one instead of replaying from the
+// beginning.  fresh produces the zero state for a stream with no
+// snapshot; restore rehydrates state from the snapshot payload; apply
+// folds one event into the state.  Pages at or below the snapshot's
+// sequence are skipped — a store that returns overlapping history does
+// not double-apply events.  Rebuild functions for CommandRouter wrap it:
+//
+//	router.WithRebuild(func(events *pb.EventBook) any {
+//		state, err := angzarr.RebuildWithSnapshot(events, newLedger, restoreLedger, applyLedger)
+//		...
+//	})
+func RebuildWithSnapshot[S any](
+	book *pb.EventBook,
+	fresh func() *S,
+	restore func(state *anypb.Any) (*S, error),
+	apply func(state *S, event *anypb.Any) error,
+) (*S, error) {
+	var state *S
+	base := uint32(0)
+	snap := book.GetSnapshot()
+	if snap != nil {
+		restored, err := restore(snap.GetState())
+		if err != nil {
+			return nil, err
+		}
+		state = restored
+		base = snap.GetSequence() + 1
+	} else {
+		state = fresh()
+	}
+	for _, page := range book.GetPages() {
+		if snap != nil {
+			if num, ok := pageNum(page); ok && num < base {
+				continue
+			}
+		}
+		if page.GetEvent() == nil {
+			continue
+		}
+		if err := apply(state, page.GetEvent()); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,102 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// balance stands in for transaction state: a running total restored from
+// an Int32Value snapshot, incremented by each Int32Value event.
+type balance struct {
+	total int32
+}
+
+func newBalance() *balance { return &balance{} }
+
+func restoreBalance(state *anypb.Any) (*balance, error) {
+	var snap wrapperspb.Int32Value
+	if err := state.UnmarshalTo(&snap); err != nil {
+		return nil, err
+	}
+	return &balance{total: snap.Value}, nil
+}
+
+func applyBalance(b *balance, event *anypb.Any) error {
+	var delta wrapperspb.Int32Value
+	if err := event.UnmarshalTo(&delta); err != nil {
+		return err
+	}
+	b.total += delta.Value
+	return nil
+}
+
+// deltaBook builds an event book of Int32Value deltas, one per entry,
+// starting at the given sequence.
+func deltaBook(t *testing.T, start uint32, deltas ...int32) *pb.EventBook {
+	t.Helper()
+	book := &pb.EventBook{
+		Cover: &pb.Cover{Domain: "transaction", Root: testRoot()},
+	}
+	for i, delta := range deltas {
+		event, err := anypb.New(wrapperspb.Int32(delta))
+		if err != nil {
+			t.Fatalf("packing: %v", err)
+		}
+		book.Pages = append(book.Pages, &pb.EventPage{
+			Sequence: &pb.EventPage_Num{Num: start + uint32(i)},
+			Event:    event,
+		})
+	}
+	return book
+}
+
+func TestRebuildWithSnapshotFromScratch(t *testing.T) {
+	book := deltaBook(t, 0, 10, 5)
+	state, err := RebuildWithSnapshot(book, newBalance, restoreBalance, applyBalance)
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if state.total != 15 {
+		t.Errorf("total = %d, want 15", state.total)
+	}
+}
+
+func TestRebuildWithSnapshotStartsFromSnapshot(t *testing.T) {
+	book := deltaBook(t, 5, 7)
+	snapState, err := anypb.New(wrapperspb.Int32(100))
+	if err != nil {
+		t.Fatalf("packing snapshot: %v", err)
+	}
+	book.Snapshot = &pb.Snapshot{Sequence: 4, State: snapState}
+
+	state, err := RebuildWithSnapshot(book, newBalance, restoreBalance, applyBalance)
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if state.total != 107 {
+		t.Errorf("total = %d, want 107 (snapshot 100 + delta 7)", state.total)
+	}
+}
+
+func TestRebuildWithSnapshotSkipsCoveredPages(t *testing.T) {
+	// The store returned overlapping history: pages 3 and 4 are already
+	// folded into the snapshot and must not apply twice.
+	book := deltaBook(t, 3, 50, 50, 7)
+	snapState, err := anypb.New(wrapperspb.Int32(100))
+	if err != nil {
+		t.Fatalf("packing snapshot: %v", err)
+	}
+	book.Snapshot = &pb.Snapshot{Sequence: 4, State: snapState}
+
+	state, err := RebuildWithSnapshot(book, newBalance, restoreBalance, applyBalance)
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if state.total != 107 {
+		t.Errorf("total = %d, want 107 (pages at or below snapshot ignored)", state.total)
+	}
+}
@@ -0,0 +1,99 @@
+package angzarr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// redactMode selects what happens to a matched field.
+type redactMode int
+
+const (
+	redactZero redactMode = iota
+	redactHash
+)
+
+// Redactor produces redacted copies of proto messages for external
+// publication — CloudEvents payloads, log projections — so projectors
+// don't hand-maintain parallel "Public*" message types.  Register the
+// dotted field paths to strip with Zero, or to replace with a stable
+// token with Hash, then call Redact.  Paths that don't exist on a given
+// message are ignored, so one redactor can serve several event types.
+type Redactor struct {
+	rules map[string]redactMode
+}
+
+// NewRedactor creates a redactor with no rules; it copies messages
+// unchanged until Zero or Hash registrations are added.
+func NewRedactor() *Redactor {
+	return &Redactor{rules: make(map[string]redactMode)}
+}
+
+// Zero clears the fields at the given dotted paths in redacted copies.
+func (r *Redactor) Zero(paths ...string) *Redactor {
+	for _, path := range paths {
+		r.rules[path] = redactZero
+	}
+	return r
+}
+
+// Hash replaces the string fields at the given dotted paths with
+// HashToken of their value, so redacted copies of the same input remain
+// correlatable without exposing the original — the treatment emails
+// need.  Non-string fields at a hashed path are cleared instead.
+func (r *Redactor) Hash(paths ...string) *Redactor {
+	for _, path := range paths {
+		r.rules[path] = redactHash
+	}
+	return r
+}
+
+// Redact returns a copy of msg with every registered rule applied.  The
+// original is never modified.
+func (r *Redactor) Redact(msg proto.Message) proto.Message {
+	clone := proto.Clone(msg)
+	for path, mode := range r.rules {
+		applyRedaction(clone.ProtoReflect(), strings.Split(path, "."), mode)
+	}
+	return clone
+}
+
+// applyRedaction walks the dotted path into the message and applies the
+// mode to the final field.  Missing fields, unset intermediate messages,
+// and paths descending into non-message fields are silently skipped.
+func applyRedaction(m protoreflect.Message, path []string, mode redactMode) {
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if fd == nil {
+		return
+	}
+	if len(path) > 1 {
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() || !m.Has(fd) {
+			return
+		}
+		applyRedaction(m.Mutable(fd).Message(), path[1:], mode)
+		return
+	}
+	switch mode {
+	case redactHash:
+		if fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() {
+			m.Set(fd, protoreflect.ValueOfString(HashToken(m.Get(fd).String())))
+			return
+		}
+		m.Clear(fd)
+	default:
+		m.Clear(fd)
+	}
+}
+
+// HashToken maps a sensitive string to a stable, non-reversible token:
+// "redacted:" plus the first 16 hex characters of its SHA-256.  Equal
+// inputs always produce equal tokens, so redacted streams can still be
+// grouped and joined per subject.
+func HashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "redacted:" + hex.EncodeToString(sum[:8])
+}
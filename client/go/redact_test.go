@@ -0,0 +1,56 @@
+package angzarr
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRedactorHashesStringField(t *testing.T) {
+	redactor := NewRedactor().Hash("value")
+	original := wrapperspb.String("alice@example.com")
+
+	redacted := redactor.Redact(original).(*wrapperspb.StringValue)
+	if redacted.Value == original.Value {
+		t.Fatal("email survived redaction")
+	}
+	if !strings.HasPrefix(redacted.Value, "redacted:") {
+		t.Errorf("hashed value = %q, want a redacted: token", redacted.Value)
+	}
+	if original.Value != "alice@example.com" {
+		t.Error("redaction modified the original message")
+	}
+
+	// Stable: the same input maps to the same token across calls.
+	again := redactor.Redact(wrapperspb.String("alice@example.com")).(*wrapperspb.StringValue)
+	if again.Value != redacted.Value {
+		t.Errorf("tokens differ for equal input: %q vs %q", again.Value, redacted.Value)
+	}
+	other := redactor.Redact(wrapperspb.String("bob@example.com")).(*wrapperspb.StringValue)
+	if other.Value == redacted.Value {
+		t.Error("distinct inputs collided")
+	}
+}
+
+func TestRedactorZeroesField(t *testing.T) {
+	redacted := NewRedactor().Zero("value").Redact(wrapperspb.Int32(4111)).(*wrapperspb.Int32Value)
+	if redacted.Value != 0 {
+		t.Errorf("value = %d, want 0", redacted.Value)
+	}
+}
+
+func TestRedactorHashOnNonStringClears(t *testing.T) {
+	redacted := NewRedactor().Hash("value").Redact(wrapperspb.Int32(4111)).(*wrapperspb.Int32Value)
+	if redacted.Value != 0 {
+		t.Errorf("non-string hash target = %d, want cleared to 0", redacted.Value)
+	}
+}
+
+func TestRedactorIgnoresMissingPaths(t *testing.T) {
+	original := wrapperspb.String("keep me")
+	redacted := NewRedactor().Zero("email", "profile.email").Redact(original).(*wrapperspb.StringValue)
+	if redacted.Value != "keep me" {
+		t.Errorf("unrelated field changed: %q", redacted.Value)
+	}
+}
@@ -0,0 +1,122 @@
+package angzarr
+
+import (
+	"sort"
+	"sync"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// SeenStore records which event sequences a replay has already applied,
+// letting a resumed or repeated backfill skip them.  Implementations must
+// be safe for the replay's sequential use; MemorySeenStore suffices for
+// single-process rebuilds.
+type SeenStore interface {
+	// Seen reports whether the sequence was already applied for the cover.
+	Seen(cover *pb.Cover, seq uint32) bool
+	// Mark records the sequence as applied for the cover.
+	Mark(cover *pb.Cover, seq uint32)
+}
+
+// MemorySeenStore is an in-memory SeenStore.
+type MemorySeenStore struct {
+	mu   sync.Mutex
+	seen map[string]map[uint32]bool
+}
+
+// NewMemorySeenStore creates an empty in-memory seen store.
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: make(map[string]map[uint32]bool)}
+}
+
+// Seen implements SeenStore.
+func (s *MemorySeenStore) Seen(cover *pb.Cover, seq uint32) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[coverKey(cover)][seq]
+}
+
+// Mark implements SeenStore.
+func (s *MemorySeenStore) Mark(cover *pb.Cover, seq uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := coverKey(cover)
+	if s.seen[key] == nil {
+		s.seen[key] = make(map[uint32]bool)
+	}
+	s.seen[key][seq] = true
+}
+
+// ReplayOption configures a Replay call.
+type ReplayOption func(*replayConfig)
+
+type replayConfig struct {
+	seen     SeenStore
+	progress func(applied, total int)
+}
+
+// WithSeenStore skips pages the store has already seen and marks each page
+// after its book is applied, making the replay resumable.
+func WithSeenStore(store SeenStore) ReplayOption {
+	return func(c *replayConfig) { c.seen = store }
+}
+
+// WithProgress registers a callback invoked after each root's book is
+// applied, with the number of roots applied so far and the total.
+func WithProgress(progress func(applied, total int)) ReplayOption {
+	return func(c *replayConfig) { c.progress = progress }
+}
+
+// Replay feeds historical event books through a projector or state router
+// dispatch function to rebuild a read model from scratch — pass
+// router.Handle for a StateRouter, or wrap a ProjectorHandler's
+// HandleSync.  Pages are regrouped per root and sorted by sequence before
+// delivery, so interleaved or out-of-order input books replay in the
+// order the events were recorded.  The first dispatch error aborts the
+// replay.
+func Replay(handle func(*pb.EventBook) error, books []*pb.EventBook, opts ...ReplayOption) error {
+	var cfg replayConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// Regroup pages per root, preserving first-seen root order.
+	var order []string
+	grouped := make(map[string]*pb.EventBook)
+	for _, book := range books {
+		key := coverKey(book.GetCover())
+		merged, ok := grouped[key]
+		if !ok {
+			merged = &pb.EventBook{Cover: book.GetCover()}
+			grouped[key] = merged
+			order = append(order, key)
+		}
+		for _, page := range book.GetPages() {
+			if cfg.seen != nil && cfg.seen.Seen(book.GetCover(), PageSequence(page)) {
+				continue
+			}
+			merged.Pages = append(merged.Pages, page)
+		}
+	}
+
+	for applied, key := range order {
+		book := grouped[key]
+		sort.SliceStable(book.Pages, func(i, j int) bool {
+			return PageSequence(book.Pages[i]) < PageSequence(book.Pages[j])
+		})
+		if len(book.Pages) > 0 {
+			if err := handle(book); err != nil {
+				return err
+			}
+			if cfg.seen != nil {
+				for _, page := range book.Pages {
+					cfg.seen.Mark(book.GetCover(), PageSequence(page))
+				}
+			}
+		}
+		if cfg.progress != nil {
+			cfg.progress(applied+1, len(order))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestReplayOrdersPerRootBySequence(t *testing.T) {
+	// Two books for the same root arriving newest-first.
+	later := NewEventBookBuilder("player", testRoot().GetValue()).
+		AppendAt(2, wrapperspb.String("third")).Build()
+	earlier := NewEventBookBuilder("player", testRoot().GetValue()).
+		Append(wrapperspb.String("first")).
+		Append(wrapperspb.String("second")).Build()
+
+	var got []uint32
+	err := Replay(func(book *pb.EventBook) error {
+		for _, page := range book.GetPages() {
+			got = append(got, PageSequence(page))
+		}
+		return nil
+	}, []*pb.EventBook{later, earlier})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	want := []uint32{0, 1, 2}
+	for i := range want {
+		if i >= len(got) || got[i] != want[i] {
+			t.Fatalf("replayed sequences = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestReplaySkipsSeenSequences(t *testing.T) {
+	book := NewEventBookBuilder("player", testRoot().GetValue()).
+		Append(wrapperspb.String("first")).
+		Append(wrapperspb.String("second")).Build()
+	seen := NewMemorySeenStore()
+	seen.Mark(book.GetCover(), 0)
+
+	var delivered int
+	err := Replay(func(b *pb.EventBook) error {
+		delivered += len(b.GetPages())
+		return nil
+	}, []*pb.EventBook{book}, WithSeenStore(seen))
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if delivered != 1 {
+		t.Errorf("delivered %d pages, want 1 (sequence 0 already seen)", delivered)
+	}
+	if !seen.Seen(book.GetCover(), 1) {
+		t.Error("applied sequence not marked seen")
+	}
+
+	// A second replay over the same store delivers nothing.
+	delivered = 0
+	if err := Replay(func(b *pb.EventBook) error {
+		delivered += len(b.GetPages())
+		return nil
+	}, []*pb.EventBook{book}, WithSeenStore(seen)); err != nil {
+		t.Fatalf("second replay: %v", err)
+	}
+	if delivered != 0 {
+		t.Errorf("second replay delivered %d pages, want 0", delivered)
+	}
+}
+
+func TestReplayReportsProgress(t *testing.T) {
+	books := []*pb.EventBook{
+		NewEventBookBuilder("player", []byte("root0000000000aa")).Append(wrapperspb.String("a")).Build(),
+		NewEventBookBuilder("player", []byte("root0000000000bb")).Append(wrapperspb.String("b")).Build(),
+	}
+	var steps []int
+	err := Replay(func(*pb.EventBook) error { return nil }, books,
+		WithProgress(func(applied, total int) {
+			if total != 2 {
+				t.Errorf("total = %d, want 2", total)
+			}
+			steps = append(steps, applied)
+		}))
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(steps) != 2 || steps[0] != 1 || steps[1] != 2 {
+		t.Errorf("progress steps = %v, want [1 2]", steps)
+	}
+}
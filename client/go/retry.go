@@ -0,0 +1,135 @@
+package angzarr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// RetryPolicy governs how many times a transiently failed send is
+// reattempted and how long to wait between attempts.  The delay grows
+// geometrically from BaseDelay by Multiplier, capped at MaxDelay.  The
+// zero value means no retries: a single attempt, surfacing its error.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first.
+	// Values below 1 are treated as 1.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the grown delay; zero means uncapped.
+	MaxDelay time.Duration
+	// Multiplier scales the delay after each attempt; values at or
+	// below 1 keep the delay constant at BaseDelay.
+	Multiplier float64
+}
+
+// attempts returns the bounded attempt count.
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// delay returns the wait before retry number n (1-based).
+func (p RetryPolicy) delay(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n; i++ {
+		if p.Multiplier > 1 {
+			d = time.Duration(float64(d) * p.Multiplier)
+		}
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return d
+}
+
+// Do runs fn up to MaxAttempts times, backing off between attempts,
+// until fn succeeds or returns an error retryable reports false for.
+// Context cancellation cuts the backoff short and returns ctx.Err().
+func (p RetryPolicy) Do(ctx context.Context, retryable func(error) bool, fn func() error) error {
+	attempts := p.attempts()
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+		select {
+		case <-time.After(p.delay(attempt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// transientSend reports whether an emission failure looks like a
+// downstream outage worth retrying, as opposed to a business rejection.
+func transientSend(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// CommandSender delivers saga-emitted commands to the owning aggregate's
+// BusinessLogic service, retrying transient failures per its RetryPolicy.
+// Every command book is stamped with a correlation id before the first
+// attempt, so the receiving aggregate can deduplicate a retry whose
+// original delivery actually landed — retries never mint a fresh id.
+type CommandSender struct {
+	logic  pb.BusinessLogicClient
+	policy RetryPolicy
+}
+
+// NewCommandSender creates a sender on an established connection.
+func NewCommandSender(conn *grpc.ClientConn, policy RetryPolicy) *CommandSender {
+	return &CommandSender{logic: pb.NewBusinessLogicClient(conn), policy: policy}
+}
+
+// Send delivers one command book, reusing its correlation id as the
+// idempotency key across retries.  Only Unavailable and DeadlineExceeded
+// failures are retried; rejections surface immediately.
+func (s *CommandSender) Send(ctx context.Context, book *pb.CommandBook) error {
+	if cover := book.GetCover(); cover != nil && cover.GetCorrelationId() == "" {
+		cover.CorrelationId = newCorrelationID()
+	}
+	return s.policy.Do(ctx, transientSend, func() error {
+		_, err := s.logic.Handle(ctx, &pb.ContextualCommand{Command: book})
+		return err
+	})
+}
+
+// SendAll delivers each emitted command book in order, stopping at the
+// first error that exhausts its retries.
+func (s *CommandSender) SendAll(ctx context.Context, books []*pb.CommandBook) error {
+	for _, book := range books {
+		if err := s.Send(ctx, book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newCorrelationID mints a random 16-byte id in dashed UUID text form.
+func newCorrelationID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand failing is unrecoverable; fall back to a
+		// constant-free zero id rather than panicking mid-send.
+		return hex.EncodeToString(b[:])
+	}
+	return BytesToUUIDText(b[:])
+}
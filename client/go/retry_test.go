@@ -0,0 +1,110 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestRetryPolicyDoRetriesTransient(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	var attempts int
+	err := policy.Do(context.Background(), transientSend, func() error {
+		attempts++
+		if attempts < 3 {
+			return status.Error(codes.Unavailable, "downstream down")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRetryPolicyDoStopsOnPermanentError(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}
+	var attempts int
+	err := policy.Do(context.Background(), transientSend, func() error {
+		attempts++
+		return FieldError("amount", "must be positive")
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("code = %v, want InvalidArgument", status.Code(err))
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (rejections are final)", attempts)
+	}
+}
+
+func TestRetryPolicyDelayGrowsAndCaps(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay:  10 * time.Millisecond,
+		MaxDelay:   35 * time.Millisecond,
+		Multiplier: 2,
+	}
+	for i, want := range []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		35 * time.Millisecond,
+		35 * time.Millisecond,
+	} {
+		if got := policy.delay(i + 1); got != want {
+			t.Errorf("delay(%d) = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestCommandSenderReusesIdempotencyKey(t *testing.T) {
+	fake := &fakeBusinessLogic{
+		errs: []error{status.Error(codes.Unavailable, "flaky"), nil},
+	}
+	sender := &CommandSender{
+		logic:  fake,
+		policy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	book := &pb.CommandBook{
+		Cover: &pb.Cover{Domain: "loyalty", Root: testRoot()},
+	}
+	if err := sender.Send(context.Background(), book); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(fake.calls) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(fake.calls))
+	}
+	first := fake.calls[0].GetCommand().GetCover().GetCorrelationId()
+	second := fake.calls[1].GetCommand().GetCover().GetCorrelationId()
+	if first == "" {
+		t.Fatal("no correlation id stamped before first attempt")
+	}
+	if first != second {
+		t.Errorf("retry minted a new id: %q then %q", first, second)
+	}
+}
+
+func TestCommandSenderDoesNotRetryRejections(t *testing.T) {
+	fake := &fakeBusinessLogic{errs: []error{FailedPrecondition("insufficient points")}}
+	sender := &CommandSender{
+		logic:  fake,
+		policy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	err := sender.SendAll(context.Background(), []*pb.CommandBook{
+		commandFor(t, "loyalty", wrapperspb.String("award"), nil).GetCommand(),
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("code = %v, want FailedPrecondition", status.Code(err))
+	}
+	if len(fake.calls) != 1 {
+		t.Errorf("got %d attempts, want 1", len(fake.calls))
+	}
+}
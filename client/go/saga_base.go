@@ -0,0 +1,52 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// SagaBase is an embeddable base for method-style saga types.  It wraps an
+// EventRouter, so annotated saga structs get the full registration surface
+// (Subscribes, On, OnRetry, Sends) through promotion:
+//
+//	type CancellationSaga struct {
+//		angzarr.SagaBase
+//	}
+//
+//	saga := CancellationSaga{SagaBase: angzarr.NewSagaBase("saga-cancellation", "order")}
+//	saga.OnRetry(saga.retryCompensations)
+type SagaBase struct {
+	*EventRouter
+}
+
+// NewSagaBase creates a saga base around a new event router with the given
+// saga name and optional source domain.
+func NewSagaBase(name string, sourceDomain ...string) SagaBase {
+	return SagaBase{EventRouter: NewEventRouter(name, sourceDomain...)}
+}
+
+// ReactsTo registers a reaction that emits at most one command book per
+// event — the common OO saga shape.  Returning a nil book (and nil
+// error) emits nothing.  For typed payload unmarshaling combine with
+// OnTyped on the embedded router.
+func (b SagaBase) ReactsTo(name string, react func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) (*pb.CommandBook, error)) SagaBase {
+	b.On(name, func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+		book, err := react(ctx, source, event, destinations)
+		if err != nil || book == nil {
+			return nil, err
+		}
+		return []*pb.CommandBook{book}, nil
+	})
+	return b
+}
+
+// ReactsToMany registers a fan-out reaction that may emit any number of
+// command books — one per winner, say — so sagas that fan out no longer
+// have to abandon the method style for the functional API.
+func (b SagaBase) ReactsToMany(name string, react EventHandler) SagaBase {
+	b.On(name, react)
+	return b
+}
@@ -0,0 +1,68 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestSagaBaseReactsToEmitsSingleBook(t *testing.T) {
+	saga := NewSagaBase("saga-hand-oo", "table").
+		ReactsTo("StringValue", func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) (*pb.CommandBook, error) {
+			return &pb.CommandBook{
+				Cover: &pb.Cover{Domain: "hand", Root: testRoot()},
+				Pages: []*pb.CommandPage{{}},
+			}, nil
+		})
+
+	commands, err := saga.Handle(context.Background(), triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("go")), nil)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(commands) != 1 {
+		t.Fatalf("got %d commands, want 1", len(commands))
+	}
+}
+
+func TestSagaBaseReactsToNilBookEmitsNothing(t *testing.T) {
+	saga := NewSagaBase("saga-hand-oo", "table").
+		ReactsTo("StringValue", func(context.Context, *pb.EventBook, *anypb.Any, []*pb.EventBook) (*pb.CommandBook, error) {
+			return nil, nil
+		})
+
+	commands, err := saga.Handle(context.Background(), triggerBook(t, "table", []byte("tableroot0000000"), 0, wrapperspb.String("go")), nil)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(commands) != 0 {
+		t.Fatalf("nil reaction emitted %d commands", len(commands))
+	}
+}
+
+func TestSagaBaseReactsToManyFansOut(t *testing.T) {
+	// One command per winner, as saga-hand-player needs.
+	winners := []string{"playerroot000001", "playerroot000002"}
+	saga := NewSagaBase("saga-hand-player", "hand").
+		ReactsToMany("StringValue", func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+			var books []*pb.CommandBook
+			for _, winner := range winners {
+				books = append(books, &pb.CommandBook{
+					Cover: &pb.Cover{Domain: "player", Root: &pb.UUID{Value: []byte(winner)}},
+					Pages: []*pb.CommandPage{{}},
+				})
+			}
+			return books, nil
+		})
+
+	commands, err := saga.Handle(context.Background(), triggerBook(t, "hand", []byte("handroot00000000"), 0, wrapperspb.String("showdown")), nil)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(commands) != 2 {
+		t.Fatalf("got %d commands, want one per winner", len(commands))
+	}
+}
@@ -0,0 +1,182 @@
+package angzarr
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// TestSaga drives source events through a real EventRouter in Given/When
+// style, running the full Prepare and Handle path so destination sequence
+// assignment is exercised:
+//
+//	h := angzarr.NewTestSaga(t, router)
+//	h.WithDestination(playerBook)
+//	h.When(&examples.HandFinished{...}).
+//		ExpectCommand("player", "CreditWinnings")
+//
+// Given events build up the source stream; each When appends the trigger
+// at the next sequence and delivers only that page, as the coordinator
+// does for newly-recorded events.
+type TestSaga struct {
+	tb           testing.TB
+	router       *EventRouter
+	root         []byte
+	source       *pb.EventBook
+	destinations map[string]*pb.EventBook
+	now          time.Time
+}
+
+// NewTestSaga creates a harness around the given event router, with a
+// fixed default source root and a fake clock for deterministic timestamps.
+func NewTestSaga(tb testing.TB, router *EventRouter) *TestSaga {
+	tb.Helper()
+	return &TestSaga{
+		tb:           tb,
+		router:       router,
+		root:         []byte("0123456789abcdef"),
+		destinations: make(map[string]*pb.EventBook),
+		now:          time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// WithRoot replaces the default source root identifier.
+func (h *TestSaga) WithRoot(root []byte) *TestSaga {
+	h.root = root
+	return h
+}
+
+// WithDestination registers a destination event book served to the saga
+// when Prepare names its cover.  The book's own cover determines the
+// registration key.
+func (h *TestSaga) WithDestination(book *pb.EventBook) *TestSaga {
+	h.destinations[coverKey(book.GetCover())] = book
+	return h
+}
+
+// Given appends prior events to the source stream without dispatching
+// them, packing each message and assigning contiguous sequences.
+func (h *TestSaga) Given(events ...proto.Message) *TestSaga {
+	h.tb.Helper()
+	for _, event := range events {
+		h.appendSource(h.pack(event))
+	}
+	return h
+}
+
+// When appends the trigger event to the source stream and runs the real
+// Prepare and Handle path for it: covers named by Prepare are resolved
+// against the registered destinations, and Handle assigns destination
+// sequences to the emitted commands.
+func (h *TestSaga) When(event proto.Message) *SagaResult {
+	h.tb.Helper()
+	page := h.appendSource(h.pack(event))
+	trigger := &pb.EventBook{
+		Cover:    h.source.GetCover(),
+		Snapshot: h.source.GetSnapshot(),
+		Pages:    []*pb.EventPage{page},
+	}
+	covers, err := h.router.PrepareCovers(context.Background(), trigger)
+	if err != nil {
+		return &SagaResult{tb: h.tb, err: err}
+	}
+	var destinations []*pb.EventBook
+	for _, cover := range covers {
+		book, ok := h.destinations[coverKey(cover)]
+		if !ok {
+			h.tb.Fatalf("prepare requested unregistered destination %s/%s",
+				cover.GetDomain(), hex.EncodeToString(cover.GetRoot().GetValue()))
+		}
+		destinations = append(destinations, book)
+	}
+	commands, err := h.router.Handle(context.Background(), trigger, destinations)
+	return &SagaResult{tb: h.tb, commands: commands, err: err}
+}
+
+// pack marshals a message into an Any, failing the test on error.
+func (h *TestSaga) pack(event proto.Message) *anypb.Any {
+	h.tb.Helper()
+	packed, err := anypb.New(event)
+	if err != nil {
+		h.tb.Fatalf("packing event: %v", err)
+	}
+	return packed
+}
+
+// appendSource adds one packed event to the source stream and returns the
+// new page.
+func (h *TestSaga) appendSource(event *anypb.Any) *pb.EventPage {
+	if h.source == nil {
+		h.source = &pb.EventBook{
+			Cover: &pb.Cover{Domain: h.router.SourceDomain(), Root: &pb.UUID{Value: h.root}},
+		}
+	}
+	page := &pb.EventPage{
+		Sequence:  &pb.EventPage_Num{Num: NextSequence(h.source)},
+		CreatedAt: timestamppb.New(h.now),
+		Event:     event,
+	}
+	h.source.Pages = append(h.source.Pages, page)
+	return page
+}
+
+// SagaResult carries one trigger's emitted commands with assertion
+// helpers.  Expectation failures are reported through the harness's
+// testing.TB.
+type SagaResult struct {
+	tb       testing.TB
+	commands []*pb.CommandBook
+	err      error
+}
+
+// Commands returns the emitted command books, nil when dispatch failed.
+func (r *SagaResult) Commands() []*pb.CommandBook {
+	return r.commands
+}
+
+// Err returns the dispatch error, nil on success.
+func (r *SagaResult) Err() error {
+	return r.err
+}
+
+// ExpectCommand asserts that a command with the given short type name was
+// emitted against the given domain.
+func (r *SagaResult) ExpectCommand(domain, name string) *SagaResult {
+	r.tb.Helper()
+	if r.err != nil {
+		r.tb.Errorf("expected command %s/%s, got error: %v", domain, name, r.err)
+		return r
+	}
+	for _, book := range r.commands {
+		if book.GetCover().GetDomain() != domain {
+			continue
+		}
+		for _, page := range book.GetPages() {
+			if TypeNameEquals(page.GetCommand().GetTypeUrl(), name) {
+				return r
+			}
+		}
+	}
+	r.tb.Errorf("expected command %s/%s among %d emitted books", domain, name, len(r.commands))
+	return r
+}
+
+// ExpectCommandCount asserts the number of emitted command books.
+func (r *SagaResult) ExpectCommandCount(n int) *SagaResult {
+	r.tb.Helper()
+	if r.err != nil {
+		r.tb.Errorf("expected %d commands, got error: %v", n, r.err)
+		return r
+	}
+	if got := len(r.commands); got != n {
+		r.tb.Errorf("expected %d emitted command books, got %d", n, got)
+	}
+	return r
+}
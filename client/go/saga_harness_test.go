@@ -0,0 +1,59 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestSagaHarnessRunsPrepareAndHandle(t *testing.T) {
+	destination := testBook(t, 0, 1, 2)
+	destination.Cover = &pb.Cover{Domain: "hand", Root: &pb.UUID{Value: []byte("handroot00000000")}}
+
+	h := NewTestSaga(t, dealCardsSaga()).WithDestination(destination)
+	res := h.When(wrapperspb.String("handroot00000000"))
+
+	res.ExpectCommandCount(1)
+	// The real Handle path assigned the destination's NextSequence.
+	if got := res.Commands()[0].Pages[0].GetSequence(); got != 3 {
+		t.Errorf("command sequence = %d, want 3", got)
+	}
+}
+
+func TestSagaHarnessExpectCommandMatchesType(t *testing.T) {
+	router := NewEventRouter("saga-hand-player").Domain("hand").
+		WithPrepare(func(source *pb.EventBook) []*pb.Cover { return nil }).
+		On("StringValue", func(ctx context.Context, source *pb.EventBook, event *anypb.Any, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+			cmd, err := anypb.New(wrapperspb.Bool(true))
+			if err != nil {
+				return nil, err
+			}
+			return []*pb.CommandBook{{
+				Cover: &pb.Cover{Domain: "player", Root: &pb.UUID{Value: []byte("playerroot000000")}},
+				Pages: []*pb.CommandPage{{Payload: &pb.CommandPage_Command{Command: cmd}}},
+			}}, nil
+		})
+
+	NewTestSaga(t, router).
+		When(wrapperspb.String("winner")).
+		ExpectCommand("player", "BoolValue")
+}
+
+func TestSagaHarnessGivenDoesNotDispatch(t *testing.T) {
+	destination := testBook(t, 0)
+	destination.Cover = &pb.Cover{Domain: "hand", Root: &pb.UUID{Value: []byte("handroot00000000")}}
+
+	h := NewTestSaga(t, dealCardsSaga()).WithDestination(destination)
+	// Given events are context only; a single When dispatches once.
+	h.Given(wrapperspb.String("handroot00000000"), wrapperspb.String("handroot00000000"))
+	h.When(wrapperspb.String("handroot00000000")).ExpectCommandCount(1)
+}
+
+func TestSagaHarnessIgnoresUnsubscribedTrigger(t *testing.T) {
+	h := NewTestSaga(t, dealCardsSaga())
+	h.When(wrapperspb.Bool(true)).ExpectCommandCount(0)
+}
@@ -0,0 +1,764 @@
+package angzarr
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// defaultShutdownTimeout bounds graceful drain when the config leaves
+// ShutdownTimeout at zero.
+const defaultShutdownTimeout = 30 * time.Second
+
+// defaultSyncTimeout bounds how long a synchronous command waits for
+// downstream acknowledgement when the config leaves SyncTimeout at zero.
+const defaultSyncTimeout = 5 * time.Second
+
+// ServerConfig carries the settings shared by the Run* server helpers.
+// Zero values fall back to sensible defaults, so examples can populate only
+// the fields they care about.
+type ServerConfig struct {
+	// Domain is the aggregate/saga domain the server fronts.
+	Domain string
+	// DefaultPort is the listen port used when the PORT environment
+	// variable is unset.
+	DefaultPort string
+	// Subscriptions lists domain/Type pairs (or bare domains, meaning
+	// every type) to register on the saga or process manager being
+	// served, on top of what code declares.  LoadConfig populates it
+	// from ANGZARR_SUBSCRIBE or a config file.
+	Subscriptions []string
+	// SchemaVersion is the event schema/contract version of this service.
+	// When non-zero, every event emitted through the router is stamped
+	// with it (see CommandRouter.WithEventSchemaVersion) so consumers can
+	// detect and upcast older event shapes.
+	SchemaVersion int32
+
+	// ShutdownTimeout bounds how long a Run* helper waits for in-flight
+	// RPCs to drain after SIGINT/SIGTERM before forcing the server down.
+	// Zero means the 30s default.
+	ShutdownTimeout time.Duration
+
+	// DisableHealth opts out of the grpc.health.v1.Health service the Run*
+	// helpers register by default.  The health status flips to NOT_SERVING
+	// as graceful shutdown begins, so load balancers stop routing to a
+	// draining server.
+	DisableHealth bool
+
+	// EnableReflection registers the gRPC server reflection service, so
+	// tools like grpcurl can introspect the server.  Off by default.
+	EnableReflection bool
+	// DisablePanicRecovery opts out of the panic-recovery interceptors
+	// the Run* helpers install by default.  With recovery on, a handler
+	// panic fails its RPC with codes.Internal and the stack is logged;
+	// disable it only when a supervisor should see the process die
+	// instead.
+	DisablePanicRecovery bool
+	// MaxConcurrentHandlers bounds how many RPCs the server processes at
+	// once; excess requests wait for a slot until their deadline and then
+	// fail with codes.ResourceExhausted.  This protects slow handlers —
+	// a projector writing files, say — from being overwhelmed during
+	// replays.  Zero means unbounded.
+	MaxConcurrentHandlers int
+	// UnaryInterceptors are chained into the server in order, for
+	// cross-cutting concerns like auth, tracing, and request logging; see
+	// LoggingInterceptor for a ready-made one.
+	UnaryInterceptors []grpc.UnaryServerInterceptor
+	// StreamInterceptors is the streaming counterpart of UnaryInterceptors.
+	StreamInterceptors []grpc.StreamServerInterceptor
+
+	// MetricsRegistry, when set, has the Run* helper build a Metrics
+	// instrument set on it and install it on the router it serves.  Pair
+	// with RunMetricsServer to expose the registry over HTTP.
+	MetricsRegistry prometheus.Registerer
+	// TLS, when set, serves over TLS with the given configuration.  Nil
+	// keeps the default plaintext listener.
+	TLS *tls.Config
+
+	// DeadLetterSink, when set, receives a copy of every event book a
+	// handler failed to process (see DeadLetter), so operators can
+	// inspect and replay instead of reconstructing from log lines.  The
+	// Run* helpers install it on the router or handler they serve.
+	DeadLetterSink DeadLetterSink
+
+	// CommandSender, when set on a saga server, has the server deliver
+	// the commands a trigger emits to the owning aggregates itself,
+	// retrying transient failures per the sender's RetryPolicy, instead
+	// of leaving delivery to the coordinator.  Emitted commands are still
+	// echoed in the SagaResponse for observability.
+	CommandSender *CommandSender
+
+	// EventStore, when set on an aggregate server, has the server load
+	// the aggregate's history itself and persist the events each command
+	// emits, instead of relying on the coordinator to carry prior events
+	// in the ContextualCommand.  A concurrent append that loses the race
+	// surfaces as codes.Aborted, safe to retry.  NewMemoryEventStore
+	// backs integration tests; real deployments supply their own.
+	EventStore EventStore
+
+	// ProcessStateStore, when set on a process manager server, persists
+	// the events a PM handler returns as the process's own state stream
+	// and replays them as processState on later triggers for the same
+	// correlation id; see ProcessManagerHandler.WithStateStore.
+	ProcessStateStore PMStateStore
+
+	// FlushOnShutdown lists buffered outputs — FileSinks and the like —
+	// the server flushes after draining in-flight RPCs during graceful
+	// stop, so the last events a projector wrote aren't lost in a buffer.
+	FlushOnShutdown []Flusher
+
+	// SyncWaiter, when set, lets synchronous commands wait for downstream
+	// acknowledgement: a request carrying sync mode SIMPLE or CASCADE in
+	// its metadata blocks on the waiter (bounded by SyncTimeout) before
+	// its BusinessResponse returns.  Requests without a mode — the
+	// default — return immediately as before.
+	SyncWaiter SyncWaiter
+	// SyncTimeout bounds how long a synchronous command waits for
+	// downstream acknowledgement.  Zero means the 5s default.
+	SyncTimeout time.Duration
+
+	// StateStore, when set together with Snapshotter, enables snapshot
+	// writing for this server.
+	StateStore StateStore
+	// Snapshotter materializes a snapshot from an aggregate's events.
+	Snapshotter Snapshotter
+	// SnapshotOnShutdown bounds how many recently-active aggregates are
+	// snapshotted during graceful shutdown; 0 disables the bound (all
+	// tracked aggregates are snapshotted when a store is configured).
+	SnapshotOnShutdown int
+	// SnapshotMaxAge excludes aggregates not touched within this window
+	// from shutdown snapshotting; 0 disables the age bound.
+	SnapshotMaxAge time.Duration
+}
+
+// Config is shorthand for the common two-field ServerConfig, for services
+// that need nothing beyond a domain (or saga/projector name) and a port:
+//
+//	angzarr.RunAggregateServer(angzarr.Config("player", "50201"), router)
+//
+// The PORT environment variable, when set, takes precedence over the
+// port given here; see ServerConfig.DefaultPort.
+func Config(domain, defaultPort string) ServerConfig {
+	return ServerConfig{Domain: domain, DefaultPort: defaultPort}
+}
+
+// listenPort returns the port to bind: the PORT environment variable when
+// set, otherwise the configured default.
+func (c ServerConfig) listenPort() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return port
+	}
+	return c.DefaultPort
+}
+
+// syncTimeout returns the configured sync-wait bound or the default.
+func (c ServerConfig) syncTimeout() time.Duration {
+	if c.SyncTimeout > 0 {
+		return c.SyncTimeout
+	}
+	return defaultSyncTimeout
+}
+
+// shutdownTimeout returns the configured drain bound or the default.
+func (c ServerConfig) shutdownTimeout() time.Duration {
+	if c.ShutdownTimeout > 0 {
+		return c.ShutdownTimeout
+	}
+	return defaultShutdownTimeout
+}
+
+// newGRPCServer builds the gRPC server for a Run* helper: TLS credentials
+// when configured, the standard health service (unless disabled) reporting
+// SERVING, and the reflection service when enabled.  The returned health
+// server is nil when health is disabled.
+func newGRPCServer(cfg ServerConfig) (*grpc.Server, *health.Server) {
+	var opts []grpc.ServerOption
+	if cfg.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(cfg.TLS)))
+	}
+	unary := cfg.UnaryInterceptors
+	if cfg.MaxConcurrentHandlers > 0 {
+		unary = append([]grpc.UnaryServerInterceptor{
+			ConcurrencyLimitInterceptor(cfg.MaxConcurrentHandlers)}, unary...)
+	}
+	stream := cfg.StreamInterceptors
+	if !cfg.DisablePanicRecovery {
+		// Recovery goes outermost so it also catches panics in the
+		// other interceptors.
+		logger, err := zap.NewProduction()
+		if err != nil {
+			logger = zap.NewNop()
+		}
+		unary = append([]grpc.UnaryServerInterceptor{PanicRecoveryInterceptor(logger)}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{PanicRecoveryStreamInterceptor(logger)}, stream...)
+	}
+	if len(unary) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(unary...))
+	}
+	if len(stream) > 0 {
+		opts = append(opts, grpc.ChainStreamInterceptor(stream...))
+	}
+	grpcServer := grpc.NewServer(opts...)
+	if cfg.EnableReflection {
+		reflection.Register(grpcServer)
+	}
+	if cfg.DisableHealth {
+		return grpcServer, nil
+	}
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthSrv)
+	return grpcServer, healthSrv
+}
+
+// LogServerStart emits the standardized startup line shared by every
+// Run* helper: component kind, registered name, bound port, and the
+// subscribed domain/Type pairs when the component has any.  One field
+// vocabulary — component, name, port, subscribes — keeps logs from the
+// whole deployment aggregatable under a single query instead of each
+// service picking its own names.
+func LogServerStart(logger *zap.Logger, component, name, port string, subscribes []string) {
+	fields := []zap.Field{
+		zap.String("component", component),
+		zap.String("name", name),
+		zap.String("port", port),
+	}
+	if len(subscribes) > 0 {
+		fields = append(fields, zap.Strings("subscribes", subscribes))
+	}
+	logger.Info("server started", fields...)
+}
+
+// serveGracefully serves grpcServer on lis until a serve error or
+// SIGINT/SIGTERM.  On a signal it flips the health service to NOT_SERVING
+// so load balancers stop routing here, drains in-flight RPCs with
+// GracefulStop bounded by the config's ShutdownTimeout (a hung drain falls
+// back to a hard Stop), runs onDrain if given, and returns nil.  Every
+// Run* helper funnels through here so they all shut down the same way.
+func serveGracefully(cfg ServerConfig, grpcServer *grpc.Server, healthSrv *health.Server, lis net.Listener, logger *zap.Logger, onDrain func()) error {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- grpcServer.Serve(lis) }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case sig := <-stop:
+		logger.Info("shutting down", zap.String("signal", sig.String()))
+		if healthSrv != nil {
+			healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+		drained := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+		case <-time.After(cfg.shutdownTimeout()):
+			logger.Warn("drain timeout exceeded, stopping hard",
+				zap.Duration("timeout", cfg.shutdownTimeout()))
+			grpcServer.Stop()
+		}
+		if onDrain != nil {
+			onDrain()
+		}
+		for _, flusher := range cfg.FlushOnShutdown {
+			if err := flusher.Flush(); err != nil {
+				logger.Error("flushing sink during shutdown", zap.Error(err))
+			}
+		}
+		return nil
+	}
+}
+
+// businessLogicServer adapts a CommandRouter to the BusinessLogic gRPC
+// service, tracking aggregate activity for shutdown snapshotting.
+type businessLogicServer struct {
+	router      *CommandRouter
+	tracker     *activityTracker
+	waiter      SyncWaiter
+	syncTimeout time.Duration
+	exec        *KeyedExecutor
+	store       EventStore
+}
+
+func (s *businessLogicServer) Handle(ctx context.Context, cc *pb.ContextualCommand) (*pb.EventBook, error) {
+	var events *pb.EventBook
+	err := s.exec.Do(coverKey(cc.GetCommand().GetCover()), func() error {
+		var err error
+		if s.store != nil {
+			events, err = s.handleStored(ctx, cc)
+		} else {
+			events, err = s.router.Handle(ctx, cc)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.tracker != nil {
+		s.tracker.touch(mergeBooks(cc.GetEvents(), events))
+	}
+	return events, nil
+}
+
+// handleStored dispatches against history loaded from the configured
+// EventStore, ignoring any prior events the request carries, and persists
+// what the handler emits.  The keyed executor already serializes commands
+// per aggregate within this process, so a sequence conflict here means
+// another process appended concurrently; it surfaces as codes.Aborted for
+// the caller to retry.
+func (s *businessLogicServer) handleStored(ctx context.Context, cc *pb.ContextualCommand) (*pb.EventBook, error) {
+	cover := cc.GetCommand().GetCover()
+	prior, err := s.store.Load(cover.GetDomain(), cover.GetRoot().GetValue())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "loading %s history: %v", cover.GetDomain(), err)
+	}
+	events, err := s.router.Handle(ctx, &pb.ContextualCommand{Command: cc.GetCommand(), Events: prior})
+	if err != nil {
+		return nil, err
+	}
+	if len(events.GetPages()) == 0 {
+		return events, nil
+	}
+	if err := s.store.Append(cover.GetDomain(), cover.GetRoot().GetValue(), events, NextSequence(prior)); err != nil {
+		if errors.Is(err, ErrSequenceConflict) {
+			return nil, status.Errorf(codes.Aborted, "persisting %s events: %v", cover.GetDomain(), err)
+		}
+		return nil, status.Errorf(codes.Internal, "persisting %s events: %v", cover.GetDomain(), err)
+	}
+	return events, nil
+}
+
+// HandleSync dispatches the command and, when the request's metadata
+// carries sync mode SIMPLE or CASCADE and a SyncWaiter is configured,
+// blocks until downstream consumers acknowledge the emitted events.  The
+// events are durable once dispatch succeeds, so a wait failure does not
+// undo the command: it surfaces as codes.DeadlineExceeded (on timeout)
+// or the waiter's error, meaning "committed, but read models may lag".
+func (s *businessLogicServer) HandleSync(ctx context.Context, cc *pb.ContextualCommand) (*pb.BusinessResponse, error) {
+	events, err := s.Handle(ctx, cc)
+	if err != nil {
+		return nil, err
+	}
+	if mode := SyncModeFromContext(ctx); mode != SyncModeAsync && s.waiter != nil {
+		timeout := s.syncTimeout
+		if timeout == 0 {
+			timeout = defaultSyncTimeout
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		if err := s.waiter.Wait(waitCtx, mode, events); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.BusinessResponse{Events: events}, nil
+}
+
+// Speculate runs the command through the same router dispatch as Handle
+// but leaves no trace: the activity tracker never sees the result, so the
+// events are never snapshotted or persisted, and the returned cover is
+// stripped to domain and root — no correlation id — so a preview cannot
+// be mistaken for (or correlated with) a real emission.
+func (s *businessLogicServer) Speculate(ctx context.Context, cc *pb.ContextualCommand) (*pb.EventBook, error) {
+	events, err := s.router.Handle(ctx, cc)
+	if err != nil {
+		return nil, err
+	}
+	if events.GetCover() != nil {
+		events.Cover = &pb.Cover{
+			Domain: events.GetCover().GetDomain(),
+			Root:   events.GetCover().GetRoot(),
+		}
+	}
+	return events, nil
+}
+
+// HandleStream receives a command whose prior history arrives as chunks,
+// folding each page into the router's streaming rebuild as it lands so
+// the full book is never materialized, then dispatches the command
+// against the folded state.  It requires WithStreamingRebuild on the
+// router.  The streamed history never passes through the activity
+// tracker — shutdown snapshotting needs the full stream, which this path
+// deliberately never holds.
+func (s *businessLogicServer) HandleStream(stream pb.BusinessLogic_HandleStreamServer) error {
+	rebuild := s.router.newStreamingRebuild()
+	if rebuild == nil {
+		return status.Errorf(codes.FailedPrecondition,
+			"aggregate %q has no streaming rebuild registered", s.router.Domain())
+	}
+	var cmdBook *pb.CommandBook
+	next := uint32(0)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.GetCommand() != nil {
+			cmdBook = chunk.GetCommand()
+		}
+		if snap := chunk.GetSnapshot(); snap != nil {
+			if err := rebuild.Restore(snap); err != nil {
+				return err
+			}
+			next = snap.GetSequence() + 1
+		}
+		for _, page := range chunk.GetPages() {
+			page, err := s.router.upcastPage(page)
+			if err != nil {
+				return err
+			}
+			if err := rebuild.Apply(page); err != nil {
+				return err
+			}
+			if num, ok := pageNum(page); ok && num >= next {
+				next = num + 1
+			}
+		}
+	}
+	if cmdBook == nil {
+		return status.Error(codes.InvalidArgument, "stream carried no command book")
+	}
+	var events *pb.EventBook
+	err := s.exec.Do(coverKey(cmdBook.GetCover()), func() error {
+		var err error
+		events, err = s.router.HandleStreamed(stream.Context(), cmdBook, rebuild, next)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(events)
+}
+
+// mergeBooks appends the emitted pages to the prior book so the activity
+// tracker holds the aggregate's full current stream.
+func mergeBooks(prior, emitted *pb.EventBook) *pb.EventBook {
+	if prior == nil {
+		return emitted
+	}
+	return &pb.EventBook{
+		Cover:    prior.GetCover(),
+		Snapshot: prior.GetSnapshot(),
+		Pages:    append(append([]*pb.EventPage{}, prior.GetPages()...), emitted.GetPages()...),
+	}
+}
+
+// RunAggregateServerPorts serves the router with just a domain and port.
+//
+// Deprecated: use RunAggregateServer(Config(domain, defaultPort), router),
+// which exposes the full ServerConfig surface through one entry point.
+func RunAggregateServerPorts(domain, defaultPort string, router *CommandRouter) error {
+	return RunAggregateServer(Config(domain, defaultPort), router)
+}
+
+// RunAggregateServerConfig is a former name for RunAggregateServer.
+//
+// Deprecated: call RunAggregateServer directly; it now takes the config.
+func RunAggregateServerConfig(cfg ServerConfig, router *CommandRouter) error {
+	return RunAggregateServer(cfg, router)
+}
+
+// RunAggregateServer serves the given command router as a BusinessLogic
+// gRPC service, blocking until the process is signalled to stop: schema
+// version stamping, and snapshot-on-shutdown when a StateStore and
+// Snapshotter are supplied.  On SIGINT/SIGTERM the server drains in-flight
+// commands (bounded by ShutdownTimeout), snapshots the most recently
+// active aggregates (bounded by SnapshotOnShutdown and SnapshotMaxAge),
+// and returns.
+func RunAggregateServer(cfg ServerConfig, router *CommandRouter) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	if cfg.SchemaVersion != 0 {
+		router.WithEventSchemaVersion(cfg.SchemaVersion)
+	}
+	if cfg.MetricsRegistry != nil {
+		router.WithMetrics(NewMetrics(cfg.MetricsRegistry))
+	}
+
+	srv := &businessLogicServer{
+		router:      router,
+		waiter:      cfg.SyncWaiter,
+		syncTimeout: cfg.syncTimeout(),
+		exec:        NewKeyedExecutor(),
+		store:       cfg.EventStore,
+	}
+	if cfg.StateStore != nil && cfg.Snapshotter != nil {
+		srv.tracker = newActivityTracker()
+	}
+
+	lis, err := net.Listen("tcp", ":"+cfg.listenPort())
+	if err != nil {
+		return err
+	}
+	grpcServer, healthSrv := newGRPCServer(cfg)
+	pb.RegisterBusinessLogicServer(grpcServer, srv)
+	pb.RegisterDescriptionServer(grpcServer, &descriptionServer{desc: describeAggregate(router)})
+
+	LogServerStart(logger, "aggregate", cfg.Domain, cfg.listenPort(), nil)
+
+	return serveGracefully(cfg, grpcServer, healthSrv, lis, logger, func() {
+		writeShutdownSnapshots(cfg, srv.tracker, logger)
+	})
+}
+
+// sagaServer adapts an EventRouter to the Saga gRPC service.  When a
+// sender is configured the server also delivers the emitted commands,
+// retrying transient downstream failures.
+type sagaServer struct {
+	router *EventRouter
+	sender *CommandSender
+}
+
+// dispatch forwards emitted commands through the configured sender, if
+// any, before they are returned in the SagaResponse.
+func (s *sagaServer) dispatch(ctx context.Context, commands []*pb.CommandBook) error {
+	if s.sender == nil {
+		return nil
+	}
+	return s.sender.SendAll(ctx, commands)
+}
+
+func (s *sagaServer) Prepare(ctx context.Context, source *pb.EventBook) (*pb.Preparation, error) {
+	covers, err := s.router.PrepareCovers(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Preparation{Covers: covers}, nil
+}
+
+func (s *sagaServer) Handle(ctx context.Context, trigger *pb.SagaTrigger) (*pb.SagaResponse, error) {
+	commands, err := s.router.Handle(ctx, trigger.GetSource(), trigger.GetDestinations())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dispatch(ctx, commands); err != nil {
+		return nil, err
+	}
+	return &pb.SagaResponse{Commands: commands}, nil
+}
+
+func (s *sagaServer) Retry(ctx context.Context, req *pb.SagaRetryRequest) (*pb.SagaResponse, error) {
+	commands, err := s.router.Retry(req.GetRejected(), req.GetDestinations())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dispatch(ctx, commands); err != nil {
+		return nil, err
+	}
+	return &pb.SagaResponse{Commands: commands}, nil
+}
+
+func (s *sagaServer) HandleRejection(ctx context.Context, rejection *pb.SagaRejection) (*pb.SagaResponse, error) {
+	commands, err := s.router.HandleRejection(rejection.GetNotification(), rejection.GetSource(), rejection.GetDestinations())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dispatch(ctx, commands); err != nil {
+		return nil, err
+	}
+	return &pb.SagaResponse{Commands: commands}, nil
+}
+
+// RunSagaServerConfig is a former name for RunSagaServer.
+//
+// Deprecated: call RunSagaServer directly; it now takes the config.
+func RunSagaServerConfig(cfg ServerConfig, router *EventRouter) error {
+	return RunSagaServer(cfg, router)
+}
+
+// RunSagaServer serves the given event router as a Saga gRPC service,
+// blocking until the process is signalled to stop.  On SIGINT/SIGTERM the
+// server drains in-flight triggers, bounded by ShutdownTimeout, and
+// returns.
+func RunSagaServer(cfg ServerConfig, router *EventRouter) error {
+	if router.SourceDomain() == "" && len(cfg.Subscriptions) == 0 {
+		return fmt.Errorf("saga %q declares no source domain; call Domain or Subscribes before serving", router.Name())
+	}
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	lis, err := net.Listen("tcp", ":"+cfg.listenPort())
+	if err != nil {
+		return err
+	}
+	if cfg.MetricsRegistry != nil {
+		router.WithMetrics(NewMetrics(cfg.MetricsRegistry))
+	}
+	if cfg.DeadLetterSink != nil {
+		router.WithDeadLetterSink(cfg.DeadLetterSink)
+	}
+	for _, pair := range cfg.subscriptionPairs() {
+		router.Subscribes(pair[0], pair[1])
+	}
+	grpcServer, healthSrv := newGRPCServer(cfg)
+	desc := describeSaga(router)
+	pb.RegisterSagaServer(grpcServer, &sagaServer{router: router, sender: cfg.CommandSender})
+	pb.RegisterDescriptionServer(grpcServer, &descriptionServer{desc: desc})
+
+	LogServerStart(logger, "saga", router.Name(), cfg.listenPort(), desc.GetEvents())
+
+	return serveGracefully(cfg, grpcServer, healthSrv, lis, logger, nil)
+}
+
+// projectorServer adapts a ProjectorHandler to the Projector gRPC service.
+type projectorServer struct {
+	handler *ProjectorHandler
+}
+
+func (s *projectorServer) HandleSync(ctx context.Context, events *pb.EventBook) (*pb.Projections, error) {
+	projections, err := s.handler.HandleSync(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Projections{Projections: projections}, nil
+}
+
+// RunProjectorServerConfig is a former name for RunProjectorServer.
+//
+// Deprecated: call RunProjectorServer directly; it now takes the config.
+func RunProjectorServerConfig(cfg ServerConfig, handler *ProjectorHandler) error {
+	return RunProjectorServer(cfg, handler)
+}
+
+// RunProjectorServer serves the given projector handler as a Projector
+// gRPC service, blocking until the process is signalled to stop.  On
+// SIGINT/SIGTERM the server drains in-flight event books, bounded by
+// ShutdownTimeout, and returns.
+func RunProjectorServer(cfg ServerConfig, handler *ProjectorHandler) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	lis, err := net.Listen("tcp", ":"+cfg.listenPort())
+	if err != nil {
+		return err
+	}
+	if cfg.MetricsRegistry != nil {
+		handler.WithMetrics(NewMetrics(cfg.MetricsRegistry))
+	}
+	if cfg.DeadLetterSink != nil {
+		handler.WithDeadLetterSink(cfg.DeadLetterSink)
+	}
+	grpcServer, healthSrv := newGRPCServer(cfg)
+	desc := describeProjector(handler)
+	pb.RegisterProjectorServer(grpcServer, &projectorServer{handler: handler})
+	pb.RegisterDescriptionServer(grpcServer, &descriptionServer{desc: desc})
+
+	LogServerStart(logger, "projector", handler.Name(), cfg.listenPort(), desc.GetEvents())
+
+	return serveGracefully(cfg, grpcServer, healthSrv, lis, logger, nil)
+}
+
+// processManagerServer adapts a ProcessManagerHandler to the
+// ProcessManager gRPC service.
+type processManagerServer struct {
+	handler *ProcessManagerHandler
+	exec    *KeyedExecutor
+}
+
+func (s *processManagerServer) Prepare(ctx context.Context, trigger *pb.EventBook) (*pb.Preparation, error) {
+	return &pb.Preparation{Covers: s.handler.Prepare(trigger)}, nil
+}
+
+// Handle dispatches the trigger, serialized per source root so triggers
+// for one process apply in order while unrelated processes run
+// concurrently.
+func (s *processManagerServer) Handle(ctx context.Context, trigger *pb.ProcessManagerTrigger) (*pb.ProcessManagerResponse, error) {
+	var commands []*pb.CommandBook
+	var events *pb.EventBook
+	err := s.exec.Do(coverKey(trigger.GetTrigger().GetCover()), func() error {
+		var err error
+		commands, events, err = s.handler.Handle(trigger.GetTrigger(), trigger.GetDestinations(), trigger.GetProcessState())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProcessManagerResponse{Commands: commands, Events: events}, nil
+}
+
+// RunProcessManagerServerConfig is a former name for
+// RunProcessManagerServer.
+//
+// Deprecated: call RunProcessManagerServer directly; it now takes the
+// config.
+func RunProcessManagerServerConfig(cfg ServerConfig, handler *ProcessManagerHandler) error {
+	return RunProcessManagerServer(cfg, handler)
+}
+
+// RunProcessManagerServer serves the given process manager handler as a
+// ProcessManager gRPC service, blocking until the process is signalled to
+// stop.  On SIGINT/SIGTERM the server drains in-flight triggers, bounded
+// by ShutdownTimeout, and returns.
+func RunProcessManagerServer(cfg ServerConfig, handler *ProcessManagerHandler) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	lis, err := net.Listen("tcp", ":"+cfg.listenPort())
+	if err != nil {
+		return err
+	}
+	if cfg.DeadLetterSink != nil {
+		handler.WithDeadLetterSink(cfg.DeadLetterSink)
+	}
+	if cfg.ProcessStateStore != nil {
+		handler.WithStateStore(cfg.ProcessStateStore)
+	}
+	for _, pair := range cfg.subscriptionPairs() {
+		if pair[1] == Wildcard {
+			handler.ListenTo(pair[0])
+		} else {
+			handler.ListenTo(pair[0], pair[1])
+		}
+	}
+	if stop := handler.startReaper(); stop != nil {
+		defer stop()
+	}
+	grpcServer, healthSrv := newGRPCServer(cfg)
+	desc := describeProcessManager(handler)
+	pb.RegisterProcessManagerServer(grpcServer, &processManagerServer{handler: handler, exec: NewKeyedExecutor()})
+	pb.RegisterDescriptionServer(grpcServer, &descriptionServer{desc: desc})
+
+	LogServerStart(logger, "process-manager", handler.Name(), cfg.listenPort(), desc.GetEvents())
+
+	return serveGracefully(cfg, grpcServer, healthSrv, lis, logger, nil)
+}
@@ -0,0 +1,52 @@
+package angzarr
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// splitMix64 is the PRNG behind Shuffle.  It is chosen for being trivial to
+// reimplement identically in other languages, so the Rust and Python sides
+// of a deployment produce the same orderings for the same seed.
+type splitMix64 struct {
+	state uint64
+}
+
+func (r *splitMix64) next() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Shuffle returns a new slice with the items in a deterministic pseudo-
+// random order derived solely from seed.  The algorithm is fixed and part
+// of the cross-language contract:
+//
+//  1. the seed bytes are hashed with SHA-256,
+//  2. the first 8 hash bytes, read big-endian, seed a SplitMix64 PRNG,
+//  3. a Fisher–Yates shuffle runs from the last index down to 1, drawing
+//     j = next() mod (i+1) at each step.
+//
+// The input slice is not modified.  Any implementation in another language
+// following the same three steps yields the identical ordering, which is
+// what makes DeckSeed-keyed deals assertable across the Go, Rust, and
+// Python examples.
+func Shuffle[T any](seed []byte, items []T) []T {
+	out := make([]T, len(items))
+	copy(out, items)
+	sum := sha256.Sum256(seed)
+	rng := &splitMix64{state: binary.BigEndian.Uint64(sum[:8])}
+	for i := len(out) - 1; i >= 1; i-- {
+		j := rng.next() % uint64(i+1)
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// ShuffledDeck is Shuffle specialized by name for card decks, matching the
+// vocabulary of the poker examples: it orders deck by the hand's DeckSeed.
+func ShuffledDeck[T any](seed []byte, deck []T) []T {
+	return Shuffle(seed, deck)
+}
@@ -0,0 +1,51 @@
+package angzarr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func deck() []int {
+	cards := make([]int, 52)
+	for i := range cards {
+		cards[i] = i
+	}
+	return cards
+}
+
+func TestShuffleDeterministic(t *testing.T) {
+	seed := []byte("hand-7f3a")
+	first := Shuffle(seed, deck())
+	second := Shuffle(seed, deck())
+	if !reflect.DeepEqual(first, second) {
+		t.Error("same seed produced different orderings")
+	}
+}
+
+func TestShuffleSeedSensitive(t *testing.T) {
+	if reflect.DeepEqual(Shuffle([]byte("seed-a"), deck()), Shuffle([]byte("seed-b"), deck())) {
+		t.Error("different seeds produced identical orderings")
+	}
+}
+
+func TestShuffleDoesNotMutateInput(t *testing.T) {
+	original := deck()
+	Shuffle([]byte("seed"), original)
+	if !reflect.DeepEqual(original, deck()) {
+		t.Error("input slice was mutated")
+	}
+}
+
+func TestShuffleIsPermutation(t *testing.T) {
+	shuffled := Shuffle([]byte("seed"), deck())
+	seen := make(map[int]bool, len(shuffled))
+	for _, card := range shuffled {
+		if seen[card] {
+			t.Fatalf("card %d duplicated", card)
+		}
+		seen[card] = true
+	}
+	if len(seen) != 52 {
+		t.Fatalf("expected 52 distinct cards, got %d", len(seen))
+	}
+}
@@ -0,0 +1,99 @@
+package angzarr
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// StateStore persists aggregate snapshots so recovery after a restart can
+// skip replaying the full event stream.
+type StateStore interface {
+	// PutSnapshot stores the snapshot for the given aggregate root,
+	// replacing any earlier one.
+	PutSnapshot(domain string, root *pb.UUID, snapshot *pb.Snapshot) error
+}
+
+// Snapshotter materializes a snapshot from an aggregate's current event
+// book (prior events plus those just emitted).
+type Snapshotter func(events *pb.EventBook) (*pb.Snapshot, error)
+
+// activityEntry remembers the most recent full event book seen for one
+// aggregate root and when it was last touched.
+type activityEntry struct {
+	book    *pb.EventBook
+	touched time.Time
+}
+
+// activityTracker records the most-recently-touched aggregates flowing
+// through a server so their state can be snapshotted on shutdown.
+type activityTracker struct {
+	mu      sync.Mutex
+	entries map[string]*activityEntry
+}
+
+func newActivityTracker() *activityTracker {
+	return &activityTracker{entries: make(map[string]*activityEntry)}
+}
+
+// touch records that the aggregate identified by the book's cover was just
+// handled, retaining the merged prior+emitted book for snapshotting.
+func (t *activityTracker) touch(book *pb.EventBook) {
+	if book.GetCover() == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries[coverKey(book.GetCover())] = &activityEntry{book: book, touched: time.Now()}
+}
+
+// recent returns up to limit entries touched within maxAge, most recent
+// first.  A zero limit or maxAge disables the respective bound.
+func (t *activityTracker) recent(limit int, maxAge time.Duration) []*activityEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries := make([]*activityEntry, 0, len(t.entries))
+	cutoff := time.Time{}
+	if maxAge > 0 {
+		cutoff = time.Now().Add(-maxAge)
+	}
+	for _, entry := range t.entries {
+		if entry.touched.After(cutoff) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].touched.After(entries[j].touched)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// writeShutdownSnapshots snapshots the most recently active aggregates into
+// the configured state store, bounded by cfg.SnapshotOnShutdown and
+// cfg.SnapshotMaxAge.  Failures are logged and skipped so one bad aggregate
+// does not block the drain.
+func writeShutdownSnapshots(cfg ServerConfig, tracker *activityTracker, logger *zap.Logger) {
+	if cfg.StateStore == nil || cfg.Snapshotter == nil || tracker == nil {
+		return
+	}
+	for _, entry := range tracker.recent(cfg.SnapshotOnShutdown, cfg.SnapshotMaxAge) {
+		cover := entry.book.GetCover()
+		snapshot, err := cfg.Snapshotter(entry.book)
+		if err != nil {
+			logger.Warn("snapshot on shutdown failed",
+				zap.String("domain", cover.GetDomain()), zap.Error(err))
+			continue
+		}
+		if err := cfg.StateStore.PutSnapshot(cover.GetDomain(), cover.GetRoot(), snapshot); err != nil {
+			logger.Warn("storing shutdown snapshot failed",
+				zap.String("domain", cover.GetDomain()), zap.Error(err))
+		}
+	}
+}
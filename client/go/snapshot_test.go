@@ -0,0 +1,71 @@
+package angzarr
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// recordingStateStore captures PutSnapshot calls for assertions.
+type recordingStateStore struct {
+	puts []string
+}
+
+func (s *recordingStateStore) PutSnapshot(domain string, root *pb.UUID, snapshot *pb.Snapshot) error {
+	s.puts = append(s.puts, domain+"/"+string(root.GetValue()))
+	return nil
+}
+
+func touchedBook(domain string, root []byte) *pb.EventBook {
+	return &pb.EventBook{Cover: &pb.Cover{Domain: domain, Root: &pb.UUID{Value: root}}}
+}
+
+func TestShutdownSnapshotsBoundedByCount(t *testing.T) {
+	tracker := newActivityTracker()
+	tracker.touch(touchedBook("player", []byte("a")))
+	tracker.touch(touchedBook("player", []byte("b")))
+	tracker.touch(touchedBook("player", []byte("c")))
+
+	store := &recordingStateStore{}
+	cfg := ServerConfig{
+		StateStore:         store,
+		SnapshotOnShutdown: 2,
+		Snapshotter: func(events *pb.EventBook) (*pb.Snapshot, error) {
+			return &pb.Snapshot{}, nil
+		},
+	}
+	writeShutdownSnapshots(cfg, tracker, zap.NewNop())
+	if len(store.puts) != 2 {
+		t.Fatalf("expected 2 shutdown snapshots, got %d", len(store.puts))
+	}
+}
+
+func TestShutdownSnapshotsMostRecentFirst(t *testing.T) {
+	tracker := newActivityTracker()
+	tracker.touch(touchedBook("player", []byte("old")))
+	time.Sleep(2 * time.Millisecond)
+	tracker.touch(touchedBook("player", []byte("new")))
+
+	store := &recordingStateStore{}
+	cfg := ServerConfig{
+		StateStore:         store,
+		SnapshotOnShutdown: 1,
+		Snapshotter: func(events *pb.EventBook) (*pb.Snapshot, error) {
+			return &pb.Snapshot{}, nil
+		},
+	}
+	writeShutdownSnapshots(cfg, tracker, zap.NewNop())
+	if len(store.puts) != 1 || store.puts[0] != "player/new" {
+		t.Fatalf("expected most recent aggregate snapshotted, got %v", store.puts)
+	}
+}
+
+func TestShutdownSnapshotsSkippedWithoutStore(t *testing.T) {
+	tracker := newActivityTracker()
+	tracker.touch(touchedBook("player", []byte("a")))
+	// Must not panic with no store/snapshotter configured.
+	writeShutdownSnapshots(ServerConfig{}, tracker, zap.NewNop())
+}
@@ -0,0 +1,50 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// SpeculativeClient previews the outcome of a command without applying
+// it: the aggregate runs its normal dispatch against current state and
+// returns the would-be events, but nothing is persisted.  UIs use this
+// for "what-if" displays — showing a bet's payout or a checkout's total
+// before the user commits.  Build one with NewSpeculativeClient.
+type SpeculativeClient struct {
+	logic pb.BusinessLogicClient
+}
+
+// NewSpeculativeClient creates a speculative client on an established
+// connection.
+func NewSpeculativeClient(conn *grpc.ClientConn) *SpeculativeClient {
+	return &SpeculativeClient{logic: pb.NewBusinessLogicClient(conn)}
+}
+
+// Speculate sends cmd through the aggregate's Speculate RPC and returns
+// the events the command would emit.  The returned cover carries only
+// domain and root; correlation metadata is stripped server-side so the
+// preview cannot leak into downstream tracing.
+func (c *SpeculativeClient) Speculate(ctx context.Context, domain string, root []byte, cmd proto.Message) (*pb.EventBook, error) {
+	payload, err := anypb.New(cmd)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "packing command: %v", err)
+	}
+	return c.logic.Speculate(ctx, &pb.ContextualCommand{
+		Command: &pb.CommandBook{
+			Cover: &pb.Cover{
+				Domain: domain,
+				Root:   &pb.UUID{Value: root},
+			},
+			Pages: []*pb.CommandPage{{
+				Payload: &pb.CommandPage_Command{Command: payload},
+			}},
+		},
+	})
+}
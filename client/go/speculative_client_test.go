@@ -0,0 +1,61 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestSpeculateStripsCoverMetadata(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	srv := &businessLogicServer{router: router}
+
+	cc := commandFor(t, "player", wrapperspb.String("bet"), nil)
+	cc.Command.Cover.CorrelationId = "corr-1"
+
+	events, err := srv.Speculate(context.Background(), cc)
+	if err != nil {
+		t.Fatalf("speculate: %v", err)
+	}
+	if len(events.GetPages()) != 1 {
+		t.Fatalf("got %d pages, want 1", len(events.GetPages()))
+	}
+	cover := events.GetCover()
+	if cover.GetCorrelationId() != "" {
+		t.Errorf("speculative cover kept correlation id %q", cover.GetCorrelationId())
+	}
+	if cover.GetDomain() != "player" {
+		t.Errorf("speculative cover domain = %q, want %q", cover.GetDomain(), "player")
+	}
+}
+
+func TestSpeculateLeavesNoActivityTrace(t *testing.T) {
+	router := NewCommandRouter("player").On("StringValue", echoHandler)
+	tracker := newActivityTracker()
+	srv := &businessLogicServer{router: router, tracker: tracker}
+
+	if _, err := srv.Speculate(context.Background(),
+		commandFor(t, "player", wrapperspb.String("bet"), nil)); err != nil {
+		t.Fatalf("speculate: %v", err)
+	}
+	if got := len(tracker.recent(10, 0)); got != 0 {
+		t.Errorf("speculation touched %d aggregates, want 0", got)
+	}
+}
+
+func TestSpeculateSurfacesRejections(t *testing.T) {
+	router := NewCommandRouter("player").
+		On("StringValue", func(context.Context, *pb.CommandBook, *anypb.Any, any, uint32) (*pb.EventBook, error) {
+			return nil, FailedPrecondition("hand is finished")
+		})
+	srv := &businessLogicServer{router: router}
+
+	if _, err := srv.Speculate(context.Background(),
+		commandFor(t, "player", wrapperspb.String("bet"), nil)); err == nil {
+		t.Fatal("expected rejection from speculation")
+	}
+}
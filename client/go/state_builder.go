@@ -0,0 +1,117 @@
+package angzarr
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// StateBuilder assembles a rebuild function from per-event appliers,
+// replacing the hand-written page loop each domain repeats:
+//
+//	rebuild := angzarr.NewStateBuilder[Ledger]().
+//		On("FundsDeposited", func(l *Ledger, e *examples.FundsDeposited) {
+//			l.Balance += e.Amount
+//		}).
+//		On("FundsWithdrawn", func(l *Ledger, e *examples.FundsWithdrawn) {
+//			l.Balance -= e.Amount
+//		}).
+//		Build()
+//	router.WithRebuild(func(events *pb.EventBook) any { return rebuild(events) })
+//
+// Registration mirrors StateRouter.On: appliers are keyed by the event's
+// short type name and receive the unmarshaled concrete type.  Pages with
+// no registered applier are skipped, matching the default case of the
+// switch statements this replaces.
+type StateBuilder[S any] struct {
+	appliers map[string]func(*S, *anypb.Any) error
+	restore  func(*S, *anypb.Any) error
+}
+
+// NewStateBuilder starts a builder for state type S.  Rebuilds begin from
+// S's zero value unless the book carries a snapshot.
+func NewStateBuilder[S any]() *StateBuilder[S] {
+	return &StateBuilder[S]{appliers: make(map[string]func(*S, *anypb.Any) error)}
+}
+
+// On registers an applier for the event with the given short type name.
+// apply must be a func(*S, *T) with *T a proto.Message; the rebuild
+// unmarshals each matching page's payload into a fresh *T before invoking
+// it.  Registration panics on an applier with any other shape, surfacing
+// wiring bugs at startup rather than at dispatch.
+func (b *StateBuilder[S]) On(name string, apply any) *StateBuilder[S] {
+	b.appliers[name] = typedApplier[S](name, apply)
+	return b
+}
+
+// WithSnapshot registers how a snapshot payload rehydrates the state.
+// restore must be a func(*S, *T) with *T a proto.Message, invoked with
+// the unmarshaled snapshot state before any pages are applied; pages at
+// or below the snapshot's sequence are then skipped.  Books carrying a
+// snapshot when none is registered fall back to replaying every page.
+func (b *StateBuilder[S]) WithSnapshot(restore any) *StateBuilder[S] {
+	b.restore = typedApplier[S]("snapshot", restore)
+	return b
+}
+
+// Build returns the rebuild function.  A payload that fails to unmarshal
+// into its registered type panics — that is a schema mismatch the
+// appliers cannot recover from, not a condition to fold past silently.
+func (b *StateBuilder[S]) Build() func(events *pb.EventBook) *S {
+	return func(events *pb.EventBook) *S {
+		state := new(S)
+		base := uint32(0)
+		snap := events.GetSnapshot()
+		if snap != nil && b.restore != nil {
+			if err := b.restore(state, snap.GetState()); err != nil {
+				panic(fmt.Sprintf("angzarr: restoring snapshot: %v", err))
+			}
+			base = snap.GetSequence() + 1
+		}
+		for _, page := range events.GetPages() {
+			if base > 0 {
+				if num, ok := pageNum(page); ok && num < base {
+					continue
+				}
+			}
+			event := page.GetEvent()
+			if event == nil {
+				continue
+			}
+			apply, ok := b.appliers[typeNameOf(event.GetTypeUrl())]
+			if !ok {
+				continue
+			}
+			if err := apply(state, event); err != nil {
+				panic(fmt.Sprintf("angzarr: %v", err))
+			}
+		}
+		return state
+	}
+}
+
+// typedApplier wraps a func(*S, *T) applier into one accepting the packed
+// payload, validating the signature up front.
+func typedApplier[S any](name string, apply any) func(*S, *anypb.Any) error {
+	v := reflect.ValueOf(apply)
+	t := v.Type()
+	stateType := reflect.TypeOf((*S)(nil))
+	if t.Kind() != reflect.Func || t.NumIn() != 2 || t.NumOut() != 0 ||
+		t.In(0) != stateType ||
+		t.In(1).Kind() != reflect.Pointer || !t.In(1).Implements(protoMessageType) {
+		panic(fmt.Sprintf("angzarr: applier for %q must be func(%s, *T) with T a proto message, got %T", name, stateType, apply))
+	}
+	msgType := t.In(1)
+	return func(state *S, event *anypb.Any) error {
+		msg := reflect.New(msgType.Elem()).Interface().(proto.Message)
+		if err := event.UnmarshalTo(msg); err != nil {
+			return fmt.Errorf("unmarshaling %s: %w", name, err)
+		}
+		v.Call([]reflect.Value{reflect.ValueOf(state), reflect.ValueOf(msg)})
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestStateBuilderFoldsRegisteredEvents(t *testing.T) {
+	rebuild := NewStateBuilder[balance]().
+		On("Int32Value", func(b *balance, delta *wrapperspb.Int32Value) {
+			b.total += delta.Value
+		}).
+		Build()
+
+	state := rebuild(deltaBook(t, 0, 10, 5, 7))
+	if state.total != 22 {
+		t.Errorf("total = %d, want 22", state.total)
+	}
+}
+
+func TestStateBuilderSkipsUnregisteredEvents(t *testing.T) {
+	rebuild := NewStateBuilder[balance]().
+		On("StringValue", func(b *balance, _ *wrapperspb.StringValue) {
+			b.total++
+		}).
+		Build()
+
+	state := rebuild(deltaBook(t, 0, 10, 5))
+	if state.total != 0 {
+		t.Errorf("total = %d, want 0 (Int32Value pages have no applier)", state.total)
+	}
+}
+
+func TestStateBuilderRestoresSnapshot(t *testing.T) {
+	book := deltaBook(t, 3, 50, 7)
+	snapState, err := anypb.New(wrapperspb.Int32(100))
+	if err != nil {
+		t.Fatalf("packing snapshot: %v", err)
+	}
+	book.Snapshot = &pb.Snapshot{Sequence: 3, State: snapState}
+
+	rebuild := NewStateBuilder[balance]().
+		On("Int32Value", func(b *balance, delta *wrapperspb.Int32Value) {
+			b.total += delta.Value
+		}).
+		WithSnapshot(func(b *balance, snap *wrapperspb.Int32Value) {
+			b.total = snap.Value
+		}).
+		Build()
+
+	state := rebuild(book)
+	if state.total != 107 {
+		t.Errorf("total = %d, want 107 (snapshot 100 + delta 7, covered page skipped)", state.total)
+	}
+}
+
+func TestStateBuilderRejectsBadApplierShape(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registration accepted an applier with the wrong shape")
+		}
+	}()
+	NewStateBuilder[balance]().On("Int32Value", func(delta *wrapperspb.Int32Value) {})
+}
@@ -0,0 +1,119 @@
+package angzarr
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// StateCache memoizes rebuilt aggregate state by root, so a command
+// arriving at sequence N+1 starts from the cached state at N and applies
+// only the new pages instead of replaying the whole stream.  rebuild
+// performs a full fold of an event book; apply folds one additional
+// event into the cached state.  A book whose new pages are gapped or out
+// of order relative to the cached position invalidates the entry and
+// falls back to a full rebuild.
+//
+// The cached state is shared across dispatches and advanced in place, so
+// handlers must treat the state they receive as read-only — the same
+// contract rebuilt state already carries.
+type StateCache[S any] struct {
+	mu      sync.Mutex
+	entries map[string]*stateCacheEntry[S]
+	rebuild func(events *pb.EventBook) *S
+	apply   func(state *S, event *anypb.Any) error
+}
+
+// stateCacheEntry pairs cached state with the stream position it
+// reflects, expressed as the next free sequence.
+type stateCacheEntry[S any] struct {
+	next  uint32
+	state *S
+}
+
+// NewStateCache creates a cache around the given full-rebuild and
+// incremental-apply functions.
+func NewStateCache[S any](rebuild func(events *pb.EventBook) *S, apply func(state *S, event *anypb.Any) error) *StateCache[S] {
+	return &StateCache[S]{
+		entries: make(map[string]*stateCacheEntry[S]),
+		rebuild: rebuild,
+		apply:   apply,
+	}
+}
+
+// WithStateCache installs the cache as the router's rebuild function.  It
+// is a package-level function only because Go methods cannot be generic;
+// it reads like WithRebuild:
+//
+//	cache := angzarr.NewStateCache(rebuildLedger, applyLedger)
+//	angzarr.WithStateCache(router, cache)
+func WithStateCache[S any](r *CommandRouter, cache *StateCache[S]) *CommandRouter {
+	return r.WithRebuild(cache.Rebuild)
+}
+
+// Rebuild returns the state for the book's root, reusing the cached fold
+// when the book extends it contiguously.  It satisfies RebuildFunc, so it
+// can be registered directly with WithRebuild.  Books without a root
+// bypass the cache entirely.
+func (c *StateCache[S]) Rebuild(events *pb.EventBook) any {
+	root := events.GetCover().GetRoot().GetValue()
+	if len(root) == 0 {
+		return c.rebuild(events)
+	}
+	key := hex.EncodeToString(root)
+	next := NextSequence(events)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		if entry.next == next {
+			return entry.state
+		}
+		if entry.next < next {
+			if state := c.advance(entry, events, next); state != nil {
+				c.entries[key] = &stateCacheEntry[S]{next: next, state: state}
+				return state
+			}
+		}
+	}
+	state := c.rebuild(events)
+	c.entries[key] = &stateCacheEntry[S]{next: next, state: state}
+	return state
+}
+
+// Invalidate drops the cached state for a root, forcing the next Rebuild
+// to fold the full stream.
+func (c *StateCache[S]) Invalidate(root []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, hex.EncodeToString(root))
+}
+
+// advance applies the pages beyond the entry's position to its state,
+// returning nil when the new pages are not contiguous from the cached
+// position or an apply fails — the caller then rebuilds from scratch.
+func (c *StateCache[S]) advance(entry *stateCacheEntry[S], events *pb.EventBook, next uint32) *S {
+	expected := entry.next
+	for _, page := range events.GetPages() {
+		num, ok := pageNum(page)
+		if !ok || num < entry.next {
+			continue
+		}
+		if num != expected {
+			return nil
+		}
+		if page.GetEvent() != nil {
+			if err := c.apply(entry.state, page.GetEvent()); err != nil {
+				return nil
+			}
+		}
+		expected++
+	}
+	if expected != next {
+		return nil
+	}
+	return entry.state
+}
@@ -0,0 +1,115 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// countingCache builds a balance StateCache that counts full rebuilds and
+// incremental applies.
+func countingCache(rebuilds, applies *int) *StateCache[balance] {
+	return NewStateCache(
+		func(events *pb.EventBook) *balance {
+			*rebuilds++
+			state, _ := RebuildWithSnapshot(events, newBalance, restoreBalance, applyBalance)
+			return state
+		},
+		func(state *balance, event *anypb.Any) error {
+			*applies++
+			return applyBalance(state, event)
+		})
+}
+
+func TestStateCacheMemoizesUnchangedStream(t *testing.T) {
+	var rebuilds, applies int
+	cache := countingCache(&rebuilds, &applies)
+
+	book := deltaBook(t, 0, 10, 5)
+	first := cache.Rebuild(book).(*balance)
+	second := cache.Rebuild(book).(*balance)
+
+	if rebuilds != 1 {
+		t.Errorf("full rebuilds = %d, want 1", rebuilds)
+	}
+	if first != second {
+		t.Error("repeated rebuild did not return the cached state")
+	}
+	if first.total != 15 {
+		t.Errorf("total = %d, want 15", first.total)
+	}
+}
+
+func TestStateCacheAppliesOnlyNewPages(t *testing.T) {
+	var rebuilds, applies int
+	cache := countingCache(&rebuilds, &applies)
+
+	cache.Rebuild(deltaBook(t, 0, 10, 5))
+	state := cache.Rebuild(deltaBook(t, 0, 10, 5, 7)).(*balance)
+
+	if rebuilds != 1 {
+		t.Errorf("full rebuilds = %d, want 1", rebuilds)
+	}
+	if applies != 1 {
+		t.Errorf("incremental applies = %d, want 1", applies)
+	}
+	if state.total != 22 {
+		t.Errorf("total = %d, want 22", state.total)
+	}
+}
+
+func TestStateCacheFallsBackOnGap(t *testing.T) {
+	var rebuilds, applies int
+	cache := countingCache(&rebuilds, &applies)
+
+	cache.Rebuild(deltaBook(t, 0, 10))
+	// Sequence 1 is missing: the cached fold cannot be extended.
+	gapped := deltaBook(t, 0, 10)
+	gapped.Pages = append(gapped.Pages, deltaBook(t, 2, 7).GetPages()...)
+	cache.Rebuild(gapped)
+
+	if rebuilds != 2 {
+		t.Errorf("full rebuilds = %d, want 2", rebuilds)
+	}
+}
+
+func TestStateCacheInvalidateForcesRebuild(t *testing.T) {
+	var rebuilds, applies int
+	cache := countingCache(&rebuilds, &applies)
+
+	book := deltaBook(t, 0, 10, 5)
+	cache.Rebuild(book)
+	cache.Invalidate(testRoot().GetValue())
+	cache.Rebuild(book)
+
+	if rebuilds != 2 {
+		t.Errorf("full rebuilds after invalidation = %d, want 2", rebuilds)
+	}
+}
+
+func TestStateCacheWiresIntoRouter(t *testing.T) {
+	var rebuilds, applies int
+	cache := countingCache(&rebuilds, &applies)
+	router := WithStateCache(NewCommandRouter("transaction"), cache)
+
+	var seen int32
+	router.On("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+		seen = state.(*balance).total
+		return nil, nil
+	})
+
+	prior := deltaBook(t, 0, 10, 5)
+	if _, err := router.Handle(context.Background(), commandFor(t, "transaction", wrapperspb.String("report"), prior)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if seen != 15 {
+		t.Errorf("handler saw total %d, want 15", seen)
+	}
+	if rebuilds != 1 {
+		t.Errorf("full rebuilds = %d, want 1", rebuilds)
+	}
+}
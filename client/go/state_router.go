@@ -0,0 +1,171 @@
+package angzarr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// protoMessageType is the interface type proto messages must satisfy,
+// used to validate typed handler signatures at registration time.
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// StateRouter dispatches events to in-process projection handlers that
+// maintain local read state (maps, counters, caches).  Handlers registered
+// with On receive the concrete event type; the router unmarshals each
+// page's payload before invoking them, so the documented
+// .On("PlayerRegistered", handlePlayerRegistered) pattern works with plain
+// func(*examples.PlayerRegistered) handlers.
+type StateRouter struct {
+	name          string
+	subscriptions map[string][]string
+	handlers      map[string]func(*anypb.Any) error
+	mu            sync.RWMutex
+	state         any
+}
+
+// NewStateRouter creates a state router with the given projector name.
+func NewStateRouter(name string) *StateRouter {
+	return &StateRouter{
+		name:          name,
+		subscriptions: make(map[string][]string),
+		handlers:      make(map[string]func(*anypb.Any) error),
+	}
+}
+
+// Name returns the projector's registered name.
+func (r *StateRouter) Name() string {
+	return r.name
+}
+
+// Subscribes declares the event types consumed from a domain, advertised to
+// the coordinator.  Repeated calls merge.
+func (r *StateRouter) Subscribes(domain string, events ...string) *StateRouter {
+	r.subscriptions[domain] = append(r.subscriptions[domain], events...)
+	return r
+}
+
+// WithState hands the router an initial projection state value to hold on
+// behalf of its handlers.  Dispatch and State() reads are serialized by the
+// router, so handlers that mutate the held value (directly or via captured
+// reference) need no locking of their own, and the state becomes queryable
+// through State and RunStateRouterQueryServer instead of being locked away
+// in handler closures.
+func (r *StateRouter) WithState(initial any) *StateRouter {
+	r.state = initial
+	return r
+}
+
+// State returns the framework-held projection state registered with
+// WithState, safe to call concurrently with Handle.
+func (r *StateRouter) State() any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.state
+}
+
+// On registers a typed handler for the event with the given short type
+// name.  handler must be a func(*T) where *T is a proto.Message; the
+// router unmarshals each matching page's payload into a fresh *T before
+// invoking it.  Registration panics on a handler with any other shape,
+// surfacing wiring bugs at startup rather than at dispatch.
+func (r *StateRouter) On(name string, handler any) *StateRouter {
+	r.handlers[name] = typedAnyHandler(name, handler)
+	return r
+}
+
+// OnRaw registers a handler receiving the raw packed payload for the event
+// with the given short type name, for handlers that route on bytes.
+func (r *StateRouter) OnRaw(name string, handler func(*anypb.Any)) *StateRouter {
+	r.handlers[name] = func(event *anypb.Any) error {
+		handler(event)
+		return nil
+	}
+	return r
+}
+
+// Handle dispatches each page of the book to the handler registered for
+// its event type; pages with no registered handler are skipped.
+func (r *StateRouter) Handle(events *pb.EventBook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, page := range events.GetPages() {
+		event := page.GetEvent()
+		if event == nil {
+			continue
+		}
+		handler, ok := r.handlers[typeNameOf(event.GetTypeUrl())]
+		if !ok {
+			continue
+		}
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stateQueryServer adapts a StateRouter to the StateQuery gRPC service.
+type stateQueryServer struct {
+	router *StateRouter
+}
+
+func (s *stateQueryServer) Get(ctx context.Context, req *pb.StateQueryRequest) (*pb.StateQueryResponse, error) {
+	data, err := json.Marshal(s.router.State())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encoding projection state: %v", err)
+	}
+	return &pb.StateQueryResponse{Json: data}, nil
+}
+
+// RunStateRouterQueryServer serves the router's framework-held state (see
+// WithState) over the StateQuery gRPC read method on addr, turning the
+// projector into a queryable read model.  It blocks until the process is
+// signalled to stop, then drains in-flight queries and returns.
+func RunStateRouterQueryServer(addr string, router *StateRouter) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	grpcServer, healthSrv := newGRPCServer(ServerConfig{})
+	pb.RegisterStateQueryServer(grpcServer, &stateQueryServer{router: router})
+	LogServerStart(logger, "state-query", router.Name(), addr, domainEventPairs(router.subscriptions))
+	return serveGracefully(ServerConfig{}, grpcServer, healthSrv, lis, logger, nil)
+}
+
+// typedAnyHandler wraps a func(*T) handler into one accepting the packed
+// payload, validating the signature up front.
+func typedAnyHandler(name string, handler any) func(*anypb.Any) error {
+	v := reflect.ValueOf(handler)
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 ||
+		t.In(0).Kind() != reflect.Pointer || !t.In(0).Implements(protoMessageType) {
+		panic(fmt.Sprintf("angzarr: handler for %q must be func(*T) with T a proto message, got %T", name, handler))
+	}
+	msgType := t.In(0)
+	return func(event *anypb.Any) error {
+		msg := reflect.New(msgType.Elem()).Interface().(proto.Message)
+		if err := event.UnmarshalTo(msg); err != nil {
+			return fmt.Errorf("unmarshaling %s: %w", name, err)
+		}
+		v.Call([]reflect.Value{reflect.ValueOf(msg)})
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package angzarr
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestStateRouterTypedDispatch(t *testing.T) {
+	var got []string
+	router := NewStateRouter("prj-output").
+		Subscribes("player", "StringValue").
+		On("StringValue", func(event *wrapperspb.StringValue) {
+			got = append(got, event.Value)
+		})
+
+	book := triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.String("alice"))
+	if err := router.Handle(book); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(got) != 1 || got[0] != "alice" {
+		t.Fatalf("typed handler saw %v, want [alice]", got)
+	}
+}
+
+func TestStateRouterSkipsUnregisteredTypes(t *testing.T) {
+	called := false
+	router := NewStateRouter("prj-output").
+		On("StringValue", func(event *wrapperspb.StringValue) { called = true })
+
+	if err := router.Handle(triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.Bool(true))); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if called {
+		t.Error("handler invoked for unregistered event type")
+	}
+}
+
+func TestStateRouterOnRaw(t *testing.T) {
+	var raw *anypb.Any
+	router := NewStateRouter("prj-output").
+		OnRaw("StringValue", func(event *anypb.Any) { raw = event })
+
+	if err := router.Handle(triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.String("x"))); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if raw == nil {
+		t.Fatal("raw handler not invoked")
+	}
+}
+
+func TestStateRouterHeldState(t *testing.T) {
+	playerNames := map[string]string{}
+	router := NewStateRouter("prj-output").WithState(playerNames)
+	router.On("StringValue", func(event *wrapperspb.StringValue) {
+		playerNames[event.Value] = event.Value
+	})
+
+	if err := router.Handle(triggerBook(t, "player", []byte("0123456789abcdef"), 0, wrapperspb.String("alice"))); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	state, ok := router.State().(map[string]string)
+	if !ok {
+		t.Fatalf("State() returned %T, want map[string]string", router.State())
+	}
+	if state["alice"] != "alice" {
+		t.Errorf("handler mutation not visible through State(): %v", state)
+	}
+}
+
+func TestStateRouterRejectsBadHandlerShape(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non-proto handler signature")
+		}
+	}()
+	NewStateRouter("prj-output").On("StringValue", func(s string) {})
+}
@@ -0,0 +1,122 @@
+package angzarr
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// StreamingRebuild folds a streamed prior history into aggregate state
+// one page at a time, so the full event book never needs to be held in
+// memory.  The server feeds it the stream's snapshot (if any) and each
+// arriving page in order; handlers then receive State() as their rebuilt
+// state.
+type StreamingRebuild interface {
+	// Restore rehydrates state from the stream's snapshot.
+	Restore(snapshot *pb.Snapshot) error
+	// Apply folds one event page into the state.
+	Apply(page *pb.EventPage) error
+	// State returns the current state, in the form handlers receive.
+	State() any
+}
+
+// WithStreamingRebuild registers a factory producing one StreamingRebuild
+// per HandleStream call, enabling the client-streaming Handle variant for
+// aggregates whose histories are too large to carry in one
+// ContextualCommand.  The unary Handle path is unaffected and still uses
+// the WithRebuild function.
+func (r *CommandRouter) WithStreamingRebuild(factory func() StreamingRebuild) *CommandRouter {
+	r.streamRebuild = factory
+	return r
+}
+
+// newStreamingRebuild returns a fresh streaming rebuild, or nil when none
+// is registered.
+func (r *CommandRouter) newStreamingRebuild() StreamingRebuild {
+	if r.streamRebuild == nil {
+		return nil
+	}
+	return r.streamRebuild()
+}
+
+// upcastPage rewrites one page's event to its latest schema shape through
+// the router's upcaster, for the streaming path where pages are consumed
+// individually rather than as a book.
+func (r *CommandRouter) upcastPage(page *pb.EventPage) (*pb.EventPage, error) {
+	if r.upcaster == nil || page.GetEvent() == nil {
+		return page, nil
+	}
+	upcast, err := r.upcaster.Apply(page.GetEvent())
+	if err != nil {
+		return nil, err
+	}
+	if upcast == page.GetEvent() {
+		return page, nil
+	}
+	rewritten := *page
+	rewritten.Event = upcast
+	return &rewritten, nil
+}
+
+// HandleStreamed dispatches a command book against state rebuilt by the
+// streaming path, the streaming counterpart of Handle: state was already
+// folded page by page, so each command page sees the current State() and
+// the emitted events are folded back in before the next page dispatches.
+// seq is the next free sequence after the streamed history.
+func (r *CommandRouter) HandleStreamed(ctx context.Context, cmdBook *pb.CommandBook, state StreamingRebuild, seq uint32) (*pb.EventBook, error) {
+	pages := cmdBook.GetPages()
+	if len(pages) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "command book has no pages")
+	}
+	var emitted *pb.EventBook
+	for i, page := range pages {
+		cmd := page.GetCommand()
+		if cmd == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "command page %d has no payload", i)
+		}
+		handler, ok := r.lookup(cmd.GetTypeUrl())
+		if !ok {
+			return nil, status.Errorf(codes.Unimplemented, "unknown command type %q in domain %q",
+				typeNameOf(cmd.GetTypeUrl()), r.domain)
+		}
+		handlerCtx, finishSpan := startDispatchSpan(ctx, r.tracer,
+			r.domain+"/"+typeNameOf(cmd.GetTypeUrl()), cmdBook.GetCover())
+		start := time.Now()
+		events, err := handler(handlerCtx, cmdBook, cmd, state.State(), seq)
+		finishSpan(err)
+		r.metrics.observeCommand(r.domain, typeNameOf(cmd.GetTypeUrl()),
+			len(events.GetPages()), time.Since(start), err)
+		if err != nil {
+			return nil, MapCommandError(err)
+		}
+		if err := r.validateEmitted(events); err != nil {
+			return nil, err
+		}
+		if err := AssertContiguous(events, seq); err != nil {
+			return nil, status.Errorf(codes.Internal,
+				"aggregate %q emitted non-contiguous events: %v", r.domain, err)
+		}
+		for _, emittedPage := range events.GetPages() {
+			if err := state.Apply(emittedPage); err != nil {
+				return nil, err
+			}
+			if num, ok := pageNum(emittedPage); ok && num >= seq {
+				seq = num + 1
+			}
+		}
+		if emitted == nil {
+			emitted = events
+		} else {
+			emitted.Pages = append(emitted.Pages, events.GetPages()...)
+		}
+	}
+	r.stampSchemaVersion(emitted)
+	if emitted != nil {
+		propagateMetadata(emitted.GetCover(), cmdBook.GetCover().GetMetadata())
+	}
+	return emitted, nil
+}
@@ -0,0 +1,150 @@
+package angzarr
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// balanceStream folds streamed Int32Value pages into a balance, the
+// StreamingRebuild counterpart of the rebuild_test fixtures.
+type balanceStream struct {
+	balance balance
+}
+
+func (s *balanceStream) Restore(snapshot *pb.Snapshot) error {
+	restored, err := restoreBalance(snapshot.GetState())
+	if err != nil {
+		return err
+	}
+	s.balance = *restored
+	return nil
+}
+
+func (s *balanceStream) Apply(page *pb.EventPage) error {
+	if page.GetEvent() == nil {
+		return nil
+	}
+	return applyBalance(&s.balance, page.GetEvent())
+}
+
+func (s *balanceStream) State() any {
+	return &s.balance
+}
+
+// fakeHandleStream scripts the server side of a HandleStream call.
+type fakeHandleStream struct {
+	grpc.ServerStream
+	chunks []*pb.HandleChunk
+	result *pb.EventBook
+}
+
+func (f *fakeHandleStream) Context() context.Context { return context.Background() }
+
+func (f *fakeHandleStream) Recv() (*pb.HandleChunk, error) {
+	if len(f.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := f.chunks[0]
+	f.chunks = f.chunks[1:]
+	return chunk, nil
+}
+
+func (f *fakeHandleStream) SendAndClose(book *pb.EventBook) error {
+	f.result = book
+	return nil
+}
+
+// streamCommand packs msg into a single-page command book for domain.
+func streamCommand(t *testing.T, domain string, msg *wrapperspb.StringValue) *pb.CommandBook {
+	t.Helper()
+	cmd, err := anypb.New(msg)
+	if err != nil {
+		t.Fatalf("packing command: %v", err)
+	}
+	return &pb.CommandBook{
+		Cover: &pb.Cover{Domain: domain, Root: testRoot()},
+		Pages: []*pb.CommandPage{{Payload: &pb.CommandPage_Command{Command: cmd}}},
+	}
+}
+
+func TestHandleStreamFoldsChunkedHistory(t *testing.T) {
+	var sawTotal int32
+	var sawSeq uint32
+	router := NewCommandRouter("transaction").
+		WithStreamingRebuild(func() StreamingRebuild { return &balanceStream{} }).
+		On("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+			sawTotal = state.(*balance).total
+			sawSeq = seq
+			// Emit a delta the streaming state understands, so the
+			// fold-back path is exercised too.
+			return deltaBook(t, seq, 8), nil
+		})
+	srv := &businessLogicServer{router: router, exec: NewKeyedExecutor()}
+
+	stream := &fakeHandleStream{chunks: []*pb.HandleChunk{
+		{Command: streamCommand(t, "transaction", wrapperspb.String("report"))},
+		{Pages: deltaBook(t, 0, 10, 5).GetPages()},
+		{Pages: deltaBook(t, 2, 7).GetPages()},
+	}}
+	if err := srv.HandleStream(stream); err != nil {
+		t.Fatalf("handle stream: %v", err)
+	}
+	if sawTotal != 22 {
+		t.Errorf("handler saw total %d, want 22", sawTotal)
+	}
+	if sawSeq != 3 {
+		t.Errorf("handler saw sequence %d, want 3", sawSeq)
+	}
+	if len(stream.result.GetPages()) != 1 {
+		t.Fatalf("result has %d pages, want 1", len(stream.result.GetPages()))
+	}
+}
+
+func TestHandleStreamRestoresSnapshot(t *testing.T) {
+	var sawTotal int32
+	router := NewCommandRouter("transaction").
+		WithStreamingRebuild(func() StreamingRebuild { return &balanceStream{} }).
+		On("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+			sawTotal = state.(*balance).total
+			if seq != 6 {
+				t.Errorf("sequence after snapshot = %d, want 6", seq)
+			}
+			return nil, nil
+		})
+	srv := &businessLogicServer{router: router, exec: NewKeyedExecutor()}
+
+	snap, err := anypb.New(wrapperspb.Int32(100))
+	if err != nil {
+		t.Fatalf("packing snapshot: %v", err)
+	}
+	stream := &fakeHandleStream{chunks: []*pb.HandleChunk{
+		{
+			Command:  streamCommand(t, "transaction", wrapperspb.String("report")),
+			Snapshot: &pb.Snapshot{Sequence: 4, State: snap},
+		},
+		{Pages: deltaBook(t, 5, 7).GetPages()},
+	}}
+	if err := srv.HandleStream(stream); err != nil {
+		t.Fatalf("handle stream: %v", err)
+	}
+	if sawTotal != 107 {
+		t.Errorf("handler saw total %d, want 107", sawTotal)
+	}
+}
+
+func TestHandleStreamRequiresStreamingRebuild(t *testing.T) {
+	srv := &businessLogicServer{router: NewCommandRouter("transaction"), exec: NewKeyedExecutor()}
+	err := srv.HandleStream(&fakeHandleStream{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("code = %v, want FailedPrecondition", status.Code(err))
+	}
+}
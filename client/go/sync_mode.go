@@ -0,0 +1,90 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// SyncMode selects how much downstream processing a synchronous command
+// waits for before the BusinessResponse returns.
+type SyncMode int32
+
+const (
+	// SyncModeAsync returns as soon as the events are accepted; read
+	// models and downstream sagas catch up eventually.  This is the
+	// default.
+	SyncModeAsync SyncMode = iota
+	// SyncModeSimple waits for this domain's registered projectors to
+	// acknowledge the emitted events, so an immediate read-after-write
+	// against the domain's own read models is consistent.
+	SyncModeSimple
+	// SyncModeCascade additionally waits for downstream sagas triggered
+	// by the emitted events to acknowledge, covering cross-domain
+	// read-after-write.
+	SyncModeCascade
+)
+
+// syncModeMetadataKey is the gRPC metadata key carrying a request's sync
+// mode from client to server.
+const syncModeMetadataKey = "angzarr-sync-mode"
+
+// String returns the wire spelling of the mode.
+func (m SyncMode) String() string {
+	switch m {
+	case SyncModeSimple:
+		return "SIMPLE"
+	case SyncModeCascade:
+		return "CASCADE"
+	default:
+		return "ASYNC"
+	}
+}
+
+// ParseSyncMode maps a wire spelling back to its mode.  Unknown values
+// parse as SyncModeAsync — a garbled header degrades to the default
+// behavior rather than failing the command.
+func ParseSyncMode(s string) SyncMode {
+	switch s {
+	case "SIMPLE":
+		return SyncModeSimple
+	case "CASCADE":
+		return SyncModeCascade
+	default:
+		return SyncModeAsync
+	}
+}
+
+// SyncModeFromContext reads the sync mode from a request's incoming gRPC
+// metadata, defaulting to SyncModeAsync when absent.
+func SyncModeFromContext(ctx context.Context) SyncMode {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return SyncModeAsync
+	}
+	values := md.Get(syncModeMetadataKey)
+	if len(values) == 0 {
+		return SyncModeAsync
+	}
+	return ParseSyncMode(values[0])
+}
+
+// withSyncModeMetadata stamps the mode onto a client call's outgoing
+// metadata.  Async is the absence of the header.
+func withSyncModeMetadata(ctx context.Context, mode SyncMode) context.Context {
+	if mode == SyncModeAsync {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, syncModeMetadataKey, mode.String())
+}
+
+// SyncWaiter blocks until the downstream consumers implied by the mode
+// have acknowledged the emitted events: the domain's projectors for
+// SyncModeSimple, projectors plus triggered sagas for SyncModeCascade.
+// The coordinator supplies the implementation; the library only defines
+// when it is awaited.
+type SyncWaiter interface {
+	Wait(ctx context.Context, mode SyncMode, events *pb.EventBook) error
+}
@@ -0,0 +1,87 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestSyncModeRoundTrip(t *testing.T) {
+	for _, mode := range []SyncMode{SyncModeAsync, SyncModeSimple, SyncModeCascade} {
+		if got := ParseSyncMode(mode.String()); got != mode {
+			t.Errorf("ParseSyncMode(%q) = %v, want %v", mode.String(), got, mode)
+		}
+	}
+	if got := ParseSyncMode("garbage"); got != SyncModeAsync {
+		t.Errorf("unknown spelling parsed as %v, want SyncModeAsync", got)
+	}
+}
+
+func TestSyncModeFromContextDefaultsToAsync(t *testing.T) {
+	if got := SyncModeFromContext(context.Background()); got != SyncModeAsync {
+		t.Errorf("mode without metadata = %v, want SyncModeAsync", got)
+	}
+}
+
+// recordingWaiter records the mode it was awaited with.
+type recordingWaiter struct {
+	mode   SyncMode
+	waited int
+	err    error
+}
+
+func (w *recordingWaiter) Wait(_ context.Context, mode SyncMode, _ *pb.EventBook) error {
+	w.mode = mode
+	w.waited++
+	return w.err
+}
+
+// syncCtx builds an incoming-metadata context as the transport would for
+// a request carrying the mode.
+func syncCtx(mode SyncMode) context.Context {
+	return metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(syncModeMetadataKey, mode.String()))
+}
+
+func TestHandleSyncWaitsForConfiguredModes(t *testing.T) {
+	waiter := &recordingWaiter{}
+	srv := &businessLogicServer{
+		router: NewCommandRouter("player").On("StringValue", echoHandler),
+		waiter: waiter,
+	}
+
+	if _, err := srv.HandleSync(syncCtx(SyncModeCascade),
+		commandFor(t, "player", wrapperspb.String("bet"), nil)); err != nil {
+		t.Fatalf("handle sync: %v", err)
+	}
+	if waiter.waited != 1 || waiter.mode != SyncModeCascade {
+		t.Errorf("waited %d times with mode %v, want once with SyncModeCascade",
+			waiter.waited, waiter.mode)
+	}
+
+	// Async requests never touch the waiter.
+	if _, err := srv.HandleSync(context.Background(),
+		commandFor(t, "player", wrapperspb.String("bet"), nil)); err != nil {
+		t.Fatalf("handle sync: %v", err)
+	}
+	if waiter.waited != 1 {
+		t.Errorf("async request awaited the waiter (%d waits)", waiter.waited)
+	}
+}
+
+func TestHandleSyncSurfacesWaitFailure(t *testing.T) {
+	waiter := &recordingWaiter{err: context.DeadlineExceeded}
+	srv := &businessLogicServer{
+		router: NewCommandRouter("player").On("StringValue", echoHandler),
+		waiter: waiter,
+	}
+
+	if _, err := srv.HandleSync(syncCtx(SyncModeSimple),
+		commandFor(t, "player", wrapperspb.String("bet"), nil)); err == nil {
+		t.Fatal("expected wait failure to surface")
+	}
+}
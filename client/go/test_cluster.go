@@ -0,0 +1,304 @@
+package angzarr
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// clusterBufSize is the bufconn listener buffer for in-process servers.
+const clusterBufSize = 1 << 20
+
+// clusterCodec serializes the mirror message types over the cluster's
+// in-process connections.  The hand-written pb mirrors carry no protobuf
+// wire codec, so the cluster uses gob — both ends are the same process
+// and the same types, which is exactly gob's contract.  It is forced on
+// every cluster server and connection and never leaves the test.
+type clusterCodec struct{}
+
+func init() {
+	// The oneof wrapper types ride inside interface fields, which gob
+	// can only decode for registered concrete types.
+	gob.Register(&pb.EventPage_Num{})
+	gob.Register(&pb.EventPage_Force{})
+	gob.Register(&pb.CommandPage_Command{})
+}
+
+func (clusterCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (clusterCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (clusterCodec) Name() string { return "angzarr-cluster-gob" }
+
+// clusterMaxDepth bounds saga cascades so a command loop fails the test
+// instead of hanging it.
+const clusterMaxDepth = 8
+
+// TestCluster wires aggregates, sagas, and projectors into an in-process
+// coordinator for end-to-end tests: each handler is served over a real
+// gRPC server on a bufconn listener, and Submit drives the full chain —
+// command to events, events to projections and saga-emitted commands,
+// those commands back into their aggregates — without Docker or mocks:
+//
+//	cluster := angzarr.NewTestCluster(t).
+//		WithAggregate(tableRouter).
+//		WithAggregate(handRouter).
+//		WithSaga(dealCardsSaga)
+//	defer cluster.Close()
+//
+//	cluster.Submit("table", tableRoot, &examples.JoinTable{...})
+//	cluster.Events("hand", handRoot) // assert downstream effects
+type TestCluster struct {
+	tb         testing.TB
+	aggregates map[string]pb.BusinessLogicClient
+	sagas      []clusterSaga
+	projectors []clusterProjector
+	store      map[string]*pb.EventBook
+	commands   []*pb.CommandBook
+	projected  []*pb.Projection
+	servers    []*grpc.Server
+}
+
+type clusterSaga struct {
+	name   string
+	domain string
+	client pb.SagaClient
+}
+
+type clusterProjector struct {
+	name    string
+	domains map[string]bool
+	client  pb.ProjectorClient
+}
+
+// NewTestCluster creates an empty cluster.  Register services with the
+// With* methods before submitting commands, and Close the cluster when
+// the test ends.
+func NewTestCluster(tb testing.TB) *TestCluster {
+	tb.Helper()
+	return &TestCluster{
+		tb:         tb,
+		aggregates: make(map[string]pb.BusinessLogicClient),
+		store:      make(map[string]*pb.EventBook),
+	}
+}
+
+// WithAggregate serves the command router as a BusinessLogic service on
+// a bufconn listener and registers it under the router's domain.
+func (c *TestCluster) WithAggregate(router *CommandRouter) *TestCluster {
+	c.tb.Helper()
+	conn := c.serve(func(s *grpc.Server) {
+		pb.RegisterBusinessLogicServer(s, &businessLogicServer{router: router, exec: NewKeyedExecutor()})
+	})
+	c.aggregates[router.Domain()] = pb.NewBusinessLogicClient(conn)
+	return c
+}
+
+// WithSaga serves the event router as a Saga service on a bufconn
+// listener, triggered by events from its source domain.
+func (c *TestCluster) WithSaga(router *EventRouter) *TestCluster {
+	c.tb.Helper()
+	conn := c.serve(func(s *grpc.Server) {
+		pb.RegisterSagaServer(s, &sagaServer{router: router})
+	})
+	c.sagas = append(c.sagas, clusterSaga{
+		name:   router.Name(),
+		domain: router.SourceDomain(),
+		client: pb.NewSagaClient(conn),
+	})
+	return c
+}
+
+// WithProjector serves the handler as a Projector service on a bufconn
+// listener, receiving every book emitted in its subscribed domains.
+func (c *TestCluster) WithProjector(handler *ProjectorHandler) *TestCluster {
+	c.tb.Helper()
+	domains := make(map[string]bool, len(handler.Domains()))
+	for _, domain := range handler.Domains() {
+		domains[domain] = true
+	}
+	conn := c.serve(func(s *grpc.Server) {
+		pb.RegisterProjectorServer(s, &projectorServer{handler: handler})
+	})
+	c.projectors = append(c.projectors, clusterProjector{
+		name:    handler.Name(),
+		domains: domains,
+		client:  pb.NewProjectorClient(conn),
+	})
+	return c
+}
+
+// serve starts a gRPC server on a fresh bufconn listener, registers the
+// given services on it, and returns a client connection to it.
+func (c *TestCluster) serve(register func(*grpc.Server)) *grpc.ClientConn {
+	c.tb.Helper()
+	lis := bufconn.Listen(clusterBufSize)
+	server := grpc.NewServer(grpc.ForceServerCodec(clusterCodec{}))
+	register(server)
+	go server.Serve(lis)
+	c.servers = append(c.servers, server)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(clusterCodec{})),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}))
+	if err != nil {
+		c.tb.Fatalf("cluster dial: %v", err)
+	}
+	return conn
+}
+
+// Submit packs cmd into a single-page command book for the aggregate at
+// domain/root, stamped at the stream's next sequence, and drives it
+// through the cluster: the aggregate's events are stored, projectors
+// subscribed to the domain receive them, and triggered sagas' commands
+// are delivered in turn.  It returns the events the first aggregate
+// emitted and fails the test on any error in the chain.
+func (c *TestCluster) Submit(domain string, root []byte, cmd proto.Message) *pb.EventBook {
+	c.tb.Helper()
+	payload, err := anypb.New(cmd)
+	if err != nil {
+		c.tb.Fatalf("packing command: %v", err)
+	}
+	cover := &pb.Cover{Domain: domain, Root: &pb.UUID{Value: root}}
+	book := &pb.CommandBook{
+		Cover: cover,
+		Pages: []*pb.CommandPage{{
+			Sequence: NextSequence(c.store[coverKey(cover)]),
+			Payload:  &pb.CommandPage_Command{Command: payload},
+		}},
+	}
+	events, err := c.deliver(context.Background(), book, 0)
+	if err != nil {
+		c.tb.Fatalf("submitting %s to %s: %v", typeNameOf(payload.GetTypeUrl()), domain, err)
+	}
+	return events
+}
+
+// deliver routes one command book to its aggregate and fans the emitted
+// events out to projectors and sagas, cascading saga-emitted commands.
+func (c *TestCluster) deliver(ctx context.Context, book *pb.CommandBook, depth int) (*pb.EventBook, error) {
+	if depth > clusterMaxDepth {
+		c.tb.Fatalf("saga cascade exceeded %d hops; command loop?", clusterMaxDepth)
+	}
+	domain := book.GetCover().GetDomain()
+	logic, ok := c.aggregates[domain]
+	if !ok {
+		c.tb.Fatalf("no aggregate registered for domain %q", domain)
+	}
+	key := coverKey(book.GetCover())
+	events, err := logic.Handle(ctx, &pb.ContextualCommand{Command: book, Events: c.store[key]})
+	if err != nil {
+		return nil, err
+	}
+	if events.Cover == nil {
+		events.Cover = book.GetCover()
+	}
+	stampClusterTimestamps(events)
+	c.store[key] = mergeBooks(c.store[key], events)
+
+	for _, projector := range c.projectors {
+		if !projector.domains[domain] {
+			continue
+		}
+		resp, err := projector.client.HandleSync(ctx, events)
+		if err != nil {
+			return nil, err
+		}
+		c.projected = append(c.projected, resp.GetProjections()...)
+	}
+	for _, saga := range c.sagas {
+		if saga.domain != domain {
+			continue
+		}
+		prep, err := saga.client.Prepare(ctx, events)
+		if err != nil {
+			return nil, err
+		}
+		var destinations []*pb.EventBook
+		for _, cover := range prep.GetCovers() {
+			if dest := c.store[coverKey(cover)]; dest != nil {
+				destinations = append(destinations, dest)
+			}
+		}
+		resp, err := saga.client.Handle(ctx, &pb.SagaTrigger{Source: events, Destinations: destinations})
+		if err != nil {
+			return nil, err
+		}
+		for _, emitted := range resp.Commands {
+			c.commands = append(c.commands, emitted)
+			if _, err := c.deliver(ctx, emitted, depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return events, nil
+}
+
+// Events returns the stored stream for the aggregate at domain/root, or
+// nil when it has no history.
+func (c *TestCluster) Events(domain string, root []byte) *pb.EventBook {
+	return c.store[coverKey(&pb.Cover{Domain: domain, Root: &pb.UUID{Value: root}})]
+}
+
+// CommandsTo returns every saga-emitted command book addressed to the
+// given domain, in delivery order.
+func (c *TestCluster) CommandsTo(domain string) []*pb.CommandBook {
+	var books []*pb.CommandBook
+	for _, book := range c.commands {
+		if book.GetCover().GetDomain() == domain {
+			books = append(books, book)
+		}
+	}
+	return books
+}
+
+// Projections returns every projection the named projector produced, in
+// delivery order.
+func (c *TestCluster) Projections(projector string) []*pb.Projection {
+	var projections []*pb.Projection
+	for _, projection := range c.projected {
+		if projection.GetProjector() == projector {
+			projections = append(projections, projection)
+		}
+	}
+	return projections
+}
+
+// Close stops every server the cluster started.
+func (c *TestCluster) Close() {
+	for _, server := range c.servers {
+		server.Stop()
+	}
+}
+
+// stampClusterTimestamps fills CreatedAt on emitted pages so stored
+// books satisfy consistency validation in downstream handlers.
+func stampClusterTimestamps(events *pb.EventBook) {
+	for _, page := range events.GetPages() {
+		if page != nil && page.CreatedAt == nil {
+			page.CreatedAt = timestamppb.Now()
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// clusterFixture wires the poker-shaped chain: a table aggregate whose
+// events trigger a saga that deals a command into the hand aggregate,
+// with a projector watching the table.  StringValue stands in for
+// JoinTable/HandStarted, Int32Value for DealCards.
+func clusterFixture(t *testing.T) *TestCluster {
+	t.Helper()
+	table := NewCommandRouter("table").On("StringValue", echoHandler)
+	hand := NewCommandRouter("hand").On("Int32Value", echoHandler)
+
+	saga := NewEventRouter("saga-table-hand").Domain("table")
+	OnTyped(saga, "StringValue",
+		func(ctx context.Context, source *pb.EventBook, event *wrapperspb.StringValue, destinations []*pb.EventBook) ([]*pb.CommandBook, error) {
+			deal, err := NewCommand("hand", []byte(event.Value)).Payload(wrapperspb.Int32(2))
+			if err != nil {
+				return nil, err
+			}
+			return []*pb.CommandBook{deal}, nil
+		})
+
+	projector := NewProjectorHandler("prj-table", "table").
+		WithHandle(func(ctx context.Context, events *pb.EventBook) (*pb.Projection, error) {
+			return &pb.Projection{
+				Cover:     events.GetCover(),
+				Projector: "prj-table",
+				Sequence:  LastSequence(events),
+			}, nil
+		})
+
+	return NewTestCluster(t).
+		WithAggregate(table).
+		WithAggregate(hand).
+		WithSaga(saga).
+		WithProjector(projector)
+}
+
+func TestClusterDrivesCommandThroughSagaToDownstreamAggregate(t *testing.T) {
+	cluster := clusterFixture(t)
+	defer cluster.Close()
+
+	handRoot := []byte("handroot00000000")
+	events := cluster.Submit("table", []byte("tableroot0000000"), wrapperspb.String(string(handRoot)))
+	if len(events.GetPages()) != 1 {
+		t.Fatalf("table emitted %d pages, want 1", len(events.GetPages()))
+	}
+
+	if commands := cluster.CommandsTo("hand"); len(commands) != 1 {
+		t.Fatalf("saga emitted %d commands to hand, want 1", len(commands))
+	}
+	handEvents := cluster.Events("hand", handRoot)
+	if len(handEvents.GetPages()) != 1 {
+		t.Fatalf("hand stream has %d pages, want 1", len(handEvents.GetPages()))
+	}
+	if got := typeNameOf(handEvents.GetPages()[0].GetEvent().GetTypeUrl()); got != "Int32Value" {
+		t.Errorf("hand event type = %q, want Int32Value", got)
+	}
+}
+
+func TestClusterFansEventsOutToProjectors(t *testing.T) {
+	cluster := clusterFixture(t)
+	defer cluster.Close()
+
+	cluster.Submit("table", []byte("tableroot0000000"), wrapperspb.String("handroot00000000"))
+	projections := cluster.Projections("prj-table")
+	if len(projections) != 1 {
+		t.Fatalf("got %d projections, want 1", len(projections))
+	}
+	if projections[0].GetCover().GetDomain() != "table" {
+		t.Errorf("projection domain = %q", projections[0].GetCover().GetDomain())
+	}
+}
+
+func TestClusterAccumulatesStreamsAcrossSubmits(t *testing.T) {
+	cluster := clusterFixture(t)
+	defer cluster.Close()
+
+	tableRoot := []byte("tableroot0000000")
+	cluster.Submit("table", tableRoot, wrapperspb.String("handroot00000001"))
+	cluster.Submit("table", tableRoot, wrapperspb.String("handroot00000002"))
+
+	stream := cluster.Events("table", tableRoot)
+	if len(stream.GetPages()) != 2 {
+		t.Fatalf("table stream has %d pages, want 2", len(stream.GetPages()))
+	}
+	if got := PageSequence(stream.GetPages()[1]); got != 1 {
+		t.Errorf("second event sequence = %d, want 1", got)
+	}
+}
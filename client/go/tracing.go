@@ -0,0 +1,72 @@
+package angzarr
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// tracerName scopes the spans this library emits.
+const tracerName = "github.com/benjaminabbitt/angzarr/client/go"
+
+// WithTracing enables OpenTelemetry spans around command dispatch.  Each
+// handled command becomes a span named "domain/CommandType" carrying the
+// cover's correlation identifier as an attribute.
+func (r *CommandRouter) WithTracing(tp trace.TracerProvider) *CommandRouter {
+	r.tracer = tp.Tracer(tracerName)
+	return r
+}
+
+// WithTracing enables OpenTelemetry spans around event dispatch.  Each
+// handled trigger becomes a span named "name/EventType", and the span's
+// trace context is injected into every emitted CommandBook's metadata so
+// downstream aggregates continue the trace.
+func (r *EventRouter) WithTracing(tp trace.TracerProvider) *EventRouter {
+	r.tracer = tp.Tracer(tracerName)
+	return r
+}
+
+// startDispatchSpan opens a span for one handler invocation under the
+// inbound context, attaching the correlation identifier when present.
+// The returned finish function records the dispatch error and ends the
+// span.
+func startDispatchSpan(ctx context.Context, tracer trace.Tracer, name string, cover *pb.Cover) (context.Context, func(error)) {
+	if tracer == nil {
+		return ctx, func(error) {}
+	}
+	ctx, span := tracer.Start(ctx, name)
+	if correlation := cover.GetCorrelationId(); correlation != "" {
+		span.SetAttributes(attribute.String("angzarr.correlation_id", correlation))
+	}
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// injectTraceContext stamps the span context from ctx into each command
+// book's metadata in W3C trace-context form, linking the commands a saga
+// emits to the trigger's span.
+func injectTraceContext(ctx context.Context, books []*pb.CommandBook) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	propagator := propagation.TraceContext{}
+	for _, book := range books {
+		if book == nil {
+			continue
+		}
+		if book.Metadata == nil {
+			book.Metadata = make(map[string]string)
+		}
+		propagator.Inject(ctx, propagation.MapCarrier(book.Metadata))
+	}
+}
@@ -0,0 +1,43 @@
+package angzarr
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// TypedCommandRouter fixes a CommandRouter's state type to S, so the
+// functional registration path gets the same typed state the OO
+// aggregates enjoy instead of asserting out of `any` in every handler.
+// The embedded CommandRouter is the real router — Run* helpers, metrics,
+// and the untyped On all keep working on it.
+type TypedCommandRouter[S any] struct {
+	*CommandRouter
+}
+
+// NewTypedCommandRouter creates a command router for the domain whose
+// rebuild function and handlers share the concrete state type S:
+//
+//	router := angzarr.NewTypedCommandRouter("player", rebuildPlayer).
+//		OnState("RegisterPlayer", handleRegister)
+//
+// rebuild folds prior events into *S; a stream with no history receives
+// whatever rebuild returns for it, typically a zero-valued state.
+func NewTypedCommandRouter[S any](domain string, rebuild func(events *pb.EventBook) *S) *TypedCommandRouter[S] {
+	router := NewCommandRouter(domain).
+		WithRebuild(func(events *pb.EventBook) any { return rebuild(events) })
+	return &TypedCommandRouter[S]{CommandRouter: router}
+}
+
+// OnState registers a handler receiving the rebuilt state as *S.  It is
+// the typed counterpart of On; the command payload stays packed, for
+// which combine with OnStaged on the embedded router.
+func (r *TypedCommandRouter[S]) OnState(name string, handler func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state *S, seq uint32) (*pb.EventBook, error)) *TypedCommandRouter[S] {
+	r.On(name, func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state any, seq uint32) (*pb.EventBook, error) {
+		typed, _ := state.(*S)
+		return handler(ctx, cmdBook, cmd, typed, seq)
+	})
+	return r
+}
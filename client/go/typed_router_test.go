@@ -0,0 +1,51 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestTypedCommandRouterHandlerSeesTypedState(t *testing.T) {
+	var seen int32
+	router := NewTypedCommandRouter("transaction", func(events *pb.EventBook) *balance {
+		state := &balance{}
+		for _, page := range events.GetPages() {
+			if err := applyBalance(state, page.GetEvent()); err != nil {
+				return state
+			}
+		}
+		return state
+	}).OnState("StringValue", func(ctx context.Context, cmdBook *pb.CommandBook, cmd *anypb.Any, state *balance, seq uint32) (*pb.EventBook, error) {
+		seen = state.total
+		return echoHandler(ctx, cmdBook, cmd, state, seq)
+	})
+
+	cc := commandFor(t, "transaction", wrapperspb.String("withdraw"), deltaBook(t, 0, 10, 5))
+	if _, err := router.Handle(context.Background(), cc); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if seen != 15 {
+		t.Errorf("handler saw state total %d, want 15", seen)
+	}
+}
+
+func TestTypedCommandRouterEmbedsPlainRouter(t *testing.T) {
+	router := NewTypedCommandRouter("player", func(*pb.EventBook) *balance { return &balance{} })
+	if router.Domain() != "player" {
+		t.Errorf("domain = %q, want player", router.Domain())
+	}
+	// Untyped registration still works through the embedded router.
+	router.On("BoolValue", echoHandler)
+	events, err := router.Handle(context.Background(), commandFor(t, "player", wrapperspb.Bool(true), nil))
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(events.GetPages()) != 1 {
+		t.Errorf("emitted %d pages, want 1", len(events.GetPages()))
+	}
+}
@@ -0,0 +1,112 @@
+package angzarr
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// UpcastFunc rewrites a packed event from an old schema shape to a newer
+// one, returning the re-packed event under its new type URL.
+type UpcastFunc func(event *anypb.Any) (*anypb.Any, error)
+
+// maxUpcastDepth bounds upcast chains, so a registration cycle fails
+// loudly instead of spinning.
+const maxUpcastDepth = 16
+
+// Upcaster rewrites old event shapes to the latest before handlers or
+// rebuild functions see them, so long-lived aggregates can migrate event
+// formats without every handler growing version switches.  Versions are
+// conventionally encoded in the type URL's package segment —
+// events.v1.TransactionCreated, events.v2.TransactionCreated — and each
+// registration maps one version to the next; chains compose, so a v1
+// event registered v1→v2 and v2→v3 arrives as v3.  Register with On and
+// install on a router with WithUpcaster.
+type Upcaster struct {
+	handlers map[string]UpcastFunc
+}
+
+// NewUpcaster creates an empty upcaster.
+func NewUpcaster() *Upcaster {
+	return &Upcaster{handlers: make(map[string]UpcastFunc)}
+}
+
+// On registers an upcast for events of the given type.  As with
+// CommandRouter.On, a short type name matches any URL whose final
+// segment equals it, while a full type URL (containing '/') matches
+// exactly; exact registrations win, which matters when several versioned
+// packages share a short name.
+func (u *Upcaster) On(oldType string, fn UpcastFunc) *Upcaster {
+	u.handlers[oldType] = fn
+	return u
+}
+
+// lookup finds the upcast for a type URL, exact URL first.
+func (u *Upcaster) lookup(typeURL string) (UpcastFunc, bool) {
+	if fn, ok := u.handlers[typeURL]; ok {
+		return fn, true
+	}
+	fn, ok := u.handlers[typeNameOf(typeURL)]
+	return fn, ok
+}
+
+// Apply upcasts one packed event to its latest shape, following the
+// registration chain until no upcast matches.  Events with no registered
+// upcast pass through untouched.
+func (u *Upcaster) Apply(event *anypb.Any) (*anypb.Any, error) {
+	if event == nil {
+		return nil, nil
+	}
+	for depth := 0; depth < maxUpcastDepth; depth++ {
+		fn, ok := u.lookup(event.GetTypeUrl())
+		if !ok {
+			return event, nil
+		}
+		upcast, err := fn(event)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal,
+				"upcasting %q: %v", typeNameOf(event.GetTypeUrl()), err)
+		}
+		event = upcast
+	}
+	return nil, status.Errorf(codes.Internal,
+		"upcast chain for %q exceeds %d steps; registration cycle?",
+		typeNameOf(event.GetTypeUrl()), maxUpcastDepth)
+}
+
+// ApplyBook returns the book with every page's event upcast to its
+// latest shape.  Books needing no rewrites are returned as-is.
+func (u *Upcaster) ApplyBook(book *pb.EventBook) (*pb.EventBook, error) {
+	if book == nil {
+		return nil, nil
+	}
+	var pages []*pb.EventPage
+	for i, page := range book.GetPages() {
+		upcast, err := u.Apply(page.GetEvent())
+		if err != nil {
+			return nil, err
+		}
+		if upcast == page.GetEvent() {
+			if pages != nil {
+				pages = append(pages, page)
+			}
+			continue
+		}
+		if pages == nil {
+			pages = append(pages, book.GetPages()[:i]...)
+		}
+		rewritten := *page
+		rewritten.Event = upcast
+		pages = append(pages, &rewritten)
+	}
+	if pages == nil {
+		return book, nil
+	}
+	return &pb.EventBook{
+		Cover:    book.GetCover(),
+		Snapshot: book.GetSnapshot(),
+		Pages:    pages,
+	}, nil
+}
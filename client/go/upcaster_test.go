@@ -0,0 +1,91 @@
+package angzarr
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// repackString packs a StringValue with the given payload, standing in
+// for an event re-shaped under a newer type.
+func repackString(t *testing.T, value string) *anypb.Any {
+	t.Helper()
+	packed, err := anypb.New(wrapperspb.String(value))
+	if err != nil {
+		t.Fatalf("packing: %v", err)
+	}
+	return packed
+}
+
+func TestUpcasterChains(t *testing.T) {
+	// Int32Value stands in for v1, BoolValue for v2, StringValue for v3.
+	upcaster := NewUpcaster().
+		On("Int32Value", func(event *anypb.Any) (*anypb.Any, error) {
+			return anypb.New(wrapperspb.Bool(true))
+		}).
+		On("BoolValue", func(event *anypb.Any) (*anypb.Any, error) {
+			return anypb.New(wrapperspb.String("v3"))
+		})
+
+	v1, err := anypb.New(wrapperspb.Int32(1))
+	if err != nil {
+		t.Fatalf("packing: %v", err)
+	}
+	latest, err := upcaster.Apply(v1)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := typeNameOf(latest.GetTypeUrl()); got != "StringValue" {
+		t.Errorf("chained upcast landed on %q, want StringValue", got)
+	}
+}
+
+func TestUpcasterDetectsCycles(t *testing.T) {
+	upcaster := NewUpcaster().
+		On("StringValue", func(event *anypb.Any) (*anypb.Any, error) {
+			return event, nil
+		})
+	if _, err := upcaster.Apply(repackString(t, "loop")); err == nil {
+		t.Fatal("expected cycle detection to fail the upcast")
+	}
+}
+
+func TestUpcasterPassesUnregisteredThrough(t *testing.T) {
+	event := repackString(t, "unchanged")
+	got, err := NewUpcaster().Apply(event)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got != event {
+		t.Error("unregistered event was rewritten")
+	}
+}
+
+func TestCommandRouterUpcastsBeforeRebuild(t *testing.T) {
+	// The rebuild function asserts it never sees the old shape.
+	var sawTypes []string
+	router := NewCommandRouter("customer").
+		WithUpcaster(NewUpcaster().
+			On("Int32Value", func(event *anypb.Any) (*anypb.Any, error) {
+				return anypb.New(wrapperspb.String("upcast"))
+			})).
+		WithRebuild(func(events *pb.EventBook) any {
+			for _, page := range events.GetPages() {
+				sawTypes = append(sawTypes, typeNameOf(page.GetEvent().GetTypeUrl()))
+			}
+			return nil
+		}).
+		On("StringValue", echoHandler)
+
+	prior := triggerBook(t, "customer", testRoot().GetValue(), 0, wrapperspb.Int32(41))
+	if _, err := router.Handle(context.Background(), commandFor(t, "customer", wrapperspb.String("update"), prior)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(sawTypes) != 1 || sawTypes[0] != "StringValue" {
+		t.Errorf("rebuild saw %v, want the upcast [StringValue]", sawTypes)
+	}
+}
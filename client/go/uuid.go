@@ -0,0 +1,63 @@
+package angzarr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+// BytesToUUIDText formats raw root identifier bytes as canonical dashed
+// UUID text when they are 16 bytes long, falling back to plain hex for
+// other lengths so malformed roots still log usefully.
+func BytesToUUIDText(b []byte) string {
+	if len(b) != 16 {
+		return hex.EncodeToString(b)
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// RootIDText formats a cover's root identifier for logs and error messages.
+func RootIDText(cover *pb.Cover) string {
+	return BytesToUUIDText(cover.GetRoot().GetValue())
+}
+
+// ParseUUID parses canonical UUID text — dashed (8-4-4-4-12) or bare
+// 32-character hex — into a validated 16-byte UUID.  It returns a
+// descriptive error for any other shape, replacing the ad-hoc
+// hex.DecodeString-and-continue pattern that silently dropped malformed
+// roots in the sagas.
+func ParseUUID(text string) (*pb.UUID, error) {
+	compact := text
+	if strings.Count(text, "-") == 4 {
+		parts := strings.Split(text, "-")
+		if len(parts[0]) != 8 || len(parts[1]) != 4 || len(parts[2]) != 4 ||
+			len(parts[3]) != 4 || len(parts[4]) != 12 {
+			return nil, fmt.Errorf("uuid %q: dashed form must be 8-4-4-4-12 hex characters", text)
+		}
+		compact = strings.Join(parts, "")
+	} else if strings.Contains(text, "-") {
+		return nil, fmt.Errorf("uuid %q: expected 4 dashes or none, got %d", text, strings.Count(text, "-"))
+	}
+	if len(compact) != 32 {
+		return nil, fmt.Errorf("uuid %q: expected 32 hex characters, got %d", text, len(compact))
+	}
+	value, err := hex.DecodeString(compact)
+	if err != nil {
+		return nil, fmt.Errorf("uuid %q: %v", text, err)
+	}
+	return &pb.UUID{Value: value}, nil
+}
+
+// UUIDBytes validates u and returns its raw 16 bytes, with a descriptive
+// error for nil or wrongly sized identifiers.
+func UUIDBytes(u *pb.UUID) ([]byte, error) {
+	if u == nil || u.Value == nil {
+		return nil, fmt.Errorf("uuid is nil")
+	}
+	if len(u.Value) != 16 {
+		return nil, fmt.Errorf("uuid has %d bytes, expected 16", len(u.Value))
+	}
+	return u.Value, nil
+}
@@ -0,0 +1,84 @@
+package angzarr
+
+import (
+	"bytes"
+	"testing"
+
+	pb "github.com/benjaminabbitt/angzarr/client/go/proto/angzarr"
+)
+
+func TestParseUUIDRoundTrip(t *testing.T) {
+	raw := []byte("0123456789abcdef")
+	text := BytesToUUIDText(raw)
+	parsed, err := ParseUUID(text)
+	if err != nil {
+		t.Fatalf("parse %q: %v", text, err)
+	}
+	got, err := UUIDBytes(parsed)
+	if err != nil {
+		t.Fatalf("bytes: %v", err)
+	}
+	if !bytes.Equal(got, raw) {
+		t.Errorf("round-trip mismatch: got %x, want %x", got, raw)
+	}
+}
+
+func TestParseUUIDBareHex(t *testing.T) {
+	parsed, err := ParseUUID("30313233343536373839616263646566")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if string(parsed.GetValue()) != "0123456789abcdef" {
+		t.Errorf("parsed value = %x", parsed.GetValue())
+	}
+}
+
+func TestParseUUIDRejectsMalformed(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"xyz",
+		"30313233343536373839-6263646566",      // wrong dash count
+		"3031-3233-3435-3637-3839616263646566", // wrong group widths
+		"303132333435363738396162636465",       // 30 chars
+		"3031323334353637383961626364656667",   // 34 chars
+		"3031323334353637383961626364656g",     // non-hex
+		"30313233-3435-3637-3839-61626364656extra-x", // junk
+	} {
+		if _, err := ParseUUID(text); err == nil {
+			t.Errorf("ParseUUID(%q) accepted malformed input", text)
+		}
+	}
+}
+
+func TestUUIDBytesValidation(t *testing.T) {
+	if _, err := UUIDBytes(nil); err == nil {
+		t.Error("nil uuid accepted")
+	}
+	if _, err := UUIDBytes(&pb.UUID{Value: []byte("short")}); err == nil {
+		t.Error("short uuid accepted")
+	}
+}
+
+func FuzzParseUUID(f *testing.F) {
+	f.Add("30313233-3435-3637-3839-616263646566")
+	f.Add("30313233343536373839616263646566")
+	f.Add("")
+	f.Add("----")
+	f.Fuzz(func(t *testing.T, text string) {
+		parsed, err := ParseUUID(text)
+		if err != nil {
+			return
+		}
+		if len(parsed.GetValue()) != 16 {
+			t.Fatalf("accepted uuid with %d bytes from %q", len(parsed.GetValue()), text)
+		}
+		// Accepted input must survive a format/parse round trip.
+		again, err := ParseUUID(BytesToUUIDText(parsed.GetValue()))
+		if err != nil {
+			t.Fatalf("formatted uuid failed to re-parse: %v", err)
+		}
+		if !bytes.Equal(again.GetValue(), parsed.GetValue()) {
+			t.Fatal("round trip changed uuid bytes")
+		}
+	})
+}